@@ -9,18 +9,23 @@ type KeyMap struct {
 	Settings  key.Binding
 	Search    key.Binding
 	Templates key.Binding
+	Trash     key.Binding
+	Archive   key.Binding
+	Import    key.Binding
+	Undo      key.Binding
+	Redo      key.Binding
 	Help      key.Binding
 }
 
 // ShortHelp returns key bindings for the short help view.
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Quit, k.Tab, k.Settings, k.Search, k.Templates, k.Help}
+	return []key.Binding{k.Quit, k.Tab, k.Settings, k.Search, k.Templates, k.Trash, k.Archive, k.Import, k.Help}
 }
 
 // FullHelp returns key bindings for the full help view.
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Quit, k.Tab, k.Settings, k.Search, k.Templates, k.Help},
+		{k.Quit, k.Tab, k.Settings, k.Search, k.Templates, k.Trash, k.Archive, k.Import, k.Help},
 	}
 }
 
@@ -47,6 +52,26 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("t"),
 			key.WithHelp("t", "templates"),
 		),
+		Trash: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "trash"),
+		),
+		Archive: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "archive"),
+		),
+		Import: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "import"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo"),
+		),
+		Redo: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("C-r", "redo"),
+		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
@@ -2,21 +2,29 @@ package app
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
+	"github.com/antti/todo-calendar/internal/archive"
 	"github.com/antti/todo-calendar/internal/calendar"
+	"github.com/antti/todo-calendar/internal/checklist"
 	"github.com/antti/todo-calendar/internal/config"
 	"github.com/antti/todo-calendar/internal/editor"
 	"github.com/antti/todo-calendar/internal/google"
 	"github.com/antti/todo-calendar/internal/holidays"
+	"github.com/antti/todo-calendar/internal/importer"
 	"github.com/antti/todo-calendar/internal/preview"
+	"github.com/antti/todo-calendar/internal/recurring"
+	"github.com/antti/todo-calendar/internal/remind"
 	"github.com/antti/todo-calendar/internal/search"
 	"github.com/antti/todo-calendar/internal/settings"
 	"github.com/antti/todo-calendar/internal/store"
 	"github.com/antti/todo-calendar/internal/theme"
 	"github.com/antti/todo-calendar/internal/tmplmgr"
+	"github.com/antti/todo-calendar/internal/trash"
 	"github.com/antti/todo-calendar/internal/todolist"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -73,11 +81,19 @@ type Model struct {
 	preview       preview.Model
 	showTmplMgr   bool
 	tmplMgr       tmplmgr.Model
+	showTrash     bool
+	trash         trash.Model
+	showArchive   bool
+	archive       archive.Model
+	showImporter  bool
+	importer      importer.Model
 	editing         bool
 	editingTmplID   int
 	editorErr       string
+	status          string // transient message, e.g. after an undo
 	store           store.TodoStore
 	cfg             config.Config
+	provider        *holidays.Provider // holidays of the configured country
 	googleAuthState google.AuthState
 	calendarSvc     *gcal.Service
 	calendarEvents  []google.CalendarEvent
@@ -95,6 +111,8 @@ func New(provider *holidays.Provider, mondayStart bool, s store.TodoStore, t the
 	tl.SetDateFormat(cfg.DateFormat, cfg.DateLayout(), cfg.DatePlaceholder())
 	tl.SetShowFuzzySections(cfg.ShowMonthTodos, cfg.ShowYearTodos)
 	tl.SetPriorityStyle(cfg.PriorityStyle)
+	tl.SetHolidayProvider(provider)
+	tl.SetSkipHolidays(cfg.PostponeSkipHolidays)
 	tl.SetViewMonth(cal.Year(), cal.Month())
 
 	h := help.New()
@@ -114,6 +132,7 @@ func New(provider *holidays.Provider, mondayStart bool, s store.TodoStore, t the
 		styles:          NewStyles(t),
 		store:           s,
 		cfg:             cfg,
+		provider:        provider,
 		googleAuthState: authState,
 		calendarSvc:     calSvc,
 	}
@@ -121,10 +140,13 @@ func New(provider *holidays.Provider, mondayStart bool, s store.TodoStore, t the
 
 // Init returns the initial command for the root model.
 func (m Model) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	if m.cfg.RemindersEnabled {
+		cmds = append(cmds, m.checkReminders(), remind.ScheduleTick())
+	}
 	if m.googleAuthState == google.AuthNotConfigured {
-		return nil
+		return tea.Batch(cmds...)
 	}
-	var cmds []tea.Cmd
 	if m.calendarSvc != nil {
 		cmds = append(cmds, google.FetchEventsCmd(m.calendarSvc, ""))
 	}
@@ -132,6 +154,18 @@ func (m Model) Init() tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// checkReminders returns a command that delivers due reminders using the
+// configured notifier. The stdout notifier is discarded because stdout
+// belongs to the TUI; delivery errors are ignored here and reported by the
+// "remind" command instead.
+func (m Model) checkReminders() tea.Cmd {
+	n, err := remind.New(m.cfg.ReminderNotifier, m.cfg.ReminderCommand, io.Discard)
+	if err != nil {
+		return nil
+	}
+	return remind.CheckCmd(m.store, n, m.cfg.ReminderTime)
+}
+
 // Update handles messages for the root model.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle settings-specific messages regardless of showSettings state,
@@ -146,12 +180,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Cfg.Country != oldCountry {
 			if p, err := holidays.NewProvider(msg.Cfg.Country); err == nil {
 				m.calendar.SetProvider(p)
+				m.todoList.SetHolidayProvider(p)
+				m.provider = p
 			}
 		}
 		m.calendar.SetMondayStart(msg.Cfg.MondayStart())
 		m.todoList.SetDateFormat(m.cfg.DateFormat, m.cfg.DateLayout(), m.cfg.DatePlaceholder())
 		m.todoList.SetShowFuzzySections(msg.Cfg.ShowMonthTodos, msg.Cfg.ShowYearTodos)
 		m.todoList.SetPriorityStyle(msg.Cfg.PriorityStyle)
+		m.todoList.SetSkipHolidays(msg.Cfg.PostponeSkipHolidays)
 		m.calendar.SetShowFuzzySections(msg.Cfg.ShowMonthTodos, msg.Cfg.ShowYearTodos)
 		if m.cfg.GoogleCalendarEnabled {
 			m.todoList.SetCalendarEvents(m.calendarEvents)
@@ -191,6 +228,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, google.ScheduleEventTick()
 
+	case remind.TickMsg:
+		return m, tea.Batch(m.checkReminders(), remind.ScheduleTick())
+
+	case remind.CheckedMsg:
+		if msg.Fired > 0 {
+			m.calendar.RefreshIndicators()
+		}
+		return m, nil
+
 	case google.EventTickMsg:
 		if m.calendarSvc == nil || m.googleAuthState != google.AuthReady {
 			return m, google.ScheduleEventTick()
@@ -232,10 +278,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.showSearch = false
 		return m, nil
 
+	case trash.CloseMsg:
+		m.showTrash = false
+		m.calendar.RefreshIndicators()
+		return m, nil
+
+	case archive.CloseMsg:
+		m.showArchive = false
+		m.calendar.RefreshIndicators()
+		return m, nil
+
+	case importer.CloseMsg:
+		m.showImporter = false
+		m.calendar.RefreshIndicators()
+		return m, nil
+
 	case preview.CloseMsg:
 		m.showPreview = false
 		return m, nil
 
+	case preview.ChecklistToggledMsg:
+		m.store.Batch("check subtask", func() {
+			m.store.UpdateBody(msg.TodoID, msg.Body)
+			m.completeIfChecklistDone(msg.TodoID)
+		})
+		m.calendar.RefreshIndicators()
+		return m, nil
+
 	case tmplmgr.CloseMsg:
 		m.showTmplMgr = false
 		return m, nil
@@ -247,13 +316,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, editorOpenTemplateContent(tpl.Content)
 
 	case tmplmgr.TemplateUpdatedMsg:
+		// A new or changed schedule creates its todos right away rather
+		// than on the next launch.
+		var isHoliday func(time.Time) bool
+		if m.provider != nil {
+			isHoliday = m.provider.IsHoliday
+		}
+		recurring.AutoCreate(m.store, isHoliday)
+		m.calendar.RefreshIndicators()
 		return m, nil
 
 	case todolist.PreviewMsg:
-		m.preview = preview.New(msg.Todo.Text, msg.Todo.Body, m.cfg.Theme, theme.ForName(m.cfg.Theme), m.width, m.height)
+		m.preview = preview.New(msg.Todo.ID, msg.Todo.Text, msg.Todo.Body, m.cfg.Theme, theme.ForName(m.cfg.Theme), m.width, m.height)
+		if msg.Todo.SeriesID != 0 {
+			m.preview.SetHistory(m.store.SeriesTodos(msg.Todo.SeriesID))
+		}
 		m.showPreview = true
 		return m, nil
 
+	case todolist.UndoneMsg:
+		m.status = undoStatus(msg)
+		m.calendar.RefreshIndicators()
+		return m, nil
+
 	case todolist.OpenEditorMsg:
 		m.editing = true
 		todo := msg.Todo
@@ -291,7 +376,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		if changed {
-			m.store.UpdateBody(msg.TodoID, newBody)
+			m.store.Batch("edit body", func() {
+				m.store.UpdateBody(msg.TodoID, newBody)
+				m.completeIfChecklistDone(msg.TodoID)
+			})
 		}
 		m.calendar.RefreshIndicators()
 		return m, nil
@@ -317,9 +405,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateSearch(msg)
 	}
 
+	// When trash overlay is open, route most messages there.
+	if m.showTrash {
+		return m.updateTrash(msg)
+	}
+
+	// When archive overlay is open, route most messages there.
+	if m.showArchive {
+		return m.updateArchive(msg)
+	}
+
+	// When import overlay is open, route most messages there.
+	if m.showImporter {
+		return m.updateImporter(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		m.editorErr = ""
+		m.status = ""
 		// In input mode, only ctrl+c quits (let 'q' go to textinput)
 		isInputting := m.activePane == todoPane && m.todoList.IsInputting()
 
@@ -359,9 +463,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.search.Init()
 		case key.Matches(msg, m.keys.Templates) && !isInputting:
 			m.tmplMgr = tmplmgr.New(m.store, theme.ForName(m.cfg.Theme))
+			m.tmplMgr.SetHolidayProvider(m.provider)
 			m.tmplMgr.SetSize(m.width, m.height)
 			m.showTmplMgr = true
 			return m, nil
+		case key.Matches(msg, m.keys.Trash) && !isInputting:
+			m.trash = trash.New(m.store, theme.ForName(m.cfg.Theme), m.cfg)
+			m.trash.SetSize(m.width, m.height)
+			m.showTrash = true
+			return m, nil
+		case key.Matches(msg, m.keys.Archive) && !isInputting:
+			m.archive = archive.New(m.store, theme.ForName(m.cfg.Theme), m.cfg)
+			m.archive.SetSize(m.width, m.height)
+			m.showArchive = true
+			return m, nil
+		case key.Matches(msg, m.keys.Import) && !isInputting:
+			m.importer = importer.New(m.store, theme.ForName(m.cfg.Theme))
+			m.importer.SetSize(m.width, m.height)
+			m.showImporter = true
+			return m, m.importer.Init()
+		case key.Matches(msg, m.keys.Undo) && m.activePane == calendarPane:
+			return m, m.todoList.Undo()
+		case key.Matches(msg, m.keys.Redo) && m.activePane == calendarPane:
+			return m, m.todoList.Redo()
 		case key.Matches(msg, m.keys.Help) && !isInputting:
 			m.help.ShowAll = !m.help.ShowAll
 			return m, nil
@@ -398,7 +522,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	// Refresh calendar indicators after every update cycle so that
-	// todo mutations (add/toggle/delete) are reflected immediately.
+	// todo mutations (add/toggle/delete) and the todo list's tag and
+	// project filters are reflected immediately.
+	m.calendar.SetTagFilter(m.todoList.TagFilter())
+	m.calendar.SetProjectFilter(m.todoList.ProjectFilter())
+	m.calendar.SetSavedSearch(m.todoList.SavedSearch())
 	m.calendar.RefreshIndicators()
 
 	return m, cmd
@@ -446,6 +574,92 @@ func (m Model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateTrash routes messages to the trash model when the overlay is open.
+func (m Model) updateTrash(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Also propagate window resize to all children so the app resizes correctly.
+	if wsm, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = wsm.Width
+		m.height = wsm.Height
+		m.ready = true
+		m.help.Width = wsm.Width
+		m.trash.SetSize(wsm.Width, wsm.Height)
+
+		var calCmd tea.Cmd
+		m.calendar, calCmd = m.calendar.Update(msg)
+		m.syncTodoSize()
+		return m, calCmd
+	}
+
+	var cmd tea.Cmd
+	m.trash, cmd = m.trash.Update(msg)
+	return m, cmd
+}
+
+// updateArchive routes messages to the archive model when the overlay is open.
+func (m Model) updateArchive(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Also propagate window resize to all children so the app resizes correctly.
+	if wsm, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = wsm.Width
+		m.height = wsm.Height
+		m.ready = true
+		m.help.Width = wsm.Width
+		m.archive.SetSize(wsm.Width, wsm.Height)
+
+		var calCmd tea.Cmd
+		m.calendar, calCmd = m.calendar.Update(msg)
+		m.syncTodoSize()
+		return m, calCmd
+	}
+
+	var cmd tea.Cmd
+	m.archive, cmd = m.archive.Update(msg)
+	return m, cmd
+}
+
+// updateImporter routes messages to the import model when the overlay is open.
+func (m Model) updateImporter(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Also propagate window resize to all children so the app resizes correctly.
+	if wsm, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = wsm.Width
+		m.height = wsm.Height
+		m.ready = true
+		m.help.Width = wsm.Width
+		m.importer.SetSize(wsm.Width, wsm.Height)
+
+		var calCmd tea.Cmd
+		m.calendar, calCmd = m.calendar.Update(msg)
+		m.syncTodoSize()
+		return m, calCmd
+	}
+
+	var cmd tea.Cmd
+	m.importer, cmd = m.importer.Update(msg)
+	return m, cmd
+}
+
+// undoStatus describes the result of an undo or redo for the status line.
+func undoStatus(msg todolist.UndoneMsg) string {
+	switch {
+	case msg.Label == "" && msg.Redo:
+		return "Nothing to redo"
+	case msg.Label == "":
+		return "Nothing to undo"
+	case msg.Redo:
+		return "Redid " + msg.Label
+	default:
+		return "Undid " + msg.Label
+	}
+}
+
+// completeIfChecklistDone marks a todo completed once every subtask in its
+// body is checked.
+func (m Model) completeIfChecklistDone(id int) {
+	t := m.store.Find(id)
+	if t != nil && !t.Done && checklist.AllDone(t.Body) {
+		recurring.Toggle(m.store, id, time.Now())
+	}
+}
+
 // updatePreview routes messages to the preview model when the overlay is open.
 func (m Model) updatePreview(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Also propagate window resize to all children so the app resizes correctly.
@@ -596,6 +810,9 @@ func (m *Model) applyTheme(t theme.Theme) {
 	m.search.SetTheme(t)
 	m.preview.SetTheme(t)
 	m.tmplMgr.SetTheme(t)
+	m.trash.SetTheme(t)
+	m.archive.SetTheme(t)
+	m.importer.SetTheme(t)
 	m.help.Styles.ShortKey = lipgloss.NewStyle().Foreground(t.AccentFg)
 	m.help.Styles.ShortDesc = lipgloss.NewStyle().Foreground(t.MutedFg)
 	m.help.Styles.ShortSeparator = lipgloss.NewStyle().Foreground(t.MutedFg)
@@ -616,6 +833,15 @@ func (m Model) currentHelpKeys() helpKeyMap {
 	if m.showSearch {
 		return helpKeyMap{bindings: m.search.HelpBindings()}
 	}
+	if m.showTrash {
+		return helpKeyMap{bindings: m.trash.HelpBindings()}
+	}
+	if m.showArchive {
+		return helpKeyMap{bindings: m.archive.HelpBindings()}
+	}
+	if m.showImporter {
+		return helpKeyMap{bindings: m.importer.HelpBindings()}
+	}
 	if m.showSettings {
 		return helpKeyMap{bindings: m.settings.HelpBindings()}
 	}
@@ -626,6 +852,9 @@ func (m Model) currentHelpKeys() helpKeyMap {
 	case calendarPane:
 		calKeys := m.calendar.Keys()
 		bindings = append(bindings, calKeys.PrevMonth, calKeys.NextMonth, calKeys.ToggleWeek)
+		if m.help.ShowAll {
+			bindings = append(bindings, m.keys.Undo, m.keys.Redo)
+		}
 	case todoPane:
 		if m.help.ShowAll {
 			bindings = append(bindings, m.todoList.AllHelpBindings()...)
@@ -635,7 +864,7 @@ func (m Model) currentHelpKeys() helpKeyMap {
 	}
 
 	if m.help.ShowAll {
-		bindings = append(bindings, m.keys.Tab, m.keys.Settings, m.keys.Search, m.keys.Templates, m.keys.Quit)
+		bindings = append(bindings, m.keys.Tab, m.keys.Settings, m.keys.Search, m.keys.Templates, m.keys.Trash, m.keys.Archive, m.keys.Import, m.keys.Quit)
 	}
 	// Show ? in help bar except during input modes (HELP-02)
 	if !m.todoList.IsInputting() || m.activePane == calendarPane {
@@ -681,6 +910,24 @@ func (m Model) View() string {
 		return lipgloss.JoinVertical(lipgloss.Left, m.search.View(), helpBar)
 	}
 
+	if m.showTrash {
+		m.help.Width = m.width
+		helpBar := m.help.View(m.currentHelpKeys())
+		return lipgloss.JoinVertical(lipgloss.Left, m.trash.View(), helpBar)
+	}
+
+	if m.showArchive {
+		m.help.Width = m.width
+		helpBar := m.help.View(m.currentHelpKeys())
+		return lipgloss.JoinVertical(lipgloss.Left, m.archive.View(), helpBar)
+	}
+
+	if m.showImporter {
+		m.help.Width = m.width
+		helpBar := m.help.View(m.currentHelpKeys())
+		return lipgloss.JoinVertical(lipgloss.Left, m.importer.View(), helpBar)
+	}
+
 	// Calculate help bar first so we can measure its height
 	m.help.Width = m.width
 	helpBar := m.help.View(m.currentHelpKeys())
@@ -723,6 +970,9 @@ func (m Model) View() string {
 		errLine := m.styles.Error.Render(m.editorErr)
 		return lipgloss.JoinVertical(lipgloss.Left, top, errLine, helpBar)
 	}
+	if m.status != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, top, m.styles.Status.Render(m.status), helpBar)
+	}
 	if m.eventsFetchErr != nil && m.cfg.GoogleCalendarEnabled {
 		errMsg := m.eventsFetchErr.Error()
 		if len(errMsg) > 80 {
@@ -10,6 +10,7 @@ type Styles struct {
 	Focused   lipgloss.Style
 	Unfocused lipgloss.Style
 	Error     lipgloss.Style
+	Status    lipgloss.Style
 }
 
 // NewStyles builds app styles from the given theme.
@@ -23,7 +24,8 @@ func NewStyles(t theme.Theme) Styles {
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(t.BorderUnfocused).
 			Padding(0, 1),
-		Error: lipgloss.NewStyle().Foreground(t.HolidayFg),
+		Error:  lipgloss.NewStyle().Foreground(t.HolidayFg),
+		Status: lipgloss.NewStyle().Foreground(t.MutedFg),
 	}
 }
 
@@ -0,0 +1,58 @@
+package archive
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines key bindings for the archive overlay.
+type KeyMap struct {
+	Up         key.Binding
+	Down       key.Binding
+	PrevPeriod key.Binding
+	NextPeriod key.Binding
+	ToggleSpan key.Binding
+	Unarchive  key.Binding
+	Cancel     key.Binding
+}
+
+// ShortHelp returns key bindings for the short help view.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.PrevPeriod, k.NextPeriod, k.ToggleSpan, k.Unarchive, k.Cancel}
+}
+
+// FullHelp returns key bindings for the full help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// DefaultKeyMap returns the default archive key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("k", "up"),
+			key.WithHelp("k/up", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("j", "down"),
+			key.WithHelp("j/dn", "down"),
+		),
+		PrevPeriod: key.NewBinding(
+			key.WithKeys("h", "left"),
+			key.WithHelp("h", "previous"),
+		),
+		NextPeriod: key.NewBinding(
+			key.WithKeys("l", "right"),
+			key.WithHelp("l", "next"),
+		),
+		ToggleSpan: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "week/month"),
+		),
+		Unarchive: key.NewBinding(
+			key.WithKeys("r", "enter"),
+			key.WithHelp("r", "unarchive"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
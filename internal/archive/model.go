@@ -0,0 +1,261 @@
+// Package archive implements the overlay for reviewing archived todos by
+// the week or month they were completed in.
+package archive
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/antti/todo-calendar/internal/config"
+	"github.com/antti/todo-calendar/internal/store"
+	"github.com/antti/todo-calendar/internal/theme"
+)
+
+// CloseMsg is emitted when the user presses Esc to close the archive overlay.
+type CloseMsg struct{}
+
+// Model represents the archive overlay.
+type Model struct {
+	store       store.TodoStore
+	todos       []store.Todo
+	cursor      int
+	weekly      bool      // browse by week instead of by month
+	start       time.Time // first day of the shown period
+	mondayStart bool
+	dateLayout  string
+	afterDays   int
+	width       int
+	height      int
+	keys        KeyMap
+	styles      Styles
+}
+
+// New creates a new archive overlay model showing the current month.
+func New(s store.TodoStore, t theme.Theme, cfg config.Config) Model {
+	now := time.Now()
+	m := Model{
+		store:       s,
+		start:       time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local),
+		mondayStart: cfg.MondayStart(),
+		dateLayout:  cfg.DateLayout(),
+		afterDays:   cfg.ArchiveAfterDays,
+		keys:        DefaultKeyMap(),
+		styles:      NewStyles(t),
+	}
+	m.refresh()
+	return m
+}
+
+// SetSize stores dimensions for layout.
+func (m *Model) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// SetTheme replaces the styles with ones built from the given theme.
+func (m *Model) SetTheme(t theme.Theme) {
+	m.styles = NewStyles(t)
+}
+
+// HelpBindings returns overlay-specific key bindings for help bar display.
+func (m Model) HelpBindings() []key.Binding {
+	return m.keys.ShortHelp()
+}
+
+// end returns the last day of the shown period.
+func (m Model) end() time.Time {
+	if m.weekly {
+		return m.start.AddDate(0, 0, 6)
+	}
+	return m.start.AddDate(0, 1, -1)
+}
+
+// weekStart returns the first day of the week containing t.
+func (m Model) weekStart(t time.Time) time.Time {
+	offset := int(t.Weekday())
+	if m.mondayStart {
+		offset = (offset + 6) % 7
+	}
+	return t.AddDate(0, 0, -offset)
+}
+
+// refresh reloads the archived todos of the shown period and clamps the cursor.
+func (m *Model) refresh() {
+	m.todos = m.store.ArchivedTodos(m.start.Format("2006-01-02"), m.end().Format("2006-01-02"))
+	if m.cursor >= len(m.todos) {
+		m.cursor = len(m.todos) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// shift moves the shown period by n weeks or months.
+func (m *Model) shift(n int) {
+	if m.weekly {
+		m.start = m.start.AddDate(0, 0, 7*n)
+	} else {
+		m.start = m.start.AddDate(0, n, 0)
+	}
+	m.cursor = 0
+	m.refresh()
+}
+
+// toggleSpan switches between browsing by month and by week. Going to
+// weeks shows the first week of the month, or the current week when
+// browsing the current month; going to months shows the week's month.
+func (m *Model) toggleSpan() {
+	m.weekly = !m.weekly
+	if m.weekly {
+		day := m.start
+		if now := time.Now(); now.Year() == day.Year() && now.Month() == day.Month() {
+			day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		}
+		m.start = m.weekStart(day)
+	} else {
+		m.start = time.Date(m.start.Year(), m.start.Month(), 1, 0, 0, 0, 0, time.Local)
+	}
+	m.cursor = 0
+	m.refresh()
+}
+
+// Update handles messages for the archive overlay.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Cancel):
+			return m, func() tea.Msg { return CloseMsg{} }
+
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.todos)-1 {
+				m.cursor++
+			}
+
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case key.Matches(msg, m.keys.PrevPeriod):
+			m.shift(-1)
+
+		case key.Matches(msg, m.keys.NextPeriod):
+			m.shift(1)
+
+		case key.Matches(msg, m.keys.ToggleSpan):
+			m.toggleSpan()
+
+		case key.Matches(msg, m.keys.Unarchive):
+			if m.cursor < len(m.todos) {
+				m.store.Unarchive(m.todos[m.cursor].ID)
+				m.refresh()
+			}
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+// periodTitle describes the shown period, e.g. "March 2026" or
+// "Week of 2026-03-09".
+func (m Model) periodTitle() string {
+	if m.weekly {
+		return "Week of " + config.FormatDate(m.start.Format("2006-01-02"), m.dateLayout)
+	}
+	return m.start.Format("January 2006")
+}
+
+// View renders the archive overlay.
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render("Archive"))
+	b.WriteString("  ")
+	b.WriteString(m.styles.Period.Render("< " + m.periodTitle() + " >"))
+	b.WriteString("\n\n")
+
+	if len(m.todos) == 0 {
+		b.WriteString(m.styles.Empty.Render("(nothing archived in this period)"))
+		b.WriteString("\n\n")
+		b.WriteString(m.hint())
+		return m.verticalCenter(b.String())
+	}
+
+	// Budget lines for todos plus a header per completion day.
+	maxVisible := m.height - 10
+	if maxVisible < 1 {
+		maxVisible = 1
+	}
+	start := 0
+	if m.cursor >= maxVisible/2 {
+		start = m.cursor - maxVisible/2 + 1
+	}
+	lines := 0
+	prevDay := ""
+	for i := start; i < len(m.todos) && lines < maxVisible; i++ {
+		t := m.todos[i]
+		day := t.CompletedAt
+		if len(day) >= 10 {
+			day = day[:10]
+		}
+		if day != prevDay {
+			weekday := ""
+			if d, err := time.Parse("2006-01-02", day); err == nil {
+				weekday = d.Format("Mon") + " "
+			}
+			b.WriteString(m.styles.DayHeader.Render(weekday + config.FormatDate(day, m.dateLayout)))
+			b.WriteString("\n")
+			prevDay = day
+			lines++
+		}
+		info := ""
+		if t.HasDate() {
+			info = "due " + config.FormatDate(t.Date, m.dateLayout)
+		}
+		if i == m.cursor {
+			b.WriteString(m.styles.SelectedItem.Render("> [x] " + t.Text))
+			b.WriteString("  ")
+			b.WriteString(m.styles.SelectedDate.Render(info))
+		} else {
+			b.WriteString(m.styles.ItemText.Render("  [x] " + t.Text))
+			b.WriteString("  ")
+			b.WriteString(m.styles.ItemDate.Render(info))
+		}
+		b.WriteString("\n")
+		lines++
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.Hint.Render(fmt.Sprintf("%d completed", len(m.todos))))
+	b.WriteString("\n")
+	b.WriteString(m.hint())
+
+	return m.verticalCenter(b.String())
+}
+
+// hint explains how todos get into the archive.
+func (m Model) hint() string {
+	if m.afterDays > 0 {
+		return m.styles.Hint.Render(fmt.Sprintf("Completed todos are archived %d days after completion, or with X in the todo list.", m.afterDays))
+	}
+	return m.styles.Hint.Render("Press X in the todo list to archive completed todos.")
+}
+
+// verticalCenter centers the content vertically within the available height.
+func (m Model) verticalCenter(content string) string {
+	if m.height > 0 {
+		lines := strings.Count(content, "\n") + 1
+		topPad := (m.height - lines) / 2
+		if topPad > 0 {
+			content = strings.Repeat("\n", topPad) + content
+		}
+	}
+	return content
+}
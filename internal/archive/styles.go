@@ -0,0 +1,34 @@
+package archive
+
+import (
+	"github.com/antti/todo-calendar/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles holds themed lipgloss styles for the archive overlay.
+type Styles struct {
+	Title        lipgloss.Style
+	Period       lipgloss.Style
+	DayHeader    lipgloss.Style
+	ItemText     lipgloss.Style
+	ItemDate     lipgloss.Style
+	SelectedItem lipgloss.Style
+	SelectedDate lipgloss.Style
+	Hint         lipgloss.Style
+	Empty        lipgloss.Style
+}
+
+// NewStyles builds archive styles from the given theme.
+func NewStyles(t theme.Theme) Styles {
+	return Styles{
+		Title:        lipgloss.NewStyle().Bold(true).Foreground(t.AccentFg),
+		Period:       lipgloss.NewStyle().Bold(true).Foreground(t.HeaderFg),
+		DayHeader:    lipgloss.NewStyle().Bold(true).Foreground(t.MutedFg),
+		ItemText:     lipgloss.NewStyle().Foreground(t.CompletedFg),
+		ItemDate:     lipgloss.NewStyle().Foreground(t.MutedFg),
+		SelectedItem: lipgloss.NewStyle().Bold(true).Foreground(t.AccentFg),
+		SelectedDate: lipgloss.NewStyle().Foreground(t.AccentFg),
+		Hint:         lipgloss.NewStyle().Foreground(t.MutedFg),
+		Empty:        lipgloss.NewStyle().Foreground(t.MutedFg),
+	}
+}
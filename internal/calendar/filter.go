@@ -0,0 +1,160 @@
+package calendar
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// filteredStore wraps a TodoStore so that the queries behind the calendar's
+// indicators and overview only see todos carrying every tag in tags and,
+// when projectID is non-zero, belonging to that project.
+// All other methods pass through to the wrapped store.
+type filteredStore struct {
+	store.TodoStore
+	tags      []string
+	projectID int
+}
+
+// keep returns the todos that pass the filter.
+func (f filteredStore) keep(todos []store.Todo) []store.Todo {
+	var result []store.Todo
+	for _, t := range todos {
+		if t.HasAllTags(f.tags) && (f.projectID == 0 || t.ProjectID == f.projectID) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// dayOf returns the day of month of a day-precision todo date.
+func dayOf(date string) int {
+	if len(date) < 10 {
+		return 0
+	}
+	d, _ := strconv.Atoi(date[8:10])
+	return d
+}
+
+// IncompleteTodosPerDay counts incomplete matching todos per day.
+func (f filteredStore) IncompleteTodosPerDay(year int, month time.Month) map[int]int {
+	counts := make(map[int]int)
+	for _, t := range f.keep(f.TodoStore.TodosForMonth(year, month)) {
+		if !t.Done {
+			counts[dayOf(t.Date)]++
+		}
+	}
+	return counts
+}
+
+// TotalTodosPerDay counts all matching todos per day.
+func (f filteredStore) TotalTodosPerDay(year int, month time.Month) map[int]int {
+	counts := make(map[int]int)
+	for _, t := range f.keep(f.TodoStore.TodosForMonth(year, month)) {
+		counts[dayOf(t.Date)]++
+	}
+	return counts
+}
+
+// HighestPriorityPerDay returns the highest priority among incomplete,
+// prioritized matching todos per day.
+func (f filteredStore) HighestPriorityPerDay(year int, month time.Month) map[int]int {
+	priorities := make(map[int]int)
+	for _, t := range f.keep(f.TodoStore.TodosForMonth(year, month)) {
+		if t.Done || t.Priority < 1 || t.Priority > 4 {
+			continue
+		}
+		day := dayOf(t.Date)
+		if p, ok := priorities[day]; !ok || t.Priority < p {
+			priorities[day] = t.Priority
+		}
+	}
+	return priorities
+}
+
+// MonthTodos returns matching month-precision todos.
+func (f filteredStore) MonthTodos(year int, month time.Month) []store.Todo {
+	return f.keep(f.TodoStore.MonthTodos(year, month))
+}
+
+// YearTodos returns matching year-precision todos.
+func (f filteredStore) YearTodos(year int) []store.Todo {
+	return f.keep(f.TodoStore.YearTodos(year))
+}
+
+// TodoCountsByMonth returns pending and completed counts per month across
+// matching day-precision todos, sorted chronologically.
+func (f filteredStore) TodoCountsByMonth() []store.MonthCount {
+	var result []store.MonthCount
+	index := make(map[string]int)
+	for _, t := range f.keep(f.TodoStore.Todos()) {
+		if !t.HasDate() || t.IsFuzzy() {
+			continue
+		}
+		d, err := time.Parse("2006-01-02", t.Date)
+		if err != nil {
+			continue
+		}
+		ym := t.Date[:7]
+		i, ok := index[ym]
+		if !ok {
+			i = len(result)
+			index[ym] = i
+			result = append(result, store.MonthCount{Year: d.Year(), Month: d.Month()})
+		}
+		if t.Done {
+			result[i].Completed++
+		} else {
+			result[i].Pending++
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Year != result[j].Year {
+			return result[i].Year < result[j].Year
+		}
+		return result[i].Month < result[j].Month
+	})
+	return result
+}
+
+// FloatingTodoCounts returns pending and completed counts for matching
+// undated todos.
+func (f filteredStore) FloatingTodoCounts() store.FloatingCount {
+	var fc store.FloatingCount
+	for _, t := range f.keep(f.TodoStore.FloatingTodos()) {
+		if t.Done {
+			fc.Completed++
+		} else {
+			fc.Pending++
+		}
+	}
+	return fc
+}
+
+// ProjectCounts returns per-project counts of todos carrying the filter
+// tags. The project filter is not applied so every project stays listed.
+func (f filteredStore) ProjectCounts() []store.ProjectCount {
+	counts := f.TodoStore.ProjectCounts()
+	if len(f.tags) == 0 {
+		return counts
+	}
+	index := make(map[int]int, len(counts))
+	for i := range counts {
+		counts[i].Pending, counts[i].Completed = 0, 0
+		index[counts[i].ProjectID] = i
+	}
+	for _, t := range f.TodoStore.Todos() {
+		i, ok := index[t.ProjectID]
+		if !ok || !t.HasAllTags(f.tags) {
+			continue
+		}
+		if t.Done {
+			counts[i].Completed++
+		} else {
+			counts[i].Pending++
+		}
+	}
+	return counts
+}
@@ -32,12 +32,13 @@ func fuzzyStatus(todos []store.Todo) string {
 //
 // Parameters:
 //   - year, month: the month to render
-//   - today: day number to highlight as today (0 for none)
+//   - today: today's date, highlighted if it is in the month; earlier days
+//     with incomplete todos are marked overdue
 //   - holidays: map of day numbers that are holidays
 //   - mondayStart: if true, weeks start on Monday; otherwise Sunday
 //   - indicators: map of day numbers to count of incomplete todos (nil safe)
 //   - st: store for querying month/year fuzzy todos (nil safe)
-func RenderGrid(year int, month time.Month, today int, holidays map[int]bool, mondayStart bool, indicators map[int]int, totals map[int]int, priorities map[int]int, st store.TodoStore, showMonthTodos bool, showYearTodos bool, contentWidth int, hasEvents map[int]bool, s Styles) string {
+func RenderGrid(year int, month time.Month, today time.Time, holidays map[int]bool, mondayStart bool, indicators map[int]int, totals map[int]int, priorities map[int]int, st store.TodoStore, showMonthTodos bool, showYearTodos bool, contentWidth int, hasEvents map[int]bool, s Styles) string {
 	var b strings.Builder
 
 	// Title line: month and year, centered in grid width.
@@ -142,9 +143,16 @@ func RenderGrid(year int, month time.Month, today int, holidays map[int]bool, mo
 	}
 
 	// Day cells.
+	todayDay := 0
+	if today.Year() == year && today.Month() == month {
+		todayDay = today.Day()
+	}
+	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	todayStart := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
 	for day := 1; day <= daysInMonth; day++ {
 		// Format cell to 4 visible characters BEFORE styling.
 		hasPending := indicators[day] > 0
+		isOverdue := hasPending && monthStart.AddDate(0, 0, day-1).Before(todayStart)
 		hasAllDone := !hasPending && totals[day] > 0
 		hasEvt := hasEvents[day]
 		var cell string
@@ -154,9 +162,9 @@ func RenderGrid(year int, month time.Month, today int, holidays map[int]bool, mo
 			cell = fmt.Sprintf(" %2d ", day)
 		}
 
-		// Apply style based on priority: today+indicator > today+done > today+event > today > holiday > indicator > done > event > normal.
+		// Apply style based on priority: today+indicator > today+done > today+event > today > overdue > holiday > indicator > done > event > normal.
 		switch {
-		case day == today && hasPending:
+		case day == todayDay && hasPending:
 			switch priorities[day] {
 			case 1:
 				cell = s.TodayIndicatorP1.Render(cell)
@@ -167,12 +175,14 @@ func RenderGrid(year int, month time.Month, today int, holidays map[int]bool, mo
 			default:
 				cell = s.TodayIndicator.Render(cell)
 			}
-		case day == today && hasAllDone:
+		case day == todayDay && hasAllDone:
 			cell = s.TodayDone.Render(cell)
-		case day == today && hasEvt:
+		case day == todayDay && hasEvt:
 			cell = s.TodayIndicator.Render(cell)
-		case day == today:
+		case day == todayDay:
 			cell = s.Today.Render(cell)
+		case isOverdue:
+			cell = s.Overdue.Render(cell)
 		case holidays[day]:
 			cell = s.Holiday.Render(cell)
 		case hasPending:
@@ -218,7 +228,8 @@ func RenderGrid(year int, month time.Month, today int, holidays map[int]bool, mo
 //
 // Parameters:
 //   - weekStart: the first day of the week to render
-//   - today: today's date for highlighting
+//   - today: today's date for highlighting; earlier days with incomplete
+//     todos are marked overdue
 //   - hp: holiday provider for holiday lookup
 //   - mondayStart: if true, weeks start on Monday; otherwise Sunday
 //   - st: store for incomplete todo indicator lookup
@@ -324,11 +335,13 @@ func RenderWeekGrid(weekStart time.Time, today time.Time, hp *holidays.Provider,
 		tots := getTotals(dy, dm)
 
 		isToday := d.Year() == today.Year() && d.Month() == today.Month() && d.Day() == today.Day()
+		isPast := !isToday && d.Before(today)
 
 		// Format cell to 4 visible characters.
 		hasPending := inds[dd] > 0
 		hasAllDone := !hasPending && tots[dd] > 0
 		hasEvt := hasEvents[dd]
+		isOverdue := hasPending && isPast
 		var cell string
 		if hasPending || hasAllDone || hasEvt {
 			cell = fmt.Sprintf("[%2d]", dd)
@@ -336,7 +349,7 @@ func RenderWeekGrid(weekStart time.Time, today time.Time, hp *holidays.Provider,
 			cell = fmt.Sprintf(" %2d ", dd)
 		}
 
-		// Apply style based on priority: today+indicator > today+done > today+event > today > holiday > indicator > done > event > normal.
+		// Apply style based on priority: today+indicator > today+done > today+event > today > overdue > holiday > indicator > done > event > normal.
 		prios := getPriorities(dy, dm)
 		switch {
 		case isToday && hasPending:
@@ -356,6 +369,8 @@ func RenderWeekGrid(weekStart time.Time, today time.Time, hp *holidays.Provider,
 			cell = s.TodayIndicator.Render(cell)
 		case isToday:
 			cell = s.Today.Render(cell)
+		case isOverdue:
+			cell = s.Overdue.Render(cell)
 		case hols[dd]:
 			cell = s.Holiday.Render(cell)
 		case hasPending:
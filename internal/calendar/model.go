@@ -57,6 +57,9 @@ type Model struct {
 	showYearTodos  bool
 	contentWidth   int // pane text content width (pane width minus padding)
 	calendarEvents []google.CalendarEvent
+	tagFilter      []string // only count todos carrying all of these tags
+	projectFilter  int      // only count todos in this project (0 = all)
+	savedSearch    int      // saved search shown in the todo pane (0 = none)
 }
 
 // New creates a new calendar model with the given holiday provider,
@@ -104,9 +107,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				// m.year and m.month already track the week's month
 			}
 			m.holidays = m.provider.HolidaysInMonth(m.year, m.month)
-			m.indicators = m.store.IncompleteTodosPerDay(m.year, m.month)
-			m.totals = m.store.TotalTodosPerDay(m.year, m.month)
-			m.priorities = m.store.HighestPriorityPerDay(m.year, m.month)
+			m.indicators = m.todoSource().IncompleteTodosPerDay(m.year, m.month)
+			m.totals = m.todoSource().TotalTodosPerDay(m.year, m.month)
+			m.priorities = m.todoSource().HighestPriorityPerDay(m.year, m.month)
 
 		case key.Matches(msg, m.keys.PrevMonth):
 			if m.viewMode == WeekView {
@@ -121,9 +124,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				}
 			}
 			m.holidays = m.provider.HolidaysInMonth(m.year, m.month)
-			m.indicators = m.store.IncompleteTodosPerDay(m.year, m.month)
-			m.totals = m.store.TotalTodosPerDay(m.year, m.month)
-			m.priorities = m.store.HighestPriorityPerDay(m.year, m.month)
+			m.indicators = m.todoSource().IncompleteTodosPerDay(m.year, m.month)
+			m.totals = m.todoSource().TotalTodosPerDay(m.year, m.month)
+			m.priorities = m.todoSource().HighestPriorityPerDay(m.year, m.month)
 
 		case key.Matches(msg, m.keys.NextMonth):
 			if m.viewMode == WeekView {
@@ -138,9 +141,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				}
 			}
 			m.holidays = m.provider.HolidaysInMonth(m.year, m.month)
-			m.indicators = m.store.IncompleteTodosPerDay(m.year, m.month)
-			m.totals = m.store.TotalTodosPerDay(m.year, m.month)
-			m.priorities = m.store.HighestPriorityPerDay(m.year, m.month)
+			m.indicators = m.todoSource().IncompleteTodosPerDay(m.year, m.month)
+			m.totals = m.todoSource().TotalTodosPerDay(m.year, m.month)
+			m.priorities = m.todoSource().HighestPriorityPerDay(m.year, m.month)
 		}
 
 	}
@@ -153,16 +156,10 @@ func (m Model) View() string {
 	hasEvents := m.hasEventsPerDay(m.year, m.month)
 	var content string
 	if m.viewMode == WeekView {
-		grid := RenderWeekGrid(m.weekStart, time.Now(), m.provider, m.mondayStart, m.store, hasEvents, m.styles)
+		grid := RenderWeekGrid(m.weekStart, time.Now(), m.provider, m.mondayStart, m.todoSource(), hasEvents, m.styles)
 		content = grid + m.renderOverview()
 	} else {
-		todayDay := 0
-		now := time.Now()
-		if now.Year() == m.year && now.Month() == m.month {
-			todayDay = now.Day()
-		}
-
-		grid := RenderGrid(m.year, m.month, todayDay, m.holidays, m.mondayStart, m.indicators, m.totals, m.priorities, m.store, m.showMonthTodos, m.showYearTodos, m.contentWidth, hasEvents, m.styles)
+		grid := RenderGrid(m.year, m.month, time.Now(), m.holidays, m.mondayStart, m.indicators, m.totals, m.priorities, m.todoSource(), m.showMonthTodos, m.showYearTodos, m.contentWidth, hasEvents, m.styles)
 		content = grid + m.renderOverview()
 	}
 
@@ -170,14 +167,17 @@ func (m Model) View() string {
 }
 
 // renderOverview builds the overview section showing per-month todo counts
-// (pending in red-family, completed in green-family) and the floating (undated)
-// todo counts. It is computed fresh from the store on every render to guarantee
-// live updates without cache invalidation.
+// (pending in red-family, completed in green-family), the floating (undated)
+// todo counts, per-project counts and the counts of the saved searches. It
+// is computed fresh from the store on every render to guarantee live
+// updates without cache invalidation.
 func (m Model) renderOverview() string {
-	months := m.store.TodoCountsByMonth()
-	fc := m.store.FloatingTodoCounts()
+	months := m.todoSource().TodoCountsByMonth()
+	fc := m.todoSource().FloatingTodoCounts()
+	projects := m.todoSource().ProjectCounts()
+	searches := m.store.SavedSearchCounts()
 
-	if len(months) == 0 && fc.Pending == 0 && fc.Completed == 0 {
+	if len(months) == 0 && fc.Pending == 0 && fc.Completed == 0 && len(projects) == 0 && len(searches) == 0 {
 		return ""
 	}
 
@@ -217,15 +217,86 @@ func (m Model) renderOverview() string {
 		b.WriteString("\n")
 	}
 
+	if len(projects) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.styles.OverviewHeader.Render("Projects"))
+		b.WriteString("\n")
+		for _, pc := range projects {
+			label := pc.Name
+			if r := []rune(label); len(r) > 15 {
+				label = string(r[:14]) + "…"
+			}
+			paddedLabel := fmt.Sprintf(" %-16s", label)
+			if pc.ProjectID == m.projectFilter {
+				b.WriteString(m.styles.OverviewActive.Render(paddedLabel))
+			} else {
+				b.WriteString(m.styles.OverviewCount.Render(paddedLabel))
+			}
+			b.WriteString(m.styles.OverviewPending.Render(fmt.Sprintf("%d", pc.Pending)))
+			b.WriteString("  ")
+			b.WriteString(m.styles.OverviewCompleted.Render(fmt.Sprintf("%d", pc.Completed)))
+			b.WriteString("\n")
+		}
+	}
+
+	if len(searches) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.styles.OverviewHeader.Render("Views"))
+		b.WriteString("\n")
+		for _, sc := range searches {
+			label := sc.Name
+			if r := []rune(label); len(r) > 15 {
+				label = string(r[:14]) + "…"
+			}
+			paddedLabel := fmt.Sprintf(" %-16s", label)
+			if sc.SearchID == m.savedSearch {
+				b.WriteString(m.styles.OverviewActive.Render(paddedLabel))
+			} else {
+				b.WriteString(m.styles.OverviewCount.Render(paddedLabel))
+			}
+			b.WriteString(m.styles.OverviewPending.Render(fmt.Sprintf("%d", sc.Pending)))
+			b.WriteString("  ")
+			b.WriteString(m.styles.OverviewCompleted.Render(fmt.Sprintf("%d", sc.Completed)))
+			b.WriteString("\n")
+		}
+	}
+
 	return b.String()
 }
 
 // RefreshIndicators recomputes the indicator data for the current month.
 // Call this after todo mutations to keep the calendar display in sync.
 func (m *Model) RefreshIndicators() {
-	m.indicators = m.store.IncompleteTodosPerDay(m.year, m.month)
-	m.totals = m.store.TotalTodosPerDay(m.year, m.month)
-	m.priorities = m.store.HighestPriorityPerDay(m.year, m.month)
+	m.indicators = m.todoSource().IncompleteTodosPerDay(m.year, m.month)
+	m.totals = m.todoSource().TotalTodosPerDay(m.year, m.month)
+	m.priorities = m.todoSource().HighestPriorityPerDay(m.year, m.month)
+}
+
+// SetTagFilter limits indicators and overview counts to todos carrying
+// all of the given tags. An empty list shows every todo.
+func (m *Model) SetTagFilter(tags []string) {
+	m.tagFilter = tags
+}
+
+// SetProjectFilter limits indicators and overview counts to todos in the
+// given project. 0 shows todos from all projects.
+func (m *Model) SetProjectFilter(projectID int) {
+	m.projectFilter = projectID
+}
+
+// SetSavedSearch highlights the saved search shown in the todo pane in the
+// overview. 0 highlights none.
+func (m *Model) SetSavedSearch(id int) {
+	m.savedSearch = id
+}
+
+// todoSource returns the store used for indicator and overview queries,
+// wrapped in the active tag and project filters if there are any.
+func (m Model) todoSource() store.TodoStore {
+	if len(m.tagFilter) == 0 && m.projectFilter == 0 {
+		return m.store
+	}
+	return filteredStore{TodoStore: m.store, tags: m.tagFilter, projectID: m.projectFilter}
 }
 
 // SetFocused sets whether this pane is focused.
@@ -314,7 +385,7 @@ func (m *Model) SetYearMonth(year int, month time.Month) {
 	m.year = year
 	m.month = month
 	m.holidays = m.provider.HolidaysInMonth(year, month)
-	m.indicators = m.store.IncompleteTodosPerDay(year, month)
-	m.totals = m.store.TotalTodosPerDay(year, month)
-	m.priorities = m.store.HighestPriorityPerDay(year, month)
+	m.indicators = m.todoSource().IncompleteTodosPerDay(year, month)
+	m.totals = m.todoSource().TotalTodosPerDay(year, month)
+	m.priorities = m.todoSource().HighestPriorityPerDay(year, month)
 }
@@ -14,6 +14,7 @@ type Styles struct {
 	Holiday        lipgloss.Style
 	Indicator      lipgloss.Style
 	IndicatorDone  lipgloss.Style
+	Overdue        lipgloss.Style // pending todos on a past day
 	TodayIndicator lipgloss.Style
 	TodayDone      lipgloss.Style
 	IndicatorP1      lipgloss.Style // P1 priority pending indicator
@@ -43,6 +44,7 @@ func NewStyles(t theme.Theme) Styles {
 		Holiday:        lipgloss.NewStyle().Foreground(t.HolidayFg),
 		Indicator:      lipgloss.NewStyle().Bold(true).Foreground(t.IndicatorFg),
 		IndicatorDone:  lipgloss.NewStyle().Foreground(t.CompletedCountFg),
+		Overdue:        lipgloss.NewStyle().Bold(true).Underline(true).Foreground(t.OverdueFg),
 		TodayIndicator: lipgloss.NewStyle().Bold(true).Foreground(t.IndicatorFg).Background(t.TodayBg),
 		TodayDone:      lipgloss.NewStyle().Bold(true).Foreground(t.CompletedCountFg).Background(t.TodayBg),
 		IndicatorP1:      lipgloss.NewStyle().Bold(true).Foreground(t.PriorityP1Fg),
@@ -0,0 +1,75 @@
+// Package checklist treats markdown task-list items ("- [ ] text") in a todo
+// body as subtasks: it finds them, reports progress and toggles them.
+package checklist
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Item is a single checklist entry found in a body.
+type Item struct {
+	Line int    // zero-based line index within the body
+	Text string // item text without the list marker and checkbox
+	Done bool
+}
+
+// itemRe matches "- [ ] text", "* [x] text" and "+ [X] text", optionally
+// indented. The checkbox state is captured separately so it can be replaced
+// in place.
+var itemRe = regexp.MustCompile(`^(\s*[-*+]\s+\[)([ xX])(\].*)$`)
+
+// Parse returns the checklist items in body in order. Items without text
+// (the empty "- [ ]" placeholders seeded by templates) are skipped.
+func Parse(body string) []Item {
+	var items []Item
+	for i, line := range strings.Split(body, "\n") {
+		m := itemRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		text := strings.TrimSpace(strings.TrimPrefix(m[3], "]"))
+		if text == "" {
+			continue
+		}
+		items = append(items, Item{Line: i, Text: text, Done: m[2] != " "})
+	}
+	return items
+}
+
+// Progress returns the number of completed items and the total number of
+// items in body.
+func Progress(body string) (done, total int) {
+	for _, it := range Parse(body) {
+		total++
+		if it.Done {
+			done++
+		}
+	}
+	return done, total
+}
+
+// AllDone reports whether body has at least one item and all are checked.
+func AllDone(body string) bool {
+	done, total := Progress(body)
+	return total > 0 && done == total
+}
+
+// Toggle flips the checkbox on the given line of body and returns the new
+// body. The body is returned unchanged when the line is not a checklist item.
+func Toggle(body string, line int) string {
+	lines := strings.Split(body, "\n")
+	if line < 0 || line >= len(lines) {
+		return body
+	}
+	m := itemRe.FindStringSubmatch(lines[line])
+	if m == nil {
+		return body
+	}
+	mark := "x"
+	if m[2] != " " {
+		mark = " "
+	}
+	lines[line] = m[1] + mark + m[3]
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,76 @@
+package checklist
+
+import "testing"
+
+const prBody = `## PR: Fix login
+
+### Checklist
+- [ ] Tests added/updated
+- [x] Documentation updated
+  * [X] Nested item
+- [ ]
+1. [ ] numbered lists are not checklists
+- [ ] Self-reviewed code`
+
+func TestParse(t *testing.T) {
+	items := Parse(prBody)
+	want := []Item{
+		{Line: 3, Text: "Tests added/updated"},
+		{Line: 4, Text: "Documentation updated", Done: true},
+		{Line: 5, Text: "Nested item", Done: true},
+		{Line: 8, Text: "Self-reviewed code"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("Parse returned %d items, want %d: %+v", len(items), len(want), items)
+	}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Errorf("item %d = %+v, want %+v", i, items[i], want[i])
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	done, total := Progress(prBody)
+	if done != 2 || total != 4 {
+		t.Errorf("Progress = %d/%d, want 2/4", done, total)
+	}
+	if done, total := Progress("just text"); done != 0 || total != 0 {
+		t.Errorf("Progress(no items) = %d/%d, want 0/0", done, total)
+	}
+}
+
+func TestToggle(t *testing.T) {
+	body := Toggle(prBody, 3)
+	if got := Parse(body)[0]; !got.Done {
+		t.Errorf("item not checked after toggle: %+v", got)
+	}
+	body = Toggle(body, 5)
+	if got := Parse(body)[2]; got.Done || got.Text != "Nested item" {
+		t.Errorf("nested item not unchecked after toggle: %+v", got)
+	}
+	if Toggle(prBody, 0) != prBody {
+		t.Error("toggling a non-item line changed the body")
+	}
+	if Toggle(prBody, 99) != prBody {
+		t.Error("toggling an out-of-range line changed the body")
+	}
+}
+
+func TestAllDone(t *testing.T) {
+	if AllDone("") {
+		t.Error("AllDone(empty) = true, want false")
+	}
+	if AllDone(prBody) {
+		t.Error("AllDone(partial) = true, want false")
+	}
+	body := prBody
+	for _, it := range Parse(prBody) {
+		if !it.Done {
+			body = Toggle(body, it.Line)
+		}
+	}
+	if !AllDone(body) {
+		t.Errorf("AllDone after checking every item = false:\n%s", body)
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import "fmt"
+
+// runArchive implements "archive <id>". Archiving an archived todo is a no-op.
+func runArchive(e *env, args []string) int {
+	fs := newFlagSet(e, "archive")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	todo, code := findTodoArg(e, fs, positional)
+	if todo == nil {
+		return code
+	}
+	if !todo.Done {
+		fmt.Fprintf(e.stderr, "todo %d is not completed\n", todo.ID)
+		return ExitError
+	}
+	e.store.Archive(todo.ID)
+	return ExitOK
+}
+
+// runUnarchive implements "unarchive <id>".
+func runUnarchive(e *env, args []string) int {
+	fs := newFlagSet(e, "unarchive")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	todo, code := findTodoArg(e, fs, positional)
+	if todo == nil {
+		return code
+	}
+	if todo.ArchivedAt == "" {
+		fmt.Fprintf(e.stderr, "todo %d is not archived\n", todo.ID)
+		return ExitNotFound
+	}
+	e.store.Unarchive(todo.ID)
+	return ExitOK
+}
+
+// runArchived implements "archived [--from DATE] [--to DATE]": archived
+// todos, most recently completed first. The range limits the completion
+// date; without one the whole archive is listed.
+func runArchived(e *env, args []string) int {
+	fs := newFlagSet(e, "archived")
+	from := fs.String("from", "", "first completion date of the range (YYYY-MM-DD)")
+	to := fs.String("to", "", "last completion date of the range (YYYY-MM-DD)")
+	ff := addFormatFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+
+	start, end := "0000-01-01", "9999-12-31"
+	if *from != "" || *to != "" {
+		var err error
+		start, end, err = parseRange(*from, *to, e)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitUsage
+		}
+	}
+	return writeOutput(e, format, todoOutput(e.store.ArchivedTodos(start, end)))
+}
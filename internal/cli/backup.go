@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// runBackup implements "backup [--output FILE]": every table of the
+// database as a versioned JSON document, written to stdout or FILE.
+func runBackup(e *env, args []string) int {
+	fs := newFlagSet(e, "backup")
+	output := fs.String("output", "", "write to this file instead of stdout")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 0 {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	b, err := e.store.Backup()
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitError
+	}
+	write := func(w io.Writer) error { return store.WriteBackup(w, b) }
+	if *output == "" {
+		err = write(e.stdout)
+	} else {
+		err = exportFile(*output, write)
+	}
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+// restoreBackup implements "restore --backup FILE": all data replaced by
+// a backup, migrated first if an older version wrote it. A path of "-"
+// reads stdin.
+func restoreBackup(e *env, path string) int {
+	r := io.Reader(os.Stdin)
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitError
+		}
+		defer f.Close()
+		r = f
+	}
+
+	b, err := store.ReadBackup(r)
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitError
+	}
+	if err := e.store.RestoreBackup(b); err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitError
+	}
+	fmt.Fprintf(e.stdout, "restored backup taken %s\n", b.CreatedAt)
+	return ExitOK
+}
@@ -0,0 +1,360 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/config"
+	"github.com/antti/todo-calendar/internal/google"
+	"github.com/antti/todo-calendar/internal/recurring"
+	"github.com/antti/todo-calendar/internal/remind"
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK       = 0 // command succeeded
+	ExitError    = 1 // command failed (store error, network error, ...)
+	ExitUsage    = 2 // invalid arguments or unknown flags
+	ExitNotFound = 3 // the referenced todo does not exist
+)
+
+// command is a single scriptable subcommand.
+type command struct {
+	usage string
+	help  string
+	run   func(env *env, args []string) int
+}
+
+// env carries the dependencies shared by all subcommands.
+type env struct {
+	store  store.TodoStore
+	cfg    config.Config
+	stdout io.Writer
+	stderr io.Writer
+	now    time.Time
+
+	// fetchEvents loads Google Calendar events; replaced in tests.
+	fetchEvents func() ([]google.CalendarEvent, error)
+}
+
+// commands maps subcommand names to their implementations. It is populated
+// in init because the command functions refer back to it for usage text.
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"add": {
+			usage: "add <text> [--date DATE] [--time HH:MM] [--duration D] [--remind LIST] [--repeat RULE] [--priority N] [--body TEXT] [--tags LIST] [--project NAME] [--json|--format F]",
+			help:  "Add a todo",
+			run:   runAdd,
+		},
+		"list": {
+			usage: "list [--from DATE] [--to DATE] [--tag LIST] [--project NAME] [--json|--format F]",
+			help:  "List todos, optionally limited to a date range",
+			run:   runList,
+		},
+		"done": {
+			usage: "done <id>",
+			help:  "Mark a todo as completed",
+			run:   runDone,
+		},
+		"edit": {
+			usage: "edit <id> [--text TEXT] [--date DATE] [--time HH:MM] [--duration D] [--remind LIST] [--repeat RULE] [--priority N] [--body TEXT] [--tags LIST] [--project NAME]",
+			help:  "Change fields of an existing todo",
+			run:   runEdit,
+		},
+		"history": {
+			usage: "history <id> [--json|--format F]",
+			help:  "List the occurrences of a repeating todo",
+			run:   runHistory,
+		},
+		"postpone": {
+			usage: "postpone <id> [--by day|weekday|week|month] [--to DATE]",
+			help:  "Move a todo to a later date",
+			run:   runPostpone,
+		},
+		"rm": {
+			usage: "rm <id>",
+			help:  "Move a todo to the trash",
+			run:   runRm,
+		},
+		"trash": {
+			usage: "trash [--empty] [--json|--format F]",
+			help:  "List trashed todos, or delete them forever with --empty",
+			run:   runTrash,
+		},
+		"restore": {
+			usage: "restore <id> | restore --backup FILE",
+			help:  "Move a todo out of the trash, or replace all data with a backup",
+			run:   runRestore,
+		},
+		"archive": {
+			usage: "archive <id>",
+			help:  "Move a completed todo out of the main views",
+			run:   runArchive,
+		},
+		"unarchive": {
+			usage: "unarchive <id>",
+			help:  "Move a todo out of the archive",
+			run:   runUnarchive,
+		},
+		"archived": {
+			usage: "archived [--from DATE] [--to DATE] [--json|--format F]",
+			help:  "List archived todos by completion date",
+			run:   runArchived,
+		},
+		"overdue": {
+			usage: "overdue [--rollover] [--json|--format F]",
+			help:  "List incomplete todos dated before today, or move them to today",
+			run:   runOverdue,
+		},
+		"export": {
+			usage: "export ics|todotxt [--output FILE] [--dry-run]",
+			help:  "Export todos as iCalendar or todo.txt",
+			run:   runExport,
+		},
+		"import": {
+			usage: "import ics|todotxt <file> [--dry-run]",
+			help:  "Import todos from an iCalendar or todo.txt file (- for stdin)",
+			run:   runImport,
+		},
+		"notes": {
+			usage: "notes export|import|sync [--dir DIR] [--period day|month]",
+			help:  "Write todos into markdown notes or read their checkboxes back",
+			run:   runNotes,
+		},
+		"backup": {
+			usage: "backup [--output FILE]",
+			help:  "Write a full backup of all data as JSON",
+			run:   runBackup,
+		},
+		"remind": {
+			usage: "remind [--once] [--interval D] [--notifier notify-send|command|stdout] [--command CMD]",
+			help:  "Deliver todo reminders until interrupted",
+			run:   runRemind,
+		},
+		"status": {
+			usage: "status [--json|--format F]",
+			help:  "Show today's pending todo count",
+			run:   runStatus,
+		},
+		"templates": {
+			usage: "templates [--json|--format F]",
+			help:  "List templates",
+			run:   runTemplates,
+		},
+		"projects": {
+			usage: "projects [--json|--format F]",
+			help:  "List projects with pending and completed counts",
+			run:   runProjects,
+		},
+		"searches": {
+			usage: "searches [save NAME QUERY | rm NAME | show NAME] [--json|--format F]",
+			help:  "List saved searches with their counts, or save, delete or run one",
+			run:   runSearches,
+		},
+		"schedules": {
+			usage: "schedules [--json|--format F]",
+			help:  "List recurring schedules",
+			run:   runSchedules,
+		},
+		"events": {
+			usage: "events [--from DATE] [--to DATE] [--json|--format F]",
+			help:  "List fetched Google Calendar events",
+			run:   runEvents,
+		},
+	}
+}
+
+// IsCommand reports whether name is a known subcommand.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Run executes the subcommand named by args[0] against the given store and
+// returns the process exit code. Output goes to stdout, diagnostics to stderr.
+func Run(args []string, s store.TodoStore, cfg config.Config, stdout, stderr io.Writer) int {
+	return run(args, &env{
+		store:       s,
+		cfg:         cfg,
+		stdout:      stdout,
+		stderr:      stderr,
+		now:         time.Now(),
+		fetchEvents: fetchGoogleEvents,
+	})
+}
+
+// run dispatches to the named subcommand using a prepared environment.
+func run(args []string, e *env) int {
+	if len(args) == 0 {
+		fmt.Fprintln(e.stderr, "missing command")
+		return ExitUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "unknown command %q\n", args[0])
+		return ExitUsage
+	}
+	return cmd.run(e, args[1:])
+}
+
+// WriteUsage writes the subcommand summary shown by --help.
+func WriteUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "Commands:\n")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n        %s\n", commands[name].usage, commands[name].help)
+	}
+}
+
+// parseArgs parses flags that may appear before, between, or after positional
+// arguments (the standard flag package stops at the first positional one).
+// It returns the positional arguments in order.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet creates a flag set that reports errors to the env's stderr.
+func newFlagSet(e *env, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: todo-calendar %s\n", commands[name].usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// flagWasSet reports whether the named flag was given explicitly.
+func flagWasSet(fs *flag.FlagSet, name string) bool {
+	found := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
+
+// parseID parses a todo ID positional argument.
+func parseID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid todo id %q", s)
+	}
+	return id, nil
+}
+
+// parseDate converts a command-line date into the ISO storage date and
+// date precision used by the store. Accepted forms:
+//   - "" (floating, no date)
+//   - "today" / "tomorrow"
+//   - "YYYY-MM-DD" or the configured display format (day precision)
+//   - "YYYY-MM" (month precision)
+//   - "YYYY" (year precision)
+func parseDate(input string, layout string, now time.Time) (string, string, error) {
+	input = strings.TrimSpace(input)
+	switch input {
+	case "":
+		return "", "", nil
+	case "today":
+		return now.Format("2006-01-02"), "day", nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format("2006-01-02"), "day", nil
+	}
+	if t, err := time.Parse("2006-01-02", input); err == nil {
+		return t.Format("2006-01-02"), "day", nil
+	}
+	if iso, err := config.ParseUserDate(input, layout); err == nil {
+		return iso, "day", nil
+	}
+	if t, err := time.Parse("2006-01", input); err == nil {
+		return t.Format("2006-01-02"), "month", nil
+	}
+	if t, err := time.Parse("2006", input); err == nil {
+		return t.Format("2006-01-02"), "year", nil
+	}
+	return "", "", fmt.Errorf("invalid date %q (want YYYY-MM-DD, YYYY-MM, YYYY, today or tomorrow)", input)
+}
+
+// parseTime validates a start time and duration given on the command line
+// for a todo with the given date precision. It returns the canonical
+// "HH:MM" start time and the duration in minutes.
+func parseTime(clock, duration, precision string) (string, int, error) {
+	startTime, err := store.ParseClock(clock)
+	if err != nil {
+		return "", 0, err
+	}
+	minutes, err := store.ParseDuration(duration)
+	if err != nil {
+		return "", 0, err
+	}
+	if startTime == "" {
+		if minutes > 0 {
+			return "", 0, fmt.Errorf("a duration needs a start time (--time)")
+		}
+		return "", 0, nil
+	}
+	if precision != "day" {
+		return "", 0, fmt.Errorf("a start time needs a day date (YYYY-MM-DD)")
+	}
+	return startTime, minutes, nil
+}
+
+// parseReminders parses a --remind list for a todo with the given date
+// precision. Reminders are relative to a due time, so they need a day date.
+func parseReminders(list, precision string) ([]int, error) {
+	offsets, err := remind.ParseOffsets(list)
+	if err != nil {
+		return nil, err
+	}
+	if len(offsets) > 0 && precision != "day" {
+		return nil, fmt.Errorf("reminders need a day date (YYYY-MM-DD)")
+	}
+	return offsets, nil
+}
+
+// parseRepeat parses a --repeat rule for a todo with the given date and
+// precision and returns its canonical form; "" means no repeat. RRULEs
+// without a DTSTART are anchored on the date, or on today for a floating
+// todo.
+func parseRepeat(rule, date, precision string, today time.Time) (string, error) {
+	if strings.TrimSpace(rule) == "" {
+		return "", nil
+	}
+	r, err := recurring.ParseRepeat(recurring.AnchorRepeat(rule, date, today))
+	if err != nil {
+		return "", err
+	}
+	if precision != "" && precision != "day" {
+		return "", fmt.Errorf("repeating todos need a day date (YYYY-MM-DD) or none")
+	}
+	return r.String(), nil
+}
+
+// validPriority reports whether p is an accepted priority (0 = none, 1-4).
+func validPriority(p int) bool {
+	return p >= 0 && p <= 4
+}
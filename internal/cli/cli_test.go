@@ -0,0 +1,887 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/config"
+	"github.com/antti/todo-calendar/internal/google"
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// newTestEnv returns an env backed by a fresh SQLite store with "today"
+// pinned to 2026-10-17.
+func newTestEnv(t *testing.T) (*env, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	s, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	var stdout, stderr bytes.Buffer
+	return &env{
+		store:  s,
+		cfg:    config.DefaultConfig(),
+		stdout: &stdout,
+		stderr: &stderr,
+		now:    time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC),
+	}, &stdout, &stderr
+}
+
+// runOK runs a command and fails the test on a non-zero exit code.
+func runOK(t *testing.T, e *env, args ...string) {
+	t.Helper()
+	if code := run(args, e); code != ExitOK {
+		t.Fatalf("%v: exit code %d, stderr: %s", args, code, e.stderr.(*bytes.Buffer).String())
+	}
+}
+
+// addedID parses the ID printed by the most recent add command.
+func addedID(t *testing.T, stdout *bytes.Buffer) int {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	id, err := strconv.Atoi(lines[len(lines)-1])
+	if err != nil {
+		t.Fatalf("add output %q is not an id", stdout.String())
+	}
+	return id
+}
+
+func TestAddWithFlagsAfterText(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "Write report", "--date", "2026-10-20", "--priority", "1", "--body", "notes")
+
+	got := e.store.Find(addedID(t, stdout))
+	if got == nil {
+		t.Fatal("added todo not found")
+	}
+	if got.Text != "Write report" || got.Date != "2026-10-20" || got.DatePrecision != "day" || got.Priority != 1 {
+		t.Errorf("unexpected todo: %+v", got)
+	}
+	if got.Body != "notes" {
+		t.Errorf("body = %q, want %q", got.Body, "notes")
+	}
+}
+
+func TestAddDatePrecisions(t *testing.T) {
+	tests := []struct {
+		date     string
+		wantDate string
+		wantPrec string
+	}{
+		{"", "", ""},
+		{"2026-11", "2026-11-01", "month"},
+		{"2027", "2027-01-01", "year"},
+		{"today", "2026-10-17", "day"},
+		{"tomorrow", "2026-10-18", "day"},
+	}
+	for _, tt := range tests {
+		e, stdout, _ := newTestEnv(t)
+		runOK(t, e, "add", "--date", tt.date, "task")
+		got := e.store.Find(addedID(t, stdout))
+		if got.Date != tt.wantDate || got.DatePrecision != tt.wantPrec {
+			t.Errorf("--date %q: got (%q, %q), want (%q, %q)", tt.date, got.Date, got.DatePrecision, tt.wantDate, tt.wantPrec)
+		}
+	}
+}
+
+func TestAddRejectsBadInput(t *testing.T) {
+	e, _, _ := newTestEnv(t)
+	for _, args := range [][]string{
+		{"add"},
+		{"add", "x", "--date", "next week"},
+		{"add", "x", "--priority", "7"},
+		{"add", "x", "--bogus"},
+	} {
+		if code := run(args, e); code != ExitUsage {
+			t.Errorf("%v: exit code %d, want %d", args, code, ExitUsage)
+		}
+	}
+	if n := len(e.store.Todos()); n != 0 {
+		t.Errorf("rejected adds created %d todos", n)
+	}
+}
+
+func TestListRange(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "before", "--date", "2026-10-01")
+	runOK(t, e, "add", "inside", "--date", "2026-10-18")
+	runOK(t, e, "add", "after", "--date", "2026-11-01")
+	runOK(t, e, "add", "floating")
+	stdout.Reset()
+
+	runOK(t, e, "list", "--from", "2026-10-17", "--to", "2026-10-31")
+	out := stdout.String()
+	if !strings.Contains(out, "inside") {
+		t.Errorf("list output missing in-range todo:\n%s", out)
+	}
+	for _, name := range []string{"before", "after", "floating"} {
+		if strings.Contains(out, name) {
+			t.Errorf("list output contains out-of-range todo %q:\n%s", name, out)
+		}
+	}
+
+	stdout.Reset()
+	runOK(t, e, "list")
+	if n := strings.Count(stdout.String(), "\n"); n != 4 {
+		t.Errorf("unranged list: got %d lines, want 4", n)
+	}
+}
+
+func TestDoneEditRm(t *testing.T) {
+	e, stdout, stderr := newTestEnv(t)
+	runOK(t, e, "add", "task", "--date", "2026-10-20", "--priority", "2")
+	id := strconv.Itoa(addedID(t, stdout))
+
+	runOK(t, e, "done", id)
+	runOK(t, e, "done", id) // idempotent
+	if got := e.store.Find(addedID(t, stdout)); !got.Done {
+		t.Error("done did not complete the todo")
+	}
+
+	runOK(t, e, "edit", id, "--text", "renamed", "--date", "")
+	got := e.store.Find(addedID(t, stdout))
+	if got.Text != "renamed" || got.Date != "" || got.DatePrecision != "" {
+		t.Errorf("edit: unexpected todo %+v", got)
+	}
+	if got.Priority != 2 {
+		t.Errorf("edit changed priority without --priority: got %d", got.Priority)
+	}
+
+	runOK(t, e, "rm", id)
+	if e.store.Find(addedID(t, stdout)) != nil {
+		t.Error("rm did not delete the todo")
+	}
+
+	stderr.Reset()
+	if code := run([]string{"rm", id}, e); code != ExitNotFound {
+		t.Errorf("rm of missing todo: exit code %d, want %d", code, ExitNotFound)
+	}
+	if !strings.Contains(stderr.String(), "not found") {
+		t.Errorf("rm of missing todo: stderr %q", stderr.String())
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	e, _, _ := newTestEnv(t)
+	if IsCommand("frobnicate") {
+		t.Error("IsCommand reported unknown command as known")
+	}
+	if code := run([]string{"frobnicate"}, e); code != ExitUsage {
+		t.Errorf("unknown command: exit code %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestListJSON(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "first", "--date", "2026-10-20", "--priority", "1")
+	runOK(t, e, "add", "second")
+	stdout.Reset()
+
+	runOK(t, e, "list", "--json")
+	var todos []store.Todo
+	if err := json.Unmarshal(stdout.Bytes(), &todos); err != nil {
+		t.Fatalf("list --json is not valid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(todos) != 2 || todos[0].Text != "first" || todos[0].Priority != 1 || todos[0].Date != "2026-10-20" {
+		t.Errorf("unexpected todos: %+v", todos)
+	}
+
+	// An empty result is an empty array, never null.
+	stdout.Reset()
+	runOK(t, e, "list", "--from", "2030-01-01", "--format", "json")
+	if got := strings.TrimSpace(stdout.String()); got != "[]" {
+		t.Errorf("empty list --format json = %q, want []", got)
+	}
+}
+
+func TestListTSV(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "tab\there", "--body", "line1\nline2")
+	stdout.Reset()
+
+	runOK(t, e, "list", "--format", "tsv")
+	lines := strings.Split(strings.TrimRight(stdout.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want header + 1 row, got %d lines:\n%s", len(lines), stdout.String())
+	}
+	if lines[0] != strings.Join(todoHeader, "\t") {
+		t.Errorf("header = %q", lines[0])
+	}
+	cells := strings.Split(lines[1], "\t")
+	if len(cells) != len(todoHeader) {
+		t.Fatalf("row has %d cells, want %d: %q", len(cells), len(todoHeader), lines[1])
+	}
+	if cells[7] != `tab\there` || cells[8] != `line1\nline2` {
+		t.Errorf("text/body not escaped: %q / %q", cells[7], cells[8])
+	}
+}
+
+func TestTodoHeaderMatchesJSON(t *testing.T) {
+	columns := make(map[string]bool)
+	for _, c := range todoHeader {
+		columns[c] = true
+	}
+	typ := reflect.TypeOf(store.Todo{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" && !columns[name] {
+			t.Errorf("JSON field %q has no TSV column", name)
+		}
+	}
+}
+
+func TestFormatFlagValidation(t *testing.T) {
+	e, _, _ := newTestEnv(t)
+	for _, args := range [][]string{
+		{"list", "--format", "xml"},
+		{"list", "--json", "--format", "tsv"},
+		{"add", "x", "--format", "yaml"},
+	} {
+		if code := run(args, e); code != ExitUsage {
+			t.Errorf("%v: exit code %d, want %d", args, code, ExitUsage)
+		}
+	}
+	if n := len(e.store.Todos()); n != 0 {
+		t.Errorf("add with a bad format created %d todos", n)
+	}
+}
+
+func TestStatusFormats(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "status")
+	if stdout.String() != "" {
+		t.Errorf("plain status with nothing pending = %q, want empty", stdout.String())
+	}
+
+	runOK(t, e, "add", "a", "--date", "today")
+	runOK(t, e, "add", "b", "--date", "today")
+	runOK(t, e, "done", strconv.Itoa(addedID(t, stdout)))
+	stdout.Reset()
+
+	runOK(t, e, "status")
+	if stdout.String() != "1" {
+		t.Errorf("plain status = %q, want %q", stdout.String(), "1")
+	}
+
+	stdout.Reset()
+	runOK(t, e, "status", "--json")
+	var rec statusRecord
+	if err := json.Unmarshal(stdout.Bytes(), &rec); err != nil {
+		t.Fatalf("status --json: %v", err)
+	}
+	if rec != (statusRecord{Date: "2026-10-17", Pending: 1, Completed: 1}) {
+		t.Errorf("status --json = %+v", rec)
+	}
+}
+
+func TestTemplatesAndSchedulesJSON(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "templates", "--json")
+	var templates []store.Template
+	if err := json.Unmarshal(stdout.Bytes(), &templates); err != nil {
+		t.Fatalf("templates --json: %v", err)
+	}
+	if len(templates) == 0 || templates[0].Name == "" {
+		t.Fatalf("expected seeded templates, got %+v", templates)
+	}
+
+	if _, err := e.store.AddSchedule(templates[0].ID, "weekly", "mon,fri", "{}"); err != nil {
+		t.Fatalf("add schedule: %v", err)
+	}
+	stdout.Reset()
+	runOK(t, e, "schedules", "--json")
+	var schedules []store.Schedule
+	if err := json.Unmarshal(stdout.Bytes(), &schedules); err != nil {
+		t.Fatalf("schedules --json: %v", err)
+	}
+	if len(schedules) != 1 || schedules[0].CadenceValue != "mon,fri" || schedules[0].TemplateID != templates[0].ID {
+		t.Errorf("unexpected schedules: %+v", schedules)
+	}
+}
+
+func TestEventsJSON(t *testing.T) {
+	e, stdout, stderr := newTestEnv(t)
+	start := time.Date(2026, 10, 18, 14, 30, 0, 0, time.UTC)
+	e.fetchEvents = func() ([]google.CalendarEvent, error) {
+		return []google.CalendarEvent{
+			{ID: "a", Summary: "Dentist", Date: "2026-10-18", Start: start, End: start.Add(time.Hour), Status: "confirmed"},
+			{ID: "b", Summary: "Holiday", Date: "2026-12-24", EndDate: "2026-12-25", AllDay: true, Status: "confirmed"},
+			{ID: "c", Summary: "Gone", Date: "2026-10-18", Status: "cancelled"},
+		}, nil
+	}
+
+	runOK(t, e, "events", "--json", "--from", "2026-10-01", "--to", "2026-10-31")
+	var records []eventRecord
+	if err := json.Unmarshal(stdout.Bytes(), &records); err != nil {
+		t.Fatalf("events --json: %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "a" || records[0].Start != "2026-10-18T14:30:00Z" {
+		t.Errorf("unexpected events: %+v", records)
+	}
+
+	e.fetchEvents = func() ([]google.CalendarEvent, error) { return nil, errFake }
+	stderr.Reset()
+	if code := run([]string{"events"}, e); code != ExitError {
+		t.Errorf("events with fetch error: exit code %d, want %d", code, ExitError)
+	}
+}
+
+// errFake is a stand-in error for failing dependencies.
+var errFake = fmt.Errorf("fake failure")
+
+func TestTags(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "Deploy", "--tags", "Work, oncall")
+	deploy := addedID(t, stdout)
+	runOK(t, e, "add", "Groceries", "--tags", "home")
+	groceries := addedID(t, stdout)
+
+	if got := e.store.Find(deploy).Tags; strings.Join(got, ",") != "oncall,work" {
+		t.Errorf("tags after add = %v", got)
+	}
+
+	stdout.Reset()
+	runOK(t, e, "list", "--tag", "work")
+	if got := strings.TrimSpace(stdout.String()); got != strconv.Itoa(deploy)+"\t[ ]\t-\t-\tDeploy #oncall #work" {
+		t.Errorf("list --tag work = %q", got)
+	}
+
+	runOK(t, e, "edit", strconv.Itoa(groceries), "--tags", "home,work")
+	stdout.Reset()
+	runOK(t, e, "list", "--tag", "#work", "--json")
+	var todos []store.Todo
+	if err := json.Unmarshal(stdout.Bytes(), &todos); err != nil {
+		t.Fatalf("list --json: %v", err)
+	}
+	if len(todos) != 2 {
+		t.Errorf("list --tag work after edit: got %d todos, want 2", len(todos))
+	}
+
+	// --tags "" clears the tags; omitting --tags leaves them alone.
+	runOK(t, e, "edit", strconv.Itoa(deploy), "--text", "Deploy v2")
+	if got := e.store.Find(deploy).Tags; len(got) != 2 {
+		t.Errorf("edit without --tags changed tags to %v", got)
+	}
+	runOK(t, e, "edit", strconv.Itoa(deploy), "--tags", "")
+	if got := e.store.Find(deploy).Tags; len(got) != 0 {
+		t.Errorf("edit --tags \"\" left %v", got)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	e, stdout, stderr := newTestEnv(t)
+	runOK(t, e, "add", "Report", "--project", "Work")
+	report := addedID(t, stdout)
+	runOK(t, e, "add", "Laundry", "--project", "home")
+	runOK(t, e, "add", "Review", "--project", "work")
+	review := addedID(t, stdout)
+	runOK(t, e, "done", strconv.Itoa(review))
+
+	// "work" reuses the existing "Work" project.
+	stdout.Reset()
+	runOK(t, e, "projects", "--json")
+	var projects []projectRecord
+	if err := json.Unmarshal(stdout.Bytes(), &projects); err != nil {
+		t.Fatalf("projects --json: %v", err)
+	}
+	if len(projects) != 2 || projects[1].Name != "Work" || projects[1].Pending != 1 || projects[1].Completed != 1 {
+		t.Errorf("projects = %+v", projects)
+	}
+
+	stdout.Reset()
+	runOK(t, e, "list", "--project", "WORK")
+	if n := strings.Count(stdout.String(), "\n"); n != 2 {
+		t.Errorf("list --project WORK: got %d lines:\n%s", n, stdout.String())
+	}
+	if code := run([]string{"list", "--project", "nope"}, e); code != ExitNotFound {
+		t.Errorf("list --project nope: exit code %d, want %d (stderr %q)", code, ExitNotFound, stderr.String())
+	}
+
+	runOK(t, e, "edit", strconv.Itoa(report), "--project", "")
+	if got := e.store.Find(report).ProjectID; got != 0 {
+		t.Errorf("ProjectID after --project \"\" = %d", got)
+	}
+}
+
+func TestSubtasks(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "Release", "--body", "- [x] Tag\n- [ ] Publish\n- [ ]")
+	id := addedID(t, stdout)
+
+	stdout.Reset()
+	runOK(t, e, "list")
+	if !strings.Contains(stdout.String(), "Release (1/2)") {
+		t.Errorf("list output missing progress badge: %q", stdout.String())
+	}
+
+	runOK(t, e, "edit", strconv.Itoa(id), "--body", "- [x] Tag\n- [x] Publish")
+	if got := e.store.Find(id); !got.Done {
+		t.Error("todo not completed after checking every subtask")
+	}
+}
+
+func TestTimes(t *testing.T) {
+	e, stdout, stderr := newTestEnv(t)
+	runOK(t, e, "add", "Standup", "--date", "2026-10-20", "--time", "9:30", "--duration", "15")
+	id := addedID(t, stdout)
+	runOK(t, e, "add", "Lunch", "--date", "2026-10-20", "--time", "12:00")
+	runOK(t, e, "add", "Errand", "--date", "2026-10-20")
+
+	stdout.Reset()
+	runOK(t, e, "list", "--from", "2026-10-20", "--to", "2026-10-20")
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "Errand") ||
+		!strings.Contains(lines[1], "2026-10-20 09:30-09:45") || !strings.Contains(lines[2], "12:00\t") {
+		t.Errorf("list not ordered by time or missing times:\n%s", stdout.String())
+	}
+
+	runOK(t, e, "edit", strconv.Itoa(id), "--duration", "1h")
+	if got := e.store.Find(id); got.StartTime != "09:30" || got.Duration != 60 {
+		t.Errorf("after --duration: %q/%d, want 09:30/60", got.StartTime, got.Duration)
+	}
+	runOK(t, e, "edit", strconv.Itoa(id), "--time", "")
+	if got := e.store.Find(id); got.HasTime() || got.Duration != 0 {
+		t.Errorf("after --time \"\": %q/%d, want untimed", got.StartTime, got.Duration)
+	}
+
+	for _, args := range [][]string{
+		{"add", "x", "--date", "2026-10", "--time", "10:00"},
+		{"add", "x", "--date", "2026-10-20", "--duration", "30"},
+		{"add", "x", "--date", "2026-10-20", "--time", "25:00"},
+		{"edit", strconv.Itoa(id), "--duration", "30"},
+	} {
+		stderr.Reset()
+		if code := run(args, e); code != ExitUsage {
+			t.Errorf("%v: exit code %d, want %d", args, code, ExitUsage)
+		}
+	}
+}
+
+func TestReminders(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	e.cfg.ReminderTime = "09:00"
+	runOK(t, e, "add", "Dentist", "--date", "2026-10-17", "--time", "9:30", "--remind", "15m,1h")
+	id := addedID(t, stdout)
+	if got := e.store.Find(id).Reminders; len(got) != 2 || got[0] != 15 || got[1] != 60 {
+		t.Fatalf("reminders = %v, want [15 60]", got)
+	}
+	if code := run([]string{"add", "x", "--date", "2026-10", "--remind", "1h"}, e); code != ExitUsage {
+		t.Errorf("reminder on a month todo: exit code %d, want %d", code, ExitUsage)
+	}
+
+	// e.now is 09:00: only the one-hour reminder is due.
+	stdout.Reset()
+	runOK(t, e, "remind", "--once", "--notifier", "stdout")
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 1 ||
+		!strings.Contains(lines[0], "Dentist\tDue today at 09:30") {
+		t.Errorf("remind output = %q", stdout.String())
+	}
+	stdout.Reset()
+	runOK(t, e, "remind", "--once", "--notifier", "stdout")
+	if stdout.Len() != 0 {
+		t.Errorf("reminder repeated: %q", stdout.String())
+	}
+
+	runOK(t, e, "edit", strconv.Itoa(id), "--remind", "")
+	if got := e.store.Find(id).Reminders; len(got) != 0 {
+		t.Errorf("reminders after --remind \"\" = %v", got)
+	}
+	if code := run([]string{"remind", "--once", "--notifier", "pager"}, e); code != ExitUsage {
+		t.Errorf("unknown notifier: exit code %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestTrash(t *testing.T) {
+	e, stdout, stderr := newTestEnv(t)
+	runOK(t, e, "add", "keep", "--tags", "home")
+	keep := addedID(t, stdout)
+	runOK(t, e, "add", "drop")
+	drop := addedID(t, stdout)
+
+	runOK(t, e, "rm", strconv.Itoa(keep))
+	runOK(t, e, "rm", strconv.Itoa(drop))
+	stdout.Reset()
+	runOK(t, e, "list")
+	if stdout.Len() != 0 {
+		t.Errorf("list shows trashed todos: %q", stdout.String())
+	}
+
+	stdout.Reset()
+	runOK(t, e, "trash", "--json")
+	var trashed []store.Todo
+	if err := json.Unmarshal(stdout.Bytes(), &trashed); err != nil {
+		t.Fatalf("trash --json: %v", err)
+	}
+	if len(trashed) != 2 || trashed[0].DeletedAt == "" {
+		t.Fatalf("trash = %+v, want 2 trashed todos", trashed)
+	}
+
+	runOK(t, e, "restore", strconv.Itoa(keep))
+	if got := e.store.Find(keep); got == nil || !got.HasTag("home") {
+		t.Errorf("restored todo = %+v", got)
+	}
+	stderr.Reset()
+	if code := run([]string{"restore", strconv.Itoa(keep)}, e); code != ExitNotFound {
+		t.Errorf("restore of a live todo: exit code %d, want %d", code, ExitNotFound)
+	}
+
+	stdout.Reset()
+	runOK(t, e, "trash", "--empty")
+	if strings.TrimSpace(stdout.String()) != "1 todos deleted" || len(e.store.TrashedTodos()) != 0 {
+		t.Errorf("trash --empty: %q, %d left", stdout.String(), len(e.store.TrashedTodos()))
+	}
+	if code := run([]string{"restore", strconv.Itoa(drop)}, e); code != ExitNotFound {
+		t.Errorf("restore of a purged todo: exit code %d, want %d", code, ExitNotFound)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "report")
+	id := addedID(t, stdout)
+
+	if code := run([]string{"archive", strconv.Itoa(id)}, e); code != ExitError {
+		t.Errorf("archive of an open todo: exit code %d, want %d", code, ExitError)
+	}
+	runOK(t, e, "done", strconv.Itoa(id))
+	runOK(t, e, "archive", strconv.Itoa(id))
+	stdout.Reset()
+	runOK(t, e, "list")
+	if stdout.Len() != 0 {
+		t.Errorf("list shows archived todos: %q", stdout.String())
+	}
+
+	stdout.Reset()
+	runOK(t, e, "archived", "--json")
+	var archived []store.Todo
+	if err := json.Unmarshal(stdout.Bytes(), &archived); err != nil {
+		t.Fatalf("archived --json: %v", err)
+	}
+	if len(archived) != 1 || archived[0].CompletedAt == "" || archived[0].ArchivedAt == "" {
+		t.Fatalf("archived = %+v, want 1 archived todo", archived)
+	}
+	stdout.Reset()
+	runOK(t, e, "archived", "--from", "2000-01-01", "--to", "2000-12-31")
+	if stdout.Len() != 0 {
+		t.Errorf("archived outside the range: %q", stdout.String())
+	}
+
+	runOK(t, e, "unarchive", strconv.Itoa(id))
+	if code := run([]string{"unarchive", strconv.Itoa(id)}, e); code != ExitNotFound {
+		t.Errorf("unarchive of a live todo: exit code %d, want %d", code, ExitNotFound)
+	}
+}
+
+func TestExportICS(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "Dentist", "--date", "2026-10-20", "--priority", "2")
+
+	stdout.Reset()
+	runOK(t, e, "export", "ics")
+	out := stdout.String()
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n") || !strings.Contains(out, "SUMMARY:Dentist\r\n") || !strings.Contains(out, "DUE;VALUE=DATE:20261020\r\n") {
+		t.Errorf("export ics = %q", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "todos.ics")
+	runOK(t, e, "export", "ics", "--output", path)
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != out {
+		t.Errorf("export ics --output wrote %q (%v)", data, err)
+	}
+
+	if code := run([]string{"export", "csv"}, e); code != ExitUsage {
+		t.Errorf("export csv: exit code %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestImportICS(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	path := filepath.Join(t.TempDir(), "invite.ics")
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:x@example.com\r\nSUMMARY:Review\r\nDTSTART:20261020T140000\r\nDTEND:20261020T150000\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	if err := os.WriteFile(path, []byte(ics), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runOK(t, e, "import", "ics", path)
+	if got := strings.TrimSpace(stdout.String()); got != "1 added, 0 updated, 0 skipped" {
+		t.Errorf("import output = %q", got)
+	}
+	stdout.Reset()
+	runOK(t, e, "import", "ics", path)
+	if got := strings.TrimSpace(stdout.String()); got != "0 added, 1 updated, 0 skipped" {
+		t.Errorf("re-import output = %q", got)
+	}
+	todos := e.store.Todos()
+	if len(todos) != 1 || todos[0].StartTime != "14:00" || todos[0].Duration != 60 {
+		t.Errorf("todos = %+v", todos)
+	}
+
+	if code := run([]string{"import", "ics", filepath.Join(t.TempDir(), "missing.ics")}, e); code != ExitError {
+		t.Errorf("import of a missing file: exit code %d, want %d", code, ExitError)
+	}
+}
+
+func TestTodoTxt(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	dir := t.TempDir()
+	in := filepath.Join(dir, "todo.txt")
+	if err := os.WriteFile(in, []byte("(A) Call mom @phone due:2026-10-20\nx Pay rent\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runOK(t, e, "import", "todotxt", in, "--dry-run")
+	if !strings.Contains(stdout.String(), "+ (A) ") || !strings.HasSuffix(stdout.String(), "would add 2, update 0, leave 0 unchanged\n") {
+		t.Errorf("dry run = %q", stdout.String())
+	}
+	if len(e.store.Todos()) != 0 {
+		t.Fatal("dry run changed the store")
+	}
+
+	stdout.Reset()
+	runOK(t, e, "import", "todotxt", in)
+	if got := strings.TrimSpace(stdout.String()); got != "2 added, 0 updated, 0 unchanged" {
+		t.Errorf("import = %q", got)
+	}
+
+	out := filepath.Join(dir, "out.txt")
+	stdout.Reset()
+	runOK(t, e, "export", "todotxt", "--output", out, "--dry-run")
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "+ ") {
+		t.Errorf("export dry run = %q", stdout.String())
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Error("export dry run wrote the file")
+	}
+	runOK(t, e, "export", "todotxt", "--output", out)
+	stdout.Reset()
+	runOK(t, e, "export", "todotxt", "--output", out, "--dry-run")
+	if stdout.Len() != 0 {
+		t.Errorf("dry run after export = %q", stdout.String())
+	}
+
+	if code := run([]string{"export", "ics", "--dry-run"}, e); code != ExitUsage {
+		t.Errorf("export ics --dry-run: exit code %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestNotes(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	dir := t.TempDir()
+	runOK(t, e, "add", "Dentist", "--date", "2026-10-20")
+	id := addedID(t, stdout)
+
+	stdout.Reset()
+	runOK(t, e, "notes", "export", "--dir", dir)
+	if got := strings.TrimSpace(stdout.String()); got != "1 notes written, 0 removed" {
+		t.Errorf("notes export = %q", got)
+	}
+	path := filepath.Join(dir, "2026-10-20.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checked := strings.Replace(string(data), "- [ ]", "- [x]", 1)
+	if err := os.WriteFile(path, []byte(checked), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout.Reset()
+	runOK(t, e, "notes", "sync", "--dir", dir)
+	if got := stdout.String(); got != "1 todos updated\n0 notes written, 0 removed\n" {
+		t.Errorf("notes sync = %q", got)
+	}
+	if !e.store.Find(id).Done {
+		t.Error("checked box did not complete the todo")
+	}
+
+	if code := run([]string{"notes", "export"}, e); code != ExitError {
+		t.Errorf("notes export without a directory: exit code %d, want %d", code, ExitError)
+	}
+	if code := run([]string{"notes", "export", "--dir", dir, "--period", "week"}, e); code != ExitUsage {
+		t.Errorf("notes export --period week: exit code %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "Dentist", "--date", "2026-10-20", "--tags", "health")
+	id := addedID(t, stdout)
+
+	path := filepath.Join(t.TempDir(), "backup.json")
+	runOK(t, e, "backup", "--output", path)
+	runOK(t, e, "rm", strconv.Itoa(id))
+	runOK(t, e, "add", "Later")
+
+	stdout.Reset()
+	runOK(t, e, "restore", "--backup", path)
+	if !strings.HasPrefix(stdout.String(), "restored backup taken ") {
+		t.Errorf("restore --backup = %q", stdout.String())
+	}
+	todos := e.store.Todos()
+	if len(todos) != 1 || todos[0].ID != id || len(todos[0].Tags) != 1 {
+		t.Errorf("restored todos = %+v", todos)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"format":"todo-calendar-backup","version":9}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"restore", "--backup", path}, e); code != ExitError {
+		t.Errorf("restore of an unsupported backup: exit code %d, want %d", code, ExitError)
+	}
+	if code := run([]string{"restore", "1", "--backup", path}, e); code != ExitUsage {
+		t.Errorf("restore with an ID and --backup: exit code %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestSearches(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "Dentist", "--date", "2026-10-20", "--priority", "1")
+	runOK(t, e, "add", "Laundry")
+
+	runOK(t, e, "searches", "save", "Urgent", "p:1", "done:no")
+	if code := run([]string{"searches", "save", "Bad", "p:9"}, e); code != ExitUsage {
+		t.Errorf("searches save with an invalid query: exit code %d, want %d", code, ExitUsage)
+	}
+
+	stdout.Reset()
+	runOK(t, e, "searches", "--json")
+	var searches []savedSearchRecord
+	if err := json.Unmarshal(stdout.Bytes(), &searches); err != nil {
+		t.Fatalf("searches --json: %v", err)
+	}
+	if len(searches) != 1 || searches[0].Query != "p:1 done:no" || searches[0].Pending != 1 {
+		t.Errorf("searches = %+v", searches)
+	}
+
+	stdout.Reset()
+	runOK(t, e, "searches", "show", "urgent")
+	if !strings.Contains(stdout.String(), "Dentist") || strings.Contains(stdout.String(), "Laundry") {
+		t.Errorf("searches show urgent:\n%s", stdout.String())
+	}
+
+	runOK(t, e, "searches", "rm", "Urgent")
+	if code := run([]string{"searches", "rm", "Urgent"}, e); code != ExitNotFound {
+		t.Errorf("searches rm of a deleted search: exit code %d, want %d", code, ExitNotFound)
+	}
+}
+
+func TestOverdue(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "Dentist", "--date", "2026-10-01")
+	runOK(t, e, "add", "Review", "--date", "2026-09")
+	runOK(t, e, "add", "Future", "--date", "2026-10-20")
+
+	stdout.Reset()
+	runOK(t, e, "overdue")
+	if out := stdout.String(); strings.Count(out, "\n") != 2 || strings.Contains(out, "Future") {
+		t.Errorf("overdue:\n%s", out)
+	}
+
+	stdout.Reset()
+	runOK(t, e, "overdue", "--rollover")
+	if got := stdout.String(); got != "2 todos rolled over\n" {
+		t.Errorf("overdue --rollover = %q", got)
+	}
+	stdout.Reset()
+	runOK(t, e, "overdue")
+	if stdout.Len() != 0 {
+		t.Errorf("overdue after rollover:\n%s", stdout.String())
+	}
+	if n := len(e.store.TodosForDateRange("2026-10-17", "2026-10-17")); n != 1 {
+		t.Errorf("%d todos on 2026-10-17 after rollover, want 1", n)
+	}
+}
+
+func TestPostpone(t *testing.T) {
+	e, stdout, _ := newTestEnv(t)
+	runOK(t, e, "add", "Dentist", "--date", "2026-10-16", "--time", "9:30")
+	id := addedID(t, stdout)
+	runOK(t, e, "add", "Review", "--date", "2026-10")
+	review := addedID(t, stdout)
+
+	// Overdue todos count from today (2026-10-17, a Saturday).
+	stdout.Reset()
+	runOK(t, e, "postpone", strconv.Itoa(id), "--by", "weekday")
+	if got := stdout.String(); got != fmt.Sprintf("%d\t2026-10-19\n", id) {
+		t.Errorf("postpone --by weekday = %q", got)
+	}
+	if got := e.store.Find(id); got.StartTime != "09:30" {
+		t.Errorf("postpone dropped the start time: %+v", got)
+	}
+
+	runOK(t, e, "postpone", strconv.Itoa(review))
+	if got := e.store.Find(review); got.Date != "2026-11-01" || got.DatePrecision != "month" {
+		t.Errorf("postponed month todo = %s (%s)", got.Date, got.DatePrecision)
+	}
+
+	runOK(t, e, "postpone", strconv.Itoa(id), "--to", "fri")
+	if got := e.store.Find(id).Date; got != "2026-10-23" {
+		t.Errorf("postpone --to fri = %s", got)
+	}
+	if code := run([]string{"postpone", strconv.Itoa(id), "--to", "soon"}, e); code != ExitUsage {
+		t.Errorf("postpone --to soon: exit code %d, want %d", code, ExitUsage)
+	}
+	if code := run([]string{"postpone", strconv.Itoa(id), "--by", "year"}, e); code != ExitUsage {
+		t.Errorf("postpone --by year: exit code %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	e, stdout, stderr := newTestEnv(t)
+	runOK(t, e, "add", "Water plants", "--date", "2026-10-15", "--repeat", "after:3d")
+	id := addedID(t, stdout)
+	if got := e.store.Find(id).Repeat; got != "after:3" {
+		t.Fatalf("repeat = %q, want after:3", got)
+	}
+
+	// Completed on 2026-10-17, so the next occurrence is three days later.
+	runOK(t, e, "done", strconv.Itoa(id))
+	stdout.Reset()
+	runOK(t, e, "history", strconv.Itoa(id))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "[x]\t2026-10-15") || !strings.Contains(lines[1], "[ ]\t2026-10-20") {
+		t.Fatalf("history =\n%s", stdout.String())
+	}
+	next, _ := strconv.Atoi(strings.SplitN(lines[1], "\t", 2)[0])
+	if got := e.store.Find(next); got.Repeat != "after:3" || e.store.Find(id).Repeat != "" {
+		t.Errorf("repeat rule did not move to the next occurrence: %+v", got)
+	}
+
+	runOK(t, e, "edit", strconv.Itoa(next), "--repeat", "weekly:mon")
+	runOK(t, e, "done", strconv.Itoa(next))
+	if n := len(e.store.TodosForDateRange("2026-10-26", "2026-10-26")); n != 1 {
+		t.Errorf("%d todos on Monday 2026-10-26, want the weekly occurrence", n)
+	}
+
+	runOK(t, e, "add", "One-off", "--date", "2026-10-18")
+	stderr.Reset()
+	if code := run([]string{"history", strconv.Itoa(addedID(t, stdout))}, e); code != ExitError {
+		t.Errorf("history of a plain todo: exit code %d, want %d", code, ExitError)
+	}
+	if code := run([]string{"add", "x", "--date", "2026-10", "--repeat", "daily"}, e); code != ExitUsage {
+		t.Errorf("repeat on a month todo: exit code %d, want %d", code, ExitUsage)
+	}
+	if code := run([]string{"add", "x", "--repeat", "after:0"}, e); code != ExitUsage {
+		t.Errorf("repeat after:0: exit code %d, want %d", code, ExitUsage)
+	}
+
+	// RRULEs that count intervals start on the todo's date, or today.
+	runOK(t, e, "add", "Sprint review", "--date", "2026-10-20", "--repeat", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU")
+	if got := e.store.Find(addedID(t, stdout)).Repeat; !strings.Contains(got, "DTSTART=20261020") {
+		t.Errorf("dated repeat = %q, want it anchored on 2026-10-20", got)
+	}
+	runOK(t, e, "add", "Backup", "--repeat", "FREQ=DAILY;INTERVAL=3")
+	if got := e.store.Find(addedID(t, stdout)).Repeat; !strings.Contains(got, "DTSTART=20261017") {
+		t.Errorf("floating repeat = %q, want it anchored on today", got)
+	}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/holidays"
+	"github.com/antti/todo-calendar/internal/ics"
+	"github.com/antti/todo-calendar/internal/todotxt"
+)
+
+// runExport implements "export ics|todotxt [--output FILE] [--dry-run]":
+// the todos in another tool's format, written to stdout or FILE. With
+// --dry-run a todo.txt export prints how FILE would change instead.
+func runExport(e *env, args []string) int {
+	fs := newFlagSet(e, "export")
+	output := fs.String("output", "", "write to this file instead of stdout")
+	dryRun := fs.Bool("dry-run", false, "print the changes to --output instead of writing it (todotxt only)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	var export func(w io.Writer) error
+	switch positional[0] {
+	case "ics":
+		if *dryRun {
+			fmt.Fprintln(e.stderr, "--dry-run is only supported for todotxt")
+			return ExitUsage
+		}
+		var isHoliday func(time.Time) bool
+		if p, err := holidays.NewProvider(e.cfg.Country); err == nil {
+			isHoliday = p.IsHoliday
+		}
+		export = func(w io.Writer) error { return ics.Export(w, e.store, e.now, isHoliday) }
+	case "todotxt":
+		if *dryRun {
+			if *output == "" {
+				fmt.Fprintln(e.stderr, "--dry-run needs --output")
+				return ExitUsage
+			}
+			return exportTodoTxtDryRun(e, *output)
+		}
+		export = func(w io.Writer) error { return todotxt.Export(w, e.store) }
+	default:
+		fmt.Fprintf(e.stderr, "unknown export format %q\n", positional[0])
+		return ExitUsage
+	}
+
+	if *output == "" {
+		err = export(e.stdout)
+	} else {
+		err = exportFile(*output, export)
+	}
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+// exportFile runs export on a newly created file at path.
+func exportFile(path string, export func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportTodoTxtDryRun prints the lines a todo.txt export would remove from
+// and add to the file at path. A missing file counts as empty.
+func exportTodoTxtDryRun(e *env, path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintln(e.stderr, err)
+		return ExitError
+	}
+	var old []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			old = append(old, strings.TrimSuffix(line, "\r"))
+		}
+	}
+	for _, line := range todotxt.Diff(old, todotxt.Lines(e.store)) {
+		fmt.Fprintln(e.stdout, line)
+	}
+	return ExitOK
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// Output formats accepted by --format.
+const (
+	formatPlain = "plain" // human-readable lines (default)
+	formatJSON  = "json"  // a single JSON document
+	formatTSV   = "tsv"   // header row followed by tab-separated records
+)
+
+// formatFlags holds the --json / --format flags shared by all commands
+// that print records.
+type formatFlags struct {
+	json   *bool
+	format *string
+}
+
+// addFormatFlags registers --json and --format on the flag set.
+func addFormatFlags(fs *flag.FlagSet) *formatFlags {
+	return &formatFlags{
+		json:   fs.Bool("json", false, "shorthand for --format json"),
+		format: fs.String("format", formatPlain, "output format: plain, json or tsv"),
+	}
+}
+
+// resolve returns the selected output format, rejecting unknown names and
+// a --json that contradicts an explicit --format.
+func (f *formatFlags) resolve() (string, error) {
+	format := strings.ToLower(strings.TrimSpace(*f.format))
+	switch format {
+	case formatPlain, formatJSON, formatTSV:
+	default:
+		return "", fmt.Errorf("unknown format %q (want plain, json or tsv)", *f.format)
+	}
+	if *f.json {
+		if format != formatPlain && format != formatJSON {
+			return "", fmt.Errorf("--json conflicts with --format %s", format)
+		}
+		format = formatJSON
+	}
+	return format, nil
+}
+
+// output is a command result that can be written in every supported format.
+// JSON is the value to marshal; header and rows make up the TSV table;
+// plain holds the human-readable lines.
+type output struct {
+	json   any
+	header []string
+	rows   [][]string
+	plain  []string
+}
+
+// write renders the output to w in the given format.
+func (o output) write(w io.Writer, format string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(o.json)
+	case formatTSV:
+		if _, err := fmt.Fprintln(w, strings.Join(o.header, "\t")); err != nil {
+			return err
+		}
+		for _, row := range o.rows {
+			cells := make([]string, len(row))
+			for i, c := range row {
+				cells[i] = escapeTSV(c)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		for _, line := range o.plain {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// tsvEscaper escapes the characters that would break a TSV record.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// escapeTSV escapes backslashes, tabs and line breaks in a TSV cell.
+func escapeTSV(s string) string {
+	return tsvEscaper.Replace(s)
+}
+
+// todoHeader is the stable TSV column list for todos: every JSON field of
+// store.Todo, under the same name. New fields are added at the end.
+var todoHeader = []string{"id", "done", "date", "date_precision", "priority", "schedule_id", "created_at", "text", "body", "tags", "project_id", "start_time", "duration", "reminders", "deleted_at", "completed_at", "archived_at", "repeat", "series_id", "uid", "schedule_date", "sort_order"}
+
+// todoOutput builds the output for a list of todos.
+func todoOutput(todos []store.Todo) output {
+	if todos == nil {
+		todos = []store.Todo{}
+	}
+	o := output{json: todos, header: todoHeader}
+	for _, t := range todos {
+		o.rows = append(o.rows, []string{
+			strconv.Itoa(t.ID),
+			strconv.FormatBool(t.Done),
+			t.Date,
+			t.DatePrecision,
+			strconv.Itoa(t.Priority),
+			strconv.Itoa(t.ScheduleID),
+			t.CreatedAt,
+			t.Text,
+			t.Body,
+			strings.Join(t.Tags, ","),
+			strconv.Itoa(t.ProjectID),
+			t.StartTime,
+			strconv.Itoa(t.Duration),
+			joinInts(t.Reminders),
+			t.DeletedAt,
+			t.CompletedAt,
+			t.ArchivedAt,
+			t.Repeat,
+			strconv.Itoa(t.SeriesID),
+			t.UID,
+			t.ScheduleDate,
+			strconv.Itoa(t.SortOrder),
+		})
+		o.plain = append(o.plain, formatTodoLine(t))
+	}
+	return o
+}
+
+// joinInts joins numbers with commas, e.g. reminder offsets in minutes.
+func joinInts(nums []int) string {
+	parts := make([]string, len(nums))
+	for i, n := range nums {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ",")
+}
+
+// writeOutput writes o in the given format, reporting write failures on
+// stderr. It returns the exit code for the command.
+func writeOutput(e *env, format string, o output) int {
+	if err := o.write(e.stdout, format); err != nil {
+		fmt.Fprintf(e.stderr, "write output: %v\n", err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+// resolveFormat validates the format flags, reporting problems on stderr.
+// ok is false when the command should exit with ExitUsage.
+func resolveFormat(e *env, ff *formatFlags) (format string, ok bool) {
+	format, err := ff.resolve()
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return "", false
+	}
+	return format, true
+}
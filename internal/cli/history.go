@@ -0,0 +1,27 @@
+package cli
+
+import "fmt"
+
+// runHistory implements "history <id>": every occurrence of the repeating
+// todo the given todo belongs to, archived ones included, by date.
+func runHistory(e *env, args []string) int {
+	fs := newFlagSet(e, "history")
+	ff := addFormatFlags(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+	todo, code := findTodoArg(e, fs, positional)
+	if todo == nil {
+		return code
+	}
+	if todo.SeriesID == 0 {
+		fmt.Fprintf(e.stderr, "todo %d does not repeat\n", todo.ID)
+		return ExitError
+	}
+	return writeOutput(e, format, todoOutput(e.store.SeriesTodos(todo.SeriesID)))
+}
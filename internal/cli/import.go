@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/antti/todo-calendar/internal/ics"
+	"github.com/antti/todo-calendar/internal/todotxt"
+)
+
+// runImport implements "import ics|todotxt <file> [--dry-run]": todos read
+// from another tool's format. A file of "-" reads stdin. With --dry-run a
+// todo.txt import prints the todos it would add or change, as todo.txt
+// lines, without changing anything.
+func runImport(e *env, args []string) int {
+	fs := newFlagSet(e, "import")
+	dryRun := fs.Bool("dry-run", false, "print the changes instead of making them (todotxt only)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		return ExitUsage
+	}
+	format := positional[0]
+	if format != "ics" && format != "todotxt" {
+		fmt.Fprintf(e.stderr, "unknown import format %q\n", format)
+		return ExitUsage
+	}
+	if *dryRun && format != "todotxt" {
+		fmt.Fprintln(e.stderr, "--dry-run is only supported for todotxt")
+		return ExitUsage
+	}
+
+	r := io.Reader(os.Stdin)
+	if path := positional[1]; path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitError
+		}
+		defer f.Close()
+		r = f
+	}
+
+	if format == "ics" {
+		res, err := ics.Import(r, e.store)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitError
+		}
+		fmt.Fprintf(e.stdout, "%d added, %d updated, %d skipped", res.Added, res.Updated, res.Skipped)
+		if res.Failed > 0 {
+			fmt.Fprintf(e.stdout, ", %d failed", res.Failed)
+		}
+		fmt.Fprintln(e.stdout)
+		return ExitOK
+	}
+
+	changes, err := todotxt.Plan(r, e.store)
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitError
+	}
+	if *dryRun {
+		var res todotxt.Result
+		for _, c := range changes {
+			switch {
+			case c.Unchanged():
+				res.Unchanged++
+				continue
+			case c.Existing == nil:
+				res.Added++
+			default:
+				res.Updated++
+				fmt.Fprintln(e.stdout, "- "+c.Before)
+			}
+			fmt.Fprintln(e.stdout, "+ "+c.After)
+		}
+		fmt.Fprintf(e.stdout, "would add %d, update %d, leave %d unchanged\n", res.Added, res.Updated, res.Unchanged)
+		return ExitOK
+	}
+	res := todotxt.Apply(e.store, changes)
+	fmt.Fprintf(e.stdout, "%d added, %d updated, %d unchanged\n", res.Added, res.Updated, res.Unchanged)
+	return ExitOK
+}
@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/google"
+	"github.com/antti/todo-calendar/internal/recurring"
+	"github.com/antti/todo-calendar/internal/status"
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// statusRecord is the JSON shape of the status command.
+type statusRecord struct {
+	Date      string `json:"date"`
+	Pending   int    `json:"pending"`
+	Completed int    `json:"completed"`
+}
+
+// runStatus implements "status": today's pending and completed counts.
+// Plain output matches --status (the pending count, or nothing when zero)
+// so it can feed status bars such as Polybar.
+func runStatus(e *env, args []string) int {
+	fs := newFlagSet(e, "status")
+	ff := addFormatFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+
+	today := e.now.Format("2006-01-02")
+	todos := e.store.TodosForDateRange(today, today)
+	if format == formatPlain {
+		fmt.Fprint(e.stdout, status.FormatStatus(todos))
+		return ExitOK
+	}
+
+	rec := statusRecord{Date: today}
+	for _, t := range todos {
+		if t.Done {
+			rec.Completed++
+		} else {
+			rec.Pending++
+		}
+	}
+	return writeOutput(e, format, output{
+		json:   rec,
+		header: []string{"date", "pending", "completed"},
+		rows:   [][]string{{rec.Date, strconv.Itoa(rec.Pending), strconv.Itoa(rec.Completed)}},
+	})
+}
+
+// runTemplates implements "templates": all templates ordered by name.
+func runTemplates(e *env, args []string) int {
+	fs := newFlagSet(e, "templates")
+	ff := addFormatFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+
+	templates := e.store.ListTemplates()
+	o := output{json: templates, header: []string{"id", "name", "created_at", "content"}}
+	for _, t := range templates {
+		o.rows = append(o.rows, []string{strconv.Itoa(t.ID), t.Name, t.CreatedAt, t.Content})
+		o.plain = append(o.plain, fmt.Sprintf("%d\t%s", t.ID, t.Name))
+	}
+	return writeOutput(e, format, o)
+}
+
+// projectRecord is the JSON shape of the projects command.
+type projectRecord struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Pending   int    `json:"pending"`
+	Completed int    `json:"completed"`
+}
+
+// runProjects implements "projects": all projects with their todo counts.
+func runProjects(e *env, args []string) int {
+	fs := newFlagSet(e, "projects")
+	ff := addFormatFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+
+	records := []projectRecord{}
+	o := output{header: []string{"id", "name", "pending", "completed"}}
+	for _, pc := range e.store.ProjectCounts() {
+		rec := projectRecord{ID: pc.ProjectID, Name: pc.Name, Pending: pc.Pending, Completed: pc.Completed}
+		records = append(records, rec)
+		o.rows = append(o.rows, []string{strconv.Itoa(rec.ID), rec.Name, strconv.Itoa(rec.Pending), strconv.Itoa(rec.Completed)})
+		o.plain = append(o.plain, fmt.Sprintf("%d\t%s\t%d pending\t%d done", rec.ID, rec.Name, rec.Pending, rec.Completed))
+	}
+	o.json = records
+	return writeOutput(e, format, o)
+}
+
+// runSchedules implements "schedules": all recurring schedules ordered by ID.
+func runSchedules(e *env, args []string) int {
+	fs := newFlagSet(e, "schedules")
+	ff := addFormatFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+
+	schedules := e.store.ListSchedules()
+	if schedules == nil {
+		schedules = []store.Schedule{}
+	}
+	o := output{json: schedules, header: []string{"id", "template_id", "cadence_type", "cadence_value", "placeholder_defaults", "created_at", "holiday_policy", "lookahead_days", "catch_up", "generated_through", "start_date", "end_date", "max_occurrences", "paused", "exceptions"}}
+	for _, sc := range schedules {
+		o.rows = append(o.rows, []string{
+			strconv.Itoa(sc.ID), strconv.Itoa(sc.TemplateID), sc.CadenceType,
+			sc.CadenceValue, sc.PlaceholderDefaults, sc.CreatedAt, sc.HolidayPolicy,
+			strconv.Itoa(sc.LookaheadDays), sc.CatchUp, sc.GeneratedThrough,
+			sc.StartDate, sc.EndDate, strconv.Itoa(sc.MaxOccurrences), strconv.FormatBool(sc.Paused), strings.Join(sc.Exceptions, ","),
+		})
+		name := "(deleted template)"
+		if tpl := e.store.FindTemplate(sc.TemplateID); tpl != nil {
+			name = tpl.Name
+		}
+		cadence := sc.CadenceType
+		if sc.CadenceValue != "" {
+			cadence += ":" + sc.CadenceValue
+		}
+		var notes []string
+		if sc.HolidayPolicy != recurring.HolidayCreate {
+			notes = append(notes, recurring.HolidayPolicyLabel(sc.HolidayPolicy))
+		}
+		if n := recurring.Lookahead(sc); n != recurring.DefaultLookahead {
+			notes = append(notes, fmt.Sprintf("%d days ahead", n))
+		}
+		if sc.CatchUp != recurring.CatchUpNone {
+			notes = append(notes, recurring.CatchUpLabel(sc.CatchUp))
+		}
+		if sc.StartDate != "" {
+			notes = append(notes, "from "+sc.StartDate)
+		}
+		if sc.EndDate != "" {
+			notes = append(notes, "until "+sc.EndDate)
+		}
+		if sc.MaxOccurrences > 0 {
+			notes = append(notes, fmt.Sprintf("%d times", sc.MaxOccurrences))
+		}
+		if len(sc.Exceptions) > 0 {
+			notes = append(notes, fmt.Sprintf("%d skipped", len(sc.Exceptions)))
+		}
+		if sc.Paused {
+			notes = append(notes, "paused")
+		}
+		if len(notes) > 0 {
+			cadence += " (" + strings.Join(notes, ", ") + ")"
+		}
+		o.plain = append(o.plain, fmt.Sprintf("%d\t%s\t%s", sc.ID, cadence, name))
+	}
+	return writeOutput(e, format, o)
+}
+
+// eventRecord is the JSON shape of a Google Calendar event. Times are
+// RFC 3339 strings and are omitted for all-day events.
+type eventRecord struct {
+	ID        string `json:"id"`
+	Summary   string `json:"summary"`
+	Date      string `json:"date"`
+	EndDate   string `json:"end_date,omitempty"`
+	Start     string `json:"start,omitempty"`
+	End       string `json:"end,omitempty"`
+	AllDay    bool   `json:"all_day"`
+	Recurring bool   `json:"recurring"`
+	Status    string `json:"status"`
+}
+
+// newEventRecord converts a fetched event into its output record.
+func newEventRecord(ev google.CalendarEvent) eventRecord {
+	rec := eventRecord{
+		ID:        ev.ID,
+		Summary:   ev.Summary,
+		Date:      ev.Date,
+		EndDate:   ev.EndDate,
+		AllDay:    ev.AllDay,
+		Recurring: ev.Recurring,
+		Status:    ev.Status,
+	}
+	if !ev.Start.IsZero() {
+		rec.Start = ev.Start.Format(time.RFC3339)
+	}
+	if !ev.End.IsZero() {
+		rec.End = ev.End.Format(time.RFC3339)
+	}
+	return rec
+}
+
+// fetchGoogleEvents fetches events from the connected Google Calendar.
+func fetchGoogleEvents() ([]google.CalendarEvent, error) {
+	if google.CheckAuthState() != google.AuthReady {
+		return nil, fmt.Errorf("Google Calendar is not connected (sign in from the settings overlay)")
+	}
+	srv, err := google.NewCalendarService()
+	if err != nil {
+		return nil, fmt.Errorf("create calendar service: %w", err)
+	}
+	events, _, err := google.FetchEvents(srv, "")
+	if err != nil {
+		return nil, fmt.Errorf("fetch events: %w", err)
+	}
+	return events, nil
+}
+
+// runEvents implements "events [--from DATE] [--to DATE]": Google Calendar
+// events from the sync window (one month back to three months ahead).
+func runEvents(e *env, args []string) int {
+	fs := newFlagSet(e, "events")
+	from := fs.String("from", "", "first date of the range (YYYY-MM-DD)")
+	to := fs.String("to", "", "last date of the range (YYYY-MM-DD)")
+	ff := addFormatFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+	var start, end string
+	if *from != "" || *to != "" {
+		var err error
+		start, end, err = parseRange(*from, *to, e)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitUsage
+		}
+	}
+
+	events, err := e.fetchEvents()
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitError
+	}
+
+	records := []eventRecord{}
+	o := output{header: []string{"id", "date", "end_date", "start", "end", "all_day", "recurring", "status", "summary"}}
+	for _, ev := range events {
+		if ev.Status == "cancelled" {
+			continue
+		}
+		if start != "" && (ev.Date < start || ev.Date > end) {
+			continue
+		}
+		rec := newEventRecord(ev)
+		records = append(records, rec)
+		o.rows = append(o.rows, []string{
+			rec.ID, rec.Date, rec.EndDate, rec.Start, rec.End,
+			strconv.FormatBool(rec.AllDay), strconv.FormatBool(rec.Recurring), rec.Status, rec.Summary,
+		})
+		when := "all day"
+		if !ev.AllDay {
+			when = ev.Start.Local().Format("15:04")
+		}
+		o.plain = append(o.plain, fmt.Sprintf("%s\t%s\t%s", rec.Date, when, rec.Summary))
+	}
+	o.json = records
+	return writeOutput(e, format, o)
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/antti/todo-calendar/internal/mdnotes"
+)
+
+// runNotes implements "notes export|import|sync [--dir DIR] [--period
+// day|month]": dated todos written into markdown notes, checkbox changes
+// made in the notes read back, or both (read first, then write).
+func runNotes(e *env, args []string) int {
+	fs := newFlagSet(e, "notes")
+	dir := fs.String("dir", e.cfg.NotesPath(), "notes directory (default notes_dir)")
+	period := fs.String("period", e.cfg.NotesPeriod, "one note per day or month")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	action := positional[0]
+	if action != "export" && action != "import" && action != "sync" {
+		fmt.Fprintf(e.stderr, "unknown notes action %q\n", action)
+		return ExitUsage
+	}
+	if *period != "day" && *period != "month" {
+		fmt.Fprintf(e.stderr, "invalid period %q (want day or month)\n", *period)
+		return ExitUsage
+	}
+
+	if action == "import" || action == "sync" {
+		n, err := mdnotes.Import(e.store, *dir)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitError
+		}
+		fmt.Fprintf(e.stdout, "%d todos updated\n", n)
+	}
+	if action == "export" || action == "sync" {
+		res, err := mdnotes.Export(e.store, *dir, *period)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitError
+		}
+		fmt.Fprintf(e.stdout, "%d notes written, %d removed\n", res.Written, res.Removed)
+	}
+	return ExitOK
+}
@@ -0,0 +1,32 @@
+package cli
+
+import "fmt"
+
+// runOverdue implements "overdue [--rollover]": incomplete todos dated
+// before today, oldest first. With --rollover they are moved to today (or
+// today's month or year, for fuzzy dates) as one undoable change instead.
+func runOverdue(e *env, args []string) int {
+	fs := newFlagSet(e, "overdue")
+	rollover := fs.Bool("rollover", false, "move overdue todos to today")
+	ff := addFormatFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+
+	today := e.now.Format("2006-01-02")
+	overdue := e.store.OverdueTodos(today)
+	if *rollover {
+		e.store.Batch("rollover", func() {
+			for _, t := range overdue {
+				e.store.RollOver(t.ID, today)
+			}
+		})
+		fmt.Fprintf(e.stdout, "%d todos rolled over\n", len(overdue))
+		return ExitOK
+	}
+	return writeOutput(e, format, todoOutput(overdue))
+}
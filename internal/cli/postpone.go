@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/holidays"
+	"github.com/antti/todo-calendar/internal/postpone"
+)
+
+// runPostpone implements "postpone <id> [--by day|weekday|week|month] [--to
+// DATE]": the todo moves by a step, by default one day, or to the given
+// date. Steps keep month and year todos at their precision and skip
+// holidays when postpone_skip_holidays is set. The new date is printed.
+func runPostpone(e *env, args []string) int {
+	fs := newFlagSet(e, "postpone")
+	by := fs.String("by", "day", "step: day, weekday, week or month")
+	to := fs.String("to", "", "date to postpone to (YYYY-MM-DD, YYYY-MM, YYYY, tomorrow, fri, +2w, ...)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	todo, code := findTodoArg(e, fs, positional)
+	if todo == nil {
+		return code
+	}
+	if todo.Done {
+		fmt.Fprintf(e.stderr, "todo %d is completed\n", todo.ID)
+		return ExitError
+	}
+
+	var date, precision string
+	if *to != "" {
+		if flagWasSet(fs, "by") {
+			fmt.Fprintln(e.stderr, "--by and --to cannot be combined")
+			return ExitUsage
+		}
+		date, precision, err = postpone.Parse(*to, e.now, e.cfg.DateLayout())
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitUsage
+		}
+	} else {
+		step, ok := postpone.ParseStep(*by)
+		if !ok {
+			fmt.Fprintf(e.stderr, "invalid step %q (want day, weekday, week or month)\n", *by)
+			return ExitUsage
+		}
+		var skip func(time.Time) bool
+		if e.cfg.PostponeSkipHolidays {
+			if p, err := holidays.NewProvider(e.cfg.Country); err == nil {
+				skip = p.IsHoliday
+			}
+		}
+		date, precision = postpone.Date(*todo, step, e.now, skip)
+	}
+
+	e.store.Batch("postpone", func() {
+		e.store.Update(todo.ID, todo.Text, date, precision, todo.Priority)
+	})
+	fmt.Fprintf(e.stdout, "%d\t%s\n", todo.ID, date)
+	return ExitOK
+}
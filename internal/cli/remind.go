@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/remind"
+)
+
+// runRemind implements "remind [--once] [--interval D] [--notifier KIND] [--command CMD]".
+// It delivers due reminders, checking every interval until interrupted;
+// with --once it checks a single time, which suits cron and systemd timers.
+func runRemind(e *env, args []string) int {
+	fs := newFlagSet(e, "remind")
+	once := fs.Bool("once", false, "check once and exit")
+	interval := fs.Duration("interval", time.Minute, "time between checks")
+	kind := fs.String("notifier", e.cfg.ReminderNotifier, "notify-send, command or stdout")
+	command := fs.String("command", e.cfg.ReminderCommand, "shell command for --notifier command")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(e.stderr, "invalid interval %s\n", *interval)
+		return ExitUsage
+	}
+	n, err := remind.New(*kind, *command, e.stdout)
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitUsage
+	}
+
+	if *once {
+		if _, err := remind.Check(e.store, n, e.now, e.cfg.ReminderTime); err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitError
+		}
+		return ExitOK
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	remind.Run(ctx, e.store, n, *interval, e.cfg.ReminderTime, func(err error) {
+		fmt.Fprintln(e.stderr, err)
+	})
+	return ExitOK
+}
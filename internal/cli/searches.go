@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antti/todo-calendar/internal/query"
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// savedSearchRecord is the JSON shape of the searches command.
+type savedSearchRecord struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	Pending   int    `json:"pending"`
+	Completed int    `json:"completed"`
+}
+
+// runSearches implements "searches [save NAME QUERY | rm NAME | show
+// NAME]": without an action the saved searches are listed with their
+// todo counts; show prints the todos a saved search matches.
+func runSearches(e *env, args []string) int {
+	fs := newFlagSet(e, "searches")
+	ff := addFormatFlags(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+	if len(positional) == 0 {
+		return listSavedSearches(e, format)
+	}
+
+	action, rest := positional[0], positional[1:]
+	switch {
+	case action == "save" && len(rest) >= 2:
+		ss, err := e.store.SaveSearch(rest[0], strings.Join(rest[1:], " "))
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitUsage
+		}
+		fmt.Fprintf(e.stdout, "%d\t%s\n", ss.ID, ss.Name)
+		return ExitOK
+	case action == "rm" && len(rest) == 1:
+		ss := findSavedSearch(e, rest[0])
+		if ss == nil {
+			return ExitNotFound
+		}
+		e.store.DeleteSavedSearch(ss.ID)
+		return ExitOK
+	case action == "show" && len(rest) == 1:
+		ss := findSavedSearch(e, rest[0])
+		if ss == nil {
+			return ExitNotFound
+		}
+		q, err := query.Parse(ss.Query, e.now)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "saved search %q: %v\n", ss.Name, err)
+			return ExitError
+		}
+		var todos []store.Todo
+		for _, r := range e.store.QueryTodos(q) {
+			todos = append(todos, r.Todo)
+		}
+		return writeOutput(e, format, todoOutput(todos))
+	}
+	fs.Usage()
+	return ExitUsage
+}
+
+// listSavedSearches writes the saved searches with their todo counts.
+func listSavedSearches(e *env, format string) int {
+	counts := make(map[int]store.SavedSearchCount)
+	for _, c := range e.store.SavedSearchCounts() {
+		counts[c.SearchID] = c
+	}
+
+	records := []savedSearchRecord{}
+	o := output{header: []string{"id", "name", "query", "pending", "completed"}}
+	for _, ss := range e.store.ListSavedSearches() {
+		c := counts[ss.ID]
+		rec := savedSearchRecord{ID: ss.ID, Name: ss.Name, Query: ss.Query, Pending: c.Pending, Completed: c.Completed}
+		records = append(records, rec)
+		o.rows = append(o.rows, []string{strconv.Itoa(rec.ID), rec.Name, rec.Query, strconv.Itoa(rec.Pending), strconv.Itoa(rec.Completed)})
+		o.plain = append(o.plain, fmt.Sprintf("%d\t%s\t%s\t%d pending\t%d done", rec.ID, rec.Name, rec.Query, rec.Pending, rec.Completed))
+	}
+	o.json = records
+	return writeOutput(e, format, o)
+}
+
+// findSavedSearch looks up a saved search by name, ignoring case, and
+// reports a missing one on stderr.
+func findSavedSearch(e *env, name string) *store.SavedSearch {
+	for _, ss := range e.store.ListSavedSearches() {
+		if strings.EqualFold(ss.Name, name) {
+			return &ss
+		}
+	}
+	fmt.Fprintf(e.stderr, "saved search %q not found\n", name)
+	return nil
+}
@@ -0,0 +1,421 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antti/todo-calendar/internal/checklist"
+	"github.com/antti/todo-calendar/internal/recurring"
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// runAdd implements "add <text> [--date DATE] [--time HH:MM] [--duration D] [--remind LIST] [--repeat RULE] [--priority N] [--body TEXT] [--tags LIST] [--project NAME]".
+// It prints the new todo's ID (or the whole todo with --json / --format).
+func runAdd(e *env, args []string) int {
+	fs := newFlagSet(e, "add")
+	date := fs.String("date", "", "due date (YYYY-MM-DD, YYYY-MM, YYYY, today, tomorrow)")
+	clock := fs.String("time", "", "start time HH:MM (needs a day date)")
+	duration := fs.String("duration", "", "duration in minutes or e.g. 1h30m (needs --time)")
+	reminders := fs.String("remind", "", "reminders before due, e.g. 15m,1h,1d (needs a day date)")
+	repeat := fs.String("repeat", "", "repeat rule, e.g. weekly:mon or after:3 (days after completion)")
+	priority := fs.Int("priority", 0, "priority 1-4 (0 = none)")
+	body := fs.String("body", "", "markdown body")
+	tags := fs.String("tags", "", "comma-separated tags")
+	project := fs.String("project", "", "project name (created if missing)")
+	ff := addFormatFlags(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+
+	text := strings.TrimSpace(strings.Join(positional, " "))
+	if text == "" {
+		fs.Usage()
+		return ExitUsage
+	}
+	if !validPriority(*priority) {
+		fmt.Fprintf(e.stderr, "invalid priority %d (want 0-4)\n", *priority)
+		return ExitUsage
+	}
+	isoDate, precision, err := parseDate(*date, e.cfg.DateLayout(), e.now)
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitUsage
+	}
+	startTime, minutes, err := parseTime(*clock, *duration, precision)
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitUsage
+	}
+	offsets, err := parseReminders(*reminders, precision)
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitUsage
+	}
+	rule, err := parseRepeat(*repeat, isoDate, precision, e.now)
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitUsage
+	}
+	projectID, err := resolveProject(e, *project)
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitError
+	}
+
+	var todo store.Todo
+	e.store.Batch("add", func() {
+		todo = e.store.Add(text, isoDate, precision, *priority)
+		if todo.ID == 0 {
+			return
+		}
+		if startTime != "" {
+			e.store.SetTime(todo.ID, startTime, minutes)
+		}
+		if len(offsets) > 0 {
+			e.store.SetReminders(todo.ID, offsets)
+		}
+		if rule != "" {
+			e.store.SetRepeat(todo.ID, rule)
+		}
+		if *body != "" {
+			e.store.UpdateBody(todo.ID, *body)
+		}
+		if names := store.ParseTags(*tags); len(names) > 0 {
+			e.store.SetTags(todo.ID, names)
+		}
+		if projectID != 0 {
+			e.store.SetProject(todo.ID, projectID)
+		}
+	})
+	if todo.ID == 0 {
+		fmt.Fprintln(e.stderr, "could not add todo")
+		return ExitError
+	}
+	if fresh := e.store.Find(todo.ID); fresh != nil {
+		todo = *fresh
+	}
+
+	// Plain output is just the new ID so scripts can capture it with $(...).
+	o := todoOutput([]store.Todo{todo})
+	o.json = todo
+	o.plain = []string{strconv.Itoa(todo.ID)}
+	return writeOutput(e, format, o)
+}
+
+// runList implements "list [--from DATE] [--to DATE] [--tag LIST] [--project NAME]".
+// Without a range every todo is listed; with a range only day-precision
+// todos whose date falls inside it are listed. --tag keeps todos carrying
+// all of the given tags and --project those in the named project.
+func runList(e *env, args []string) int {
+	fs := newFlagSet(e, "list")
+	from := fs.String("from", "", "first date of the range (YYYY-MM-DD)")
+	to := fs.String("to", "", "last date of the range (YYYY-MM-DD)")
+	tag := fs.String("tag", "", "only todos with these tags (comma-separated)")
+	project := fs.String("project", "", "only todos in this project")
+	ff := addFormatFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+
+	var todos []store.Todo
+	if *from == "" && *to == "" {
+		todos = e.store.Todos()
+	} else {
+		start, end, err := parseRange(*from, *to, e)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitUsage
+		}
+		todos = e.store.TodosForDateRange(start, end)
+	}
+	projectID := 0
+	if *project != "" {
+		p := e.store.FindProjectByName(*project)
+		if p == nil {
+			fmt.Fprintf(e.stderr, "project %q not found\n", *project)
+			return ExitNotFound
+		}
+		projectID = p.ID
+	}
+	tags := store.ParseTags(*tag)
+	if len(tags) > 0 || projectID != 0 {
+		var matched []store.Todo
+		for _, t := range todos {
+			if t.HasAllTags(tags) && (projectID == 0 || t.ProjectID == projectID) {
+				matched = append(matched, t)
+			}
+		}
+		todos = matched
+	}
+
+	return writeOutput(e, format, todoOutput(todos))
+}
+
+// parseRange resolves --from/--to into an inclusive ISO date range.
+// A missing bound defaults to the other one, so "--from X" lists a single day.
+func parseRange(from, to string, e *env) (string, string, error) {
+	if from == "" {
+		from = to
+	}
+	if to == "" {
+		to = from
+	}
+	start, precision, err := parseDate(from, e.cfg.DateLayout(), e.now)
+	if err != nil {
+		return "", "", err
+	}
+	if precision != "day" {
+		return "", "", fmt.Errorf("--from must be a full date")
+	}
+	end, precision, err := parseDate(to, e.cfg.DateLayout(), e.now)
+	if err != nil {
+		return "", "", err
+	}
+	if precision != "day" {
+		return "", "", fmt.Errorf("--to must be a full date")
+	}
+	if end < start {
+		return "", "", fmt.Errorf("--to %s is before --from %s", end, start)
+	}
+	return start, end, nil
+}
+
+// runDone implements "done <id>". Completing an already completed todo is a no-op.
+func runDone(e *env, args []string) int {
+	fs := newFlagSet(e, "done")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	todo, code := findTodoArg(e, fs, positional)
+	if todo == nil {
+		return code
+	}
+	if !todo.Done {
+		recurring.Toggle(e.store, todo.ID, e.now)
+	}
+	return ExitOK
+}
+
+// runEdit implements "edit <id> [--text TEXT] [--date DATE] [--time HH:MM] [--duration D] [--remind LIST] [--repeat RULE] [--priority N] [--body TEXT] [--tags LIST] [--project NAME]".
+// Only the flags that are given change; --date "" makes the todo floating,
+// --time "" makes it untimed, --remind "" removes its reminders, --repeat ""
+// stops it repeating, --tags ""
+// removes all tags and --project "" removes it from its project.
+func runEdit(e *env, args []string) int {
+	fs := newFlagSet(e, "edit")
+	text := fs.String("text", "", "new title")
+	date := fs.String("date", "", "new date (empty for floating)")
+	clock := fs.String("time", "", "new start time HH:MM (empty for untimed)")
+	duration := fs.String("duration", "", "new duration in minutes or e.g. 1h30m")
+	reminders := fs.String("remind", "", "new reminders, e.g. 15m,1h,1d (replaces existing)")
+	repeat := fs.String("repeat", "", "new repeat rule, e.g. weekly:mon or after:3 (empty to stop)")
+	priority := fs.Int("priority", 0, "new priority 1-4 (0 = none)")
+	body := fs.String("body", "", "new markdown body")
+	tags := fs.String("tags", "", "new comma-separated tags (replaces existing)")
+	project := fs.String("project", "", "new project name (created if missing)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	todo, code := findTodoArg(e, fs, positional)
+	if todo == nil {
+		return code
+	}
+
+	newText := todo.Text
+	if flagWasSet(fs, "text") {
+		newText = strings.TrimSpace(*text)
+		if newText == "" {
+			fmt.Fprintln(e.stderr, "--text must not be empty")
+			return ExitUsage
+		}
+	}
+	newDate, newPrecision := todo.Date, todo.DatePrecision
+	if flagWasSet(fs, "date") {
+		newDate, newPrecision, err = parseDate(*date, e.cfg.DateLayout(), e.now)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitUsage
+		}
+	}
+	timeChanged := flagWasSet(fs, "time") || flagWasSet(fs, "duration")
+	newTime, newDuration := todo.StartTime, todo.Duration
+	if timeChanged {
+		if flagWasSet(fs, "time") {
+			newTime = *clock
+		}
+		// Clearing the time drops the duration unless one is given.
+		durationArg := ""
+		if newTime != "" {
+			durationArg = store.FormatDuration(newDuration)
+		}
+		if flagWasSet(fs, "duration") {
+			durationArg = *duration
+		}
+		newTime, newDuration, err = parseTime(newTime, durationArg, newPrecision)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitUsage
+		}
+	}
+	var offsets []int
+	if flagWasSet(fs, "remind") {
+		offsets, err = parseReminders(*reminders, newPrecision)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitUsage
+		}
+	}
+	rule := todo.Repeat
+	if flagWasSet(fs, "repeat") {
+		rule, err = parseRepeat(*repeat, newDate, newPrecision, e.now)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitUsage
+		}
+	} else if rule != "" && newPrecision != "day" && newPrecision != "" {
+		fmt.Fprintln(e.stderr, "repeating todos need a day date (YYYY-MM-DD) or none")
+		return ExitUsage
+	}
+	newPriority := todo.Priority
+	if flagWasSet(fs, "priority") {
+		if !validPriority(*priority) {
+			fmt.Fprintf(e.stderr, "invalid priority %d (want 0-4)\n", *priority)
+			return ExitUsage
+		}
+		newPriority = *priority
+	}
+	newProjectID := todo.ProjectID
+	if flagWasSet(fs, "project") {
+		newProjectID, err = resolveProject(e, *project)
+		if err != nil {
+			fmt.Fprintln(e.stderr, err)
+			return ExitError
+		}
+	}
+
+	e.store.Batch("edit", func() {
+		e.store.Update(todo.ID, newText, newDate, newPrecision, newPriority)
+		if timeChanged {
+			e.store.SetTime(todo.ID, newTime, newDuration)
+		}
+		if flagWasSet(fs, "remind") {
+			e.store.SetReminders(todo.ID, offsets)
+		}
+		if rule != todo.Repeat {
+			e.store.SetRepeat(todo.ID, rule)
+		}
+		if flagWasSet(fs, "body") {
+			e.store.UpdateBody(todo.ID, *body)
+			if !todo.Done && checklist.AllDone(*body) {
+				recurring.Toggle(e.store, todo.ID, e.now)
+			}
+		}
+		if flagWasSet(fs, "tags") {
+			e.store.SetTags(todo.ID, store.ParseTags(*tags))
+		}
+		if newProjectID != todo.ProjectID {
+			e.store.SetProject(todo.ID, newProjectID)
+		}
+	})
+	return ExitOK
+}
+
+// runRm implements "rm <id>".
+func runRm(e *env, args []string) int {
+	fs := newFlagSet(e, "rm")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	todo, code := findTodoArg(e, fs, positional)
+	if todo == nil {
+		return code
+	}
+	e.store.Delete(todo.ID)
+	return ExitOK
+}
+
+// resolveProject returns the ID of the named project, creating it if it
+// does not exist yet. An empty name resolves to 0 (no project).
+func resolveProject(e *env, name string) (int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, nil
+	}
+	if p := e.store.FindProjectByName(name); p != nil {
+		return p.ID, nil
+	}
+	p, err := e.store.AddProject(name)
+	if err != nil {
+		return 0, err
+	}
+	return p.ID, nil
+}
+
+// findTodoArg resolves the single <id> positional argument to a todo.
+// On failure it reports the problem and returns the exit code to use.
+func findTodoArg(e *env, fs *flag.FlagSet, positional []string) (*store.Todo, int) {
+	if len(positional) != 1 {
+		fs.Usage()
+		return nil, ExitUsage
+	}
+	id, err := parseID(positional[0])
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return nil, ExitUsage
+	}
+	todo := e.store.Find(id)
+	if todo == nil {
+		fmt.Fprintf(e.stderr, "todo %d not found\n", id)
+		return nil, ExitNotFound
+	}
+	return todo, ExitOK
+}
+
+// formatTodoLine renders a todo as a single tab-separated line:
+// id, checkbox, date (trimmed to its precision), priority label, text
+// followed by any "#tag" chips.
+func formatTodoLine(t store.Todo) string {
+	check := "[ ]"
+	if t.Done {
+		check = "[x]"
+	}
+	date := t.Date
+	if t.HasTime() {
+		date += " " + t.TimeLabel()
+	}
+	switch {
+	case t.IsMonthPrecision() && len(date) >= 7:
+		date = date[:7]
+	case t.IsYearPrecision() && len(date) >= 4:
+		date = date[:4]
+	}
+	if date == "" {
+		date = "-"
+	}
+	prio := t.PriorityLabel()
+	if prio == "" {
+		prio = "-"
+	}
+	text := t.Text
+	if done, total := checklist.Progress(t.Body); total > 0 {
+		text += fmt.Sprintf(" (%d/%d)", done, total)
+	}
+	for _, tag := range t.Tags {
+		text += " #" + tag
+	}
+	return fmt.Sprintf("%d\t%s\t%s\t%s\t%s", t.ID, check, date, prio, text)
+}
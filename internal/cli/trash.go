@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// runTrash implements "trash [--empty]": the trashed todos, most recently
+// deleted first. --empty deletes all of them permanently instead.
+func runTrash(e *env, args []string) int {
+	fs := newFlagSet(e, "trash")
+	empty := fs.Bool("empty", false, "delete all trashed todos permanently")
+	ff := addFormatFlags(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+	format, ok := resolveFormat(e, ff)
+	if !ok {
+		return ExitUsage
+	}
+
+	if *empty {
+		// Include todos trashed within the current second.
+		n := e.store.PurgeTrash(time.Now().Add(time.Second))
+		fmt.Fprintf(e.stdout, "%d todos deleted\n", n)
+		return ExitOK
+	}
+	return writeOutput(e, format, todoOutput(e.store.TrashedTodos()))
+}
+
+// runRestore implements "restore <id>" and "restore --backup FILE", the
+// latter in backup.go.
+func runRestore(e *env, args []string) int {
+	fs := newFlagSet(e, "restore")
+	backup := fs.String("backup", "", "replace all data with this backup file (- for stdin)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if *backup != "" && len(positional) == 0 {
+		return restoreBackup(e, *backup)
+	}
+	if *backup != "" || len(positional) != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	id, err := parseID(positional[0])
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return ExitUsage
+	}
+	if !inTrash(e.store, id) {
+		fmt.Fprintf(e.stderr, "todo %d is not in the trash\n", id)
+		return ExitNotFound
+	}
+	e.store.Restore(id)
+	return ExitOK
+}
+
+// inTrash reports whether the todo with the given ID is in the trash.
+func inTrash(s store.TodoStore, id int) bool {
+	for _, t := range s.TrashedTodos() {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
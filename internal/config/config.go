@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -19,6 +20,15 @@ type Config struct {
 	ShowYearTodos          bool   `toml:"show_year_todos"`
 	PriorityStyle          string `toml:"priority_style"`
 	GoogleCalendarEnabled  bool   `toml:"google_calendar_enabled"`
+	RemindersEnabled       bool   `toml:"reminders_enabled"` // check reminders while the TUI runs
+	ReminderNotifier       string `toml:"reminder_notifier"` // "notify-send", "command" or "stdout"
+	ReminderCommand        string `toml:"reminder_command"`  // shell command for the "command" notifier
+	ReminderTime           string `toml:"reminder_time"`     // due time of untimed todos ("HH:MM")
+	TrashRetentionDays     int    `toml:"trash_retention_days"` // purge trashed todos after this many days; 0 = never
+	ArchiveAfterDays       int    `toml:"archive_after_days"`   // archive completed todos after this many days; 0 = never
+	NotesDir               string `toml:"notes_dir"`            // markdown notes vault for "notes export"
+	NotesPeriod            string `toml:"notes_period"`         // one notes file per "day" or "month"
+	PostponeSkipHolidays   bool   `toml:"postpone_skip_holidays"` // postponed todos skip the country's holidays
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -32,6 +42,11 @@ func DefaultConfig() Config {
 		ShowYearTodos:         true,
 		PriorityStyle:        "bars",
 		GoogleCalendarEnabled: true,
+		RemindersEnabled:      true,
+		ReminderNotifier:      "notify-send",
+		ReminderTime:          "09:00",
+		TrashRetentionDays:    30,
+		NotesPeriod:           "day",
 	}
 }
 
@@ -40,6 +55,17 @@ func (c Config) MondayStart() bool {
 	return c.FirstDayOfWeek == "monday"
 }
 
+// NotesPath returns NotesDir with a leading "~/" expanded to the home
+// directory.
+func (c Config) NotesPath() string {
+	if rest, ok := strings.CutPrefix(c.NotesDir, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return c.NotesDir
+}
+
 // DateLayout returns the Go time layout string for the configured date format.
 func (c Config) DateLayout() string {
 	switch c.DateFormat {
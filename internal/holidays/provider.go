@@ -45,6 +45,13 @@ func (p *Provider) HolidaysInMonth(year int, month time.Month) map[int]bool {
 	return result
 }
 
+// IsHoliday reports whether the given date is a holiday, counting days
+// on which a holiday is observed.
+func (p *Provider) IsHoliday(date time.Time) bool {
+	actual, observed, _ := p.cal.IsHoliday(time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.Local))
+	return actual || observed
+}
+
 // Country returns the country code for this provider.
 func (p *Provider) Country() string {
 	return p.country
@@ -0,0 +1,306 @@
+// Package ics writes todos and recurring schedules as iCalendar (RFC 5545)
+// VTODO components for use in other calendar tools.
+package ics
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/antti/todo-calendar/internal/recurring"
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+const (
+	dateFormat      = "2006-01-02"
+	timestampFormat = "2006-01-02 15:04:05" // store completed_at layout
+
+	icsDate     = "20060102"
+	icsDateTime = "20060102T150405"
+	icsUTC      = "20060102T150405Z"
+
+	// lineLimit is the maximum length of a content line in octets,
+	// excluding the line break.
+	lineLimit = 75
+
+	// holidayOccurrences bounds the occurrences a schedule's holiday
+	// policy is applied to in the export.
+	holidayOccurrences = 5000
+)
+
+// Export writes the dated todos of the store as VTODOs, and each template
+// schedule as a recurring VTODO. Todos created by an exported schedule are
+// written as instances of it (sharing its UID, with a RECURRENCE-ID), so
+// calendar tools show them in place of the generated occurrence. Paused
+// schedules are left out, and their todos written as plain todos. Imported
+// todos keep their original UID; other todos and schedules get one made of
+// their ID and the store's DatabaseID. Times are written as floating local
+// times. now stamps every component. Schedule holiday policies use the
+// holidays isHoliday reports (nil for none).
+func Export(w io.Writer, s store.TodoStore, now time.Time, isHoliday func(time.Time) bool) error {
+	e := &encoder{w: bufio.NewWriter(w), stamp: now.UTC().Format(icsUTC), now: now, isHoliday: isHoliday, database: s.DatabaseID()}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:-//todo-calendar//EN")
+	e.line("CALSCALE:GREGORIAN")
+
+	series := make(map[int]exported)
+	for _, sched := range s.ListSchedules() {
+		if sched.Paused {
+			continue
+		}
+		rule, title, body, ok := recurring.Resolve(s, sched)
+		if !ok {
+			continue
+		}
+		series[sched.ID] = exported{sched, recurring.NewOccurrences(sched, rule)}
+		e.schedule(sched, rule, title, body)
+	}
+	for _, t := range s.Todos() {
+		if t.HasDate() {
+			sched, inSeries := series[t.ScheduleID]
+			e.todo(t, sched, inSeries)
+		}
+	}
+
+	e.line("END:VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// exported is a schedule written as a recurring VTODO.
+type exported struct {
+	sched store.Schedule
+	occ   recurring.Occurrences
+}
+
+// encoder writes content lines, remembering the first write error.
+type encoder struct {
+	w         *bufio.Writer
+	stamp     string    // DTSTAMP value
+	database  string    // identity of the store, part of the UIDs made up for it
+	now       time.Time // start of schedules without a creation date
+	isHoliday func(time.Time) bool
+	err       error
+}
+
+// todo writes a single todo. inSeries marks todos created by series, an
+// exported schedule.
+func (e *encoder) todo(t store.Todo, series exported, inSeries bool) {
+	e.line("BEGIN:VTODO")
+	if inSeries {
+		e.line("UID:" + localUID("schedule", t.ScheduleID, e.database))
+		original := t.ScheduleDate
+		if original == "" {
+			original = t.Date
+		}
+		// An occurrence the holiday policy moved is an RDATE of the
+		// series (see schedule), moved as schedule generation moves it.
+		if d, err := time.ParseInLocation(dateFormat, original, time.Local); err == nil {
+			if due, ok := series.occ.Due(d, series.sched.HolidayPolicy, e.isHoliday); ok {
+				original = due.Format(dateFormat)
+			}
+		}
+		e.line("RECURRENCE-ID;VALUE=DATE:" + compactDate(original))
+	} else if t.UID != "" {
+		e.line("UID:" + t.UID)
+	} else {
+		e.line("UID:" + localUID("todo", t.ID, e.database))
+	}
+	e.line("DTSTAMP:" + e.stamp)
+	e.line("SUMMARY:" + escapeText(t.Text))
+	if t.Body != "" {
+		e.line("DESCRIPTION:" + escapeText(t.Body))
+	}
+	e.todoDates(t)
+	if p := Priority(t.Priority); p != 0 {
+		e.line("PRIORITY:" + strconv.Itoa(p))
+	}
+	if len(t.Tags) > 0 {
+		cats := make([]string, len(t.Tags))
+		for i, tag := range t.Tags {
+			cats[i] = escapeText(tag)
+		}
+		e.line("CATEGORIES:" + strings.Join(cats, ","))
+	}
+	if t.Done {
+		e.line("STATUS:COMPLETED")
+		if done, err := time.ParseInLocation(timestampFormat, t.CompletedAt, time.Local); err == nil {
+			e.line("COMPLETED:" + done.UTC().Format(icsUTC))
+		}
+	} else {
+		e.line("STATUS:NEEDS-ACTION")
+	}
+	e.line("END:VTODO")
+}
+
+// todoDates writes the start and due dates of a todo. Day todos are due on
+// their day, or at their start time plus duration when timed. Month and
+// year todos span the whole period: they start on its first day and are
+// due on its last.
+func (e *encoder) todoDates(t store.Todo) {
+	d, err := time.ParseInLocation(dateFormat, t.Date, time.Local)
+	if err != nil {
+		return
+	}
+	switch {
+	case t.IsMonthPrecision():
+		e.line("DTSTART;VALUE=DATE:" + d.Format(icsDate))
+		e.line("DUE;VALUE=DATE:" + d.AddDate(0, 1, -1).Format(icsDate))
+	case t.IsYearPrecision():
+		e.line("DTSTART;VALUE=DATE:" + d.Format(icsDate))
+		e.line("DUE;VALUE=DATE:" + d.AddDate(1, 0, -1).Format(icsDate))
+	case t.HasTime():
+		start, err := time.ParseInLocation(dateFormat+" 15:04", t.Date+" "+t.StartTime, time.Local)
+		if err != nil {
+			e.line("DUE;VALUE=DATE:" + d.Format(icsDate))
+			return
+		}
+		if t.Duration > 0 {
+			e.line("DTSTART:" + start.Format(icsDateTime))
+			e.line("DUE:" + start.Add(time.Duration(t.Duration)*time.Minute).Format(icsDateTime))
+		} else {
+			e.line("DUE:" + start.Format(icsDateTime))
+		}
+	default:
+		e.line("DUE;VALUE=DATE:" + d.Format(icsDate))
+	}
+}
+
+// schedule writes a template schedule as a recurring VTODO starting on the
+// first matching day on or after the schedule was created, on or after
+// the DTSTART of a recurrence rule with a COUNT, or on or after its start
+// date. Its end date or maximum number of occurrences becomes an UNTIL,
+// and its exceptions EXDATEs. Occurrences its holiday policy skips become
+// EXDATEs too, and the ones it moves EXDATEs with an RDATE for the day
+// they move to. iCalendar has no holiday policies, so this is done for
+// occurrences up to a year after now; later ones follow the rule alone.
+func (e *encoder) schedule(sched store.Schedule, rule recurring.ScheduleRule, title, body string) {
+	start, err := time.ParseInLocation(dateFormat, sched.CreatedAt, time.Local)
+	if err != nil {
+		start = e.now
+	}
+	if rule.Recur != nil && rule.Recur.Count > 0 {
+		start = rule.Recur.Start
+	}
+	first, last := recurring.NewOccurrences(sched, rule).Bounds()
+	if !first.IsZero() {
+		start = first
+	}
+	for i := 0; i < 366 && !rule.MatchesDate(start); i++ {
+		start = start.AddDate(0, 0, 1)
+	}
+
+	e.line("BEGIN:VTODO")
+	e.line("UID:" + localUID("schedule", sched.ID, e.database))
+	e.line("DTSTAMP:" + e.stamp)
+	e.line("SUMMARY:" + escapeText(title))
+	if body != "" {
+		e.line("DESCRIPTION:" + escapeText(body))
+	}
+	e.line("DTSTART;VALUE=DATE:" + start.Format(icsDate))
+	rrule := rule.RRule()
+	// COUNT and UNTIL cannot be combined; a rule that has either keeps it.
+	if !last.IsZero() && (rule.Recur == nil || (rule.Recur.Count == 0 && rule.Recur.Until.IsZero())) {
+		rrule += ";UNTIL=" + last.Format(icsDate)
+	}
+	e.line("RRULE:" + rrule)
+
+	var exdates, rdates []string
+	for _, d := range sched.Exceptions {
+		exdates = append(exdates, compactDate(d))
+	}
+	if sched.HolidayPolicy != recurring.HolidayCreate {
+		horizon := e.now.AddDate(1, 0, 0)
+		for _, occ := range recurring.Upcoming(sched, rule, start, holidayOccurrences, e.isHoliday) {
+			if occ.Date.After(horizon) {
+				break
+			}
+			switch {
+			case occ.Exception:
+			case occ.Skipped:
+				exdates = append(exdates, occ.Date.Format(icsDate))
+			case !occ.Due.Equal(occ.Date):
+				exdates = append(exdates, occ.Date.Format(icsDate))
+				rdates = append(rdates, occ.Due.Format(icsDate))
+			}
+		}
+	}
+	if len(exdates) > 0 {
+		sort.Strings(exdates)
+		e.line("EXDATE;VALUE=DATE:" + strings.Join(exdates, ","))
+	}
+	if len(rdates) > 0 {
+		e.line("RDATE;VALUE=DATE:" + strings.Join(rdates, ","))
+	}
+	e.line("STATUS:NEEDS-ACTION")
+	e.line("END:VTODO")
+}
+
+// line writes a content line, folded to lineLimit octets without splitting
+// UTF-8 sequences.
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	limit := lineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(s[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = lineLimit - 1 // the continuation space counts
+	}
+	_, e.err = e.w.WriteString(s + "\r\n")
+}
+
+// Priority maps a todo priority (1 = P1 ... 4 = P4, 0 = none) to an
+// iCalendar PRIORITY, where 1-4 is high, 5 medium and 6-9 low.
+func Priority(p int) int {
+	switch p {
+	case 1:
+		return 1
+	case 2:
+		return 3
+	case 3:
+		return 5
+	case 4:
+		return 9
+	default:
+		return 0
+	}
+}
+
+// localUID returns the UID made up for a todo without one, or for a
+// schedule's recurring VTODO (kind "todo" or "schedule"), of the database
+// with the given identity.
+func localUID(kind string, id int, database string) string {
+	return kind + "-" + strconv.Itoa(id) + "@" + database + ".todo-calendar"
+}
+
+// compactDate turns YYYY-MM-DD into the iCalendar form YYYYMMDD.
+func compactDate(date string) string {
+	return strings.ReplaceAll(date, "-", "")
+}
+
+// escapeText escapes a TEXT property value.
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
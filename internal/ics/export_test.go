@@ -0,0 +1,242 @@
+package ics
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+func newStore(t *testing.T) *store.SQLiteStore {
+	t.Helper()
+	s, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// unfold joins folded content lines and splits the output into lines.
+func unfold(out string) []string {
+	return strings.Split(strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""), "\r\n")
+}
+
+// component returns the lines of the VTODO containing the given line.
+func component(lines []string, line string) []string {
+	start := -1
+	for i, l := range lines {
+		if l == "BEGIN:VTODO" {
+			start = i
+		}
+		if l == line && start >= 0 {
+			for j := i; j < len(lines); j++ {
+				if lines[j] == "END:VTODO" {
+					return lines[start : j+1]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// has reports whether comp contains the given line.
+func has(comp []string, line string) bool {
+	for _, l := range comp {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+func TestExport(t *testing.T) {
+	s := newStore(t)
+	dentist := s.Add("Dentist, 2nd visit", "2026-10-20", "day", 1)
+	s.SetTime(dentist.ID, "14:00", 45)
+	s.SetTags(dentist.ID, []string{"health"})
+	taxes := s.Add("Taxes", "2026-11-01", "month", 3)
+	s.Toggle(taxes.ID)
+	s.Add("Plan trip", "2027-01-01", "year", 0)
+	s.Add("Someday", "", "", 0)
+
+	tpl, err := s.AddTemplate("Standup", "Notes for {{.Team}}")
+	if err != nil {
+		t.Fatalf("add template: %v", err)
+	}
+	sched, err := s.AddSchedule(tpl.ID, "weekly", "mon,fri", `{"Team":"core"}`)
+	if err != nil {
+		t.Fatalf("add schedule: %v", err)
+	}
+	s.AddScheduledTodo("Standup", "2026-10-23", "Notes for core", sched.ID)
+
+	var buf bytes.Buffer
+	if err := Export(&buf, s, time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	out := buf.String()
+	for _, l := range strings.Split(out, "\r\n") {
+		if len(l) > lineLimit {
+			t.Errorf("line longer than %d octets: %q", lineLimit, l)
+		}
+	}
+	lines := unfold(out)
+	if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+		t.Fatalf("not a calendar: %q", out)
+	}
+	if strings.Contains(out, "Someday") {
+		t.Error("floating todo exported")
+	}
+
+	d := component(lines, `SUMMARY:Dentist\, 2nd visit`)
+	for _, want := range []string{"DTSTART:20261020T140000", "DUE:20261020T144500", "PRIORITY:1", "CATEGORIES:health", "STATUS:NEEDS-ACTION", "DTSTAMP:20261017T090000Z"} {
+		if !has(d, want) {
+			t.Errorf("timed todo missing %q: %q", want, d)
+		}
+	}
+	m := component(lines, "SUMMARY:Taxes")
+	for _, want := range []string{"DTSTART;VALUE=DATE:20261101", "DUE;VALUE=DATE:20261130", "PRIORITY:5", "STATUS:COMPLETED"} {
+		if !has(m, want) {
+			t.Errorf("month todo missing %q: %q", want, m)
+		}
+	}
+	if y := component(lines, "SUMMARY:Plan trip"); !has(y, "DUE;VALUE=DATE:20271231") {
+		t.Errorf("year todo not due at the end of the year: %q", y)
+	}
+
+	uid := "UID:" + localUID("schedule", sched.ID, s.DatabaseID())
+	series := component(lines, "RRULE:FREQ=WEEKLY;BYDAY=MO,FR")
+	if !has(series, uid) || !has(series, "DESCRIPTION:Notes for core") {
+		t.Errorf("schedule series = %q", series)
+	}
+	if instance := component(lines, "RECURRENCE-ID;VALUE=DATE:20261023"); !has(instance, uid) {
+		t.Errorf("scheduled todo not exported as an instance: %q", instance)
+	}
+}
+
+func TestExportRRuleSchedule(t *testing.T) {
+	s := newStore(t)
+	tpl, err := s.AddTemplate("Retro", "")
+	if err != nil {
+		t.Fatalf("add template: %v", err)
+	}
+	if _, err := s.AddSchedule(tpl.ID, "rrule", "FREQ=MONTHLY;BYDAY=-1FR;COUNT=6;DTSTART=20260101", "{}"); err != nil {
+		t.Fatalf("add schedule: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, s, time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	series := component(unfold(buf.String()), "RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=6")
+	if !has(series, "DTSTART;VALUE=DATE:20260130") {
+		t.Errorf("counted series does not start at its first occurrence: %q", series)
+	}
+}
+
+func TestExportFoldsLongLines(t *testing.T) {
+	s := newStore(t)
+	long := strings.Repeat("ä", 60)
+	s.Add(long, "2026-10-20", "day", 0)
+
+	var buf bytes.Buffer
+	if err := Export(&buf, s, time.Now(), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if !has(unfold(buf.String()), "SUMMARY:"+long) {
+		t.Errorf("summary not folded losslessly: %q", buf.String())
+	}
+}
+
+func TestExportScheduleLifecycle(t *testing.T) {
+	s := newStore(t)
+	tpl, err := s.AddTemplate("Standup", "")
+	if err != nil {
+		t.Fatalf("add template: %v", err)
+	}
+	sched, err := s.AddSchedule(tpl.ID, "weekdays", "", "{}")
+	if err != nil {
+		t.Fatalf("add schedule: %v", err)
+	}
+	s.SetScheduleRange(sched.ID, "2026-11-01", "2026-11-30", 0)
+	s.SetScheduleException(sched.ID, "2026-11-13", true)
+	s.SetScheduleException(sched.ID, "2026-11-06", true)
+
+	var buf bytes.Buffer
+	if err := Export(&buf, s, time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	series := component(unfold(buf.String()), "RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;UNTIL=20261130")
+	for _, want := range []string{"DTSTART;VALUE=DATE:20261102", "EXDATE;VALUE=DATE:20261106,20261113"} {
+		if !has(series, want) {
+			t.Errorf("series missing %q: %q", want, series)
+		}
+	}
+}
+
+func TestExportScheduleHolidayPolicy(t *testing.T) {
+	s := newStore(t)
+	tpl, err := s.AddTemplate("Rent", "")
+	if err != nil {
+		t.Fatalf("add template: %v", err)
+	}
+	sched, err := s.AddSchedule(tpl.ID, "monthly", "1", "{}")
+	if err != nil {
+		t.Fatalf("add schedule: %v", err)
+	}
+	s.SetHolidayPolicy(sched.ID, "next")
+	s.SetScheduleRange(sched.ID, "2026-10-01", "2027-01-31", 0)
+	// Nov 1, 2026 is a Sunday; its todo moved to Monday.
+	s.AddShiftedTodo("Rent", "2026-11-02", "2026-11-01", "", sched.ID)
+
+	var buf bytes.Buffer
+	if err := Export(&buf, s, time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	lines := unfold(buf.String())
+	series := component(lines, "RRULE:FREQ=MONTHLY;BYMONTHDAY=1;UNTIL=20270131")
+	for _, want := range []string{"EXDATE;VALUE=DATE:20261101", "RDATE;VALUE=DATE:20261102"} {
+		if !has(series, want) {
+			t.Errorf("series missing %q: %q", want, series)
+		}
+	}
+	if instance := component(lines, "RECURRENCE-ID;VALUE=DATE:20261102"); !has(instance, "SUMMARY:Rent") {
+		t.Errorf("shifted todo not exported as the moved occurrence: %q", instance)
+	}
+}
+
+func TestExportScheduleOverrides(t *testing.T) {
+	s := newStore(t)
+	tpl, err := s.AddTemplate("Gym", "")
+	if err != nil {
+		t.Fatalf("add template: %v", err)
+	}
+	sched, err := s.AddSchedule(tpl.ID, "weekly", "sat,sun", "{}")
+	if err != nil {
+		t.Fatalf("add schedule: %v", err)
+	}
+	s.SetHolidayPolicy(sched.ID, "next")
+	// The weekend yields a single todo, on Monday for Sunday; Saturday's
+	// was created before the policy was set and stays on its day.
+	s.AddScheduledTodo("Gym", "2026-10-24", "", sched.ID)
+	s.AddShiftedTodo("Gym", "2026-10-26", "2026-10-25", "", sched.ID)
+	paused, _ := s.AddSchedule(tpl.ID, "daily", "", "{}")
+	s.SetSchedulePaused(paused.ID, true)
+
+	var buf bytes.Buffer
+	if err := Export(&buf, s, time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"RECURRENCE-ID;VALUE=DATE:20261024\r\n", "RECURRENCE-ID;VALUE=DATE:20261026\r\n"} {
+		if n := strings.Count(out, want); n != 1 {
+			t.Errorf("%d instances with %q, want 1", n, strings.TrimSpace(want))
+		}
+	}
+	if strings.Contains(out, "RRULE:FREQ=DAILY") {
+		t.Error("paused schedule exported")
+	}
+}
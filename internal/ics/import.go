@@ -0,0 +1,475 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// ImportResult counts the components handled by Import.
+type ImportResult struct {
+	Added   int // new todos
+	Updated int // todos matched by UID and overwritten
+	Skipped int // cancelled components, todos in the trash and schedules of the store
+	Failed  int // components that could not be stored
+}
+
+// Import reads the VTODO and VEVENT components of an iCalendar stream and
+// stores them as todos, as a single undoable operation. Components whose
+// UID was imported before update that todo instead of adding a new one, as
+// do the components Export wrote for todos of the same store; components
+// whose todo is in the trash or no longer exists are skipped. Recurring
+// components are imported as their first occurrence, except the ones
+// Export wrote for schedules of the same store.
+func Import(r io.Reader, s store.TodoStore) (ImportResult, error) {
+	items, err := parse(r)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	var res ImportResult
+	s.Batch("import", func() {
+		for _, it := range items {
+			if it.cancelled {
+				res.Skipped++
+				continue
+			}
+			switch apply(s, it) {
+			case added:
+				res.Added++
+			case updated:
+				res.Updated++
+			case skipped:
+				res.Skipped++
+			default:
+				res.Failed++
+			}
+		}
+	})
+	return res, nil
+}
+
+// outcome is what apply did with an item.
+type outcome int
+
+const (
+	added outcome = iota
+	updated
+	skipped
+	failed
+)
+
+// apply stores an item.
+func apply(s store.TodoStore, it item) outcome {
+	existing, skip := match(s, it)
+	if skip {
+		return skipped
+	}
+	var id int
+	done := false
+	if existing == nil {
+		id = s.Add(it.text, it.date, it.precision, it.priority).ID
+		if id == 0 {
+			return failed
+		}
+		if it.uid != "" {
+			s.SetUID(id, it.uid)
+		}
+	} else {
+		id, done = existing.ID, existing.Done
+		s.Update(id, it.text, it.date, it.precision, it.priority)
+	}
+	s.UpdateBody(id, it.body)
+	s.SetTime(id, it.startTime, it.duration)
+	s.SetTags(id, it.tags)
+	if it.done != done {
+		s.Toggle(id)
+	}
+	if existing == nil {
+		return added
+	}
+	return updated
+}
+
+// localUIDPattern matches the UIDs localUID makes up.
+var localUIDPattern = regexp.MustCompile(`^(todo|schedule)-(\d+)@([0-9a-f]+)\.todo-calendar$`)
+
+// match returns the todo an item overwrites: the one with its UID or, for
+// a UID Export made up for this store, the todo or schedule occurrence it
+// was written for. skip reports items not to store: ones whose todo is in
+// the trash or gone, and the recurring components of the store's
+// schedules.
+func match(s store.TodoStore, it item) (existing *store.Todo, skip bool) {
+	if it.uid == "" {
+		return nil, false
+	}
+	if t := s.FindByUID(it.uid); t != nil {
+		return t, t.DeletedAt != ""
+	}
+	uid, _, _ := strings.Cut(it.uid, "#")
+	m := localUIDPattern.FindStringSubmatch(uid)
+	if m == nil || m[3] != s.DatabaseID() {
+		return nil, false
+	}
+	id, _ := strconv.Atoi(m[2])
+	var t *store.Todo
+	switch {
+	case m[1] == "todo":
+		t = s.Find(id)
+	case it.recurrence != "":
+		t = s.FindScheduled(id, it.recurrence)
+	}
+	if t == nil || t.DeletedAt != "" {
+		return nil, true
+	}
+	return t, false
+}
+
+// item is a todo parsed from a VTODO or VEVENT.
+type item struct {
+	uid        string
+	recurrence string // RECURRENCE-ID date, YYYY-MM-DD
+	text       string
+	body       string
+	date       string // YYYY-MM-DD, "" for floating
+	precision  string // "day", "month", "year", "" for floating
+	startTime  string // "HH:MM", "" for untimed
+	duration   int    // minutes
+	priority   int    // 0-4
+	tags       []string
+	done       bool
+	cancelled  bool
+}
+
+// property is a content line: NAME;PARAM=VALUE:value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parse reads the VTODO and VEVENT components of an iCalendar stream.
+// Properties of nested components such as VALARM are ignored.
+func parse(r io.Reader) ([]item, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+	var items []item
+	var comp string // "VTODO" or "VEVENT" while inside one
+	var props map[string][]property
+	nested := 0
+	for _, line := range lines {
+		p, ok := parseLine(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case p.name == "BEGIN" && comp == "" && (p.value == "VTODO" || p.value == "VEVENT"):
+			comp = p.value
+			props = make(map[string][]property)
+		case p.name == "BEGIN" && comp != "":
+			nested++
+		case p.name == "END" && comp != "" && nested > 0:
+			nested--
+		case p.name == "END" && p.value == comp:
+			items = append(items, toItem(comp, props))
+			comp = ""
+		case comp != "" && nested == 0:
+			props[p.name] = append(props[p.name], p)
+		}
+	}
+	if comp != "" {
+		return nil, fmt.Errorf("unterminated %s component", comp)
+	}
+	return items, nil
+}
+
+// unfoldLines reads content lines, joining folded continuation lines.
+func unfoldLines(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	var lines []string
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, sc.Err()
+}
+
+// parseLine splits a content line into name, parameters and value. Colons
+// and semicolons inside quoted parameter values do not split.
+func parseLine(line string) (property, bool) {
+	quoted := false
+	var parts []string
+	start := 0
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == ';':
+			parts = append(parts, line[start:i])
+			start = i + 1
+		case c == ':':
+			parts = append(parts, line[start:i])
+			p := property{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[i+1:]}
+			for _, param := range parts[1:] {
+				k, v, _ := strings.Cut(param, "=")
+				p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+			}
+			return p, true
+		}
+	}
+	return property{}, false
+}
+
+// toItem converts the properties of a component to an item.
+func toItem(comp string, props map[string][]property) item {
+	get := func(name string) *property {
+		if ps := props[name]; len(ps) > 0 {
+			return &ps[0]
+		}
+		return nil
+	}
+	text := func(name string) string {
+		if p := get(name); p != nil {
+			return unescapeText(p.value)
+		}
+		return ""
+	}
+
+	it := item{text: strings.TrimSpace(text("SUMMARY")), body: text("DESCRIPTION")}
+	if it.text == "" {
+		it.text = "(untitled)"
+	}
+	if loc := text("LOCATION"); loc != "" {
+		it.body = strings.TrimSpace("Location: " + loc + "\n\n" + it.body)
+	}
+	if uid := get("UID"); uid != nil && uid.value != "" {
+		it.uid = uid.value
+		if rid := get("RECURRENCE-ID"); rid != nil {
+			it.uid += "#" + rid.value
+			if d, ok := parseDateProp(rid); ok {
+				it.recurrence = d.t.Format(dateFormat)
+			}
+		}
+	}
+	if p := get("PRIORITY"); p != nil {
+		n, _ := strconv.Atoi(strings.TrimSpace(p.value))
+		it.priority = fromICSPriority(n)
+	}
+	for _, p := range props["CATEGORIES"] {
+		for _, c := range splitEscaped(p.value) {
+			it.tags = append(it.tags, unescapeText(c))
+		}
+	}
+	status := strings.ToUpper(text("STATUS"))
+	it.cancelled = status == "CANCELLED"
+	it.done = comp == "VTODO" && (status == "COMPLETED" || get("COMPLETED") != nil)
+
+	start, startOK := parseDateProp(get("DTSTART"))
+	var end dateValue
+	var endOK bool
+	if comp == "VTODO" {
+		end, endOK = parseDateProp(get("DUE"))
+	} else {
+		end, endOK = parseDateProp(get("DTEND"))
+	}
+	if !endOK && startOK {
+		if p := get("DURATION"); p != nil {
+			if d, ok := parseDuration(p.value); ok && d > 0 {
+				end, endOK = dateValue{t: start.t.Add(d), allDay: start.allDay}, true
+			}
+		}
+	}
+	it.setDates(comp, start, startOK, end, endOK)
+	return it
+}
+
+// setDates maps DTSTART and DUE (VTODO) or DTEND (VEVENT) to the item's
+// date, precision, time and duration. To-dos are placed on their due date
+// unless timed from a start; all-day spans covering exactly a month or a
+// year, as written by Export, become month or year todos. Events are
+// placed on their start.
+func (it *item) setDates(comp string, start dateValue, startOK bool, end dateValue, endOK bool) {
+	switch {
+	case startOK && endOK && start.allDay && end.allDay:
+		s, e := start.t, end.t
+		if comp == "VEVENT" {
+			e = e.AddDate(0, 0, -1) // DTEND of all-day events is exclusive
+		}
+		switch {
+		case s.Day() == 1 && s.Month() == time.January && e.Equal(s.AddDate(1, 0, -1)):
+			it.date, it.precision = s.Format(dateFormat), "year"
+		case s.Day() == 1 && e.Equal(s.AddDate(0, 1, -1)):
+			it.date, it.precision = s.Format(dateFormat), "month"
+		case comp == "VEVENT":
+			it.date, it.precision = s.Format(dateFormat), "day"
+		default:
+			it.date, it.precision = e.Format(dateFormat), "day"
+		}
+	case startOK && !start.allDay:
+		it.setDay(start.t)
+		it.startTime = start.t.Format("15:04")
+		if endOK && !end.allDay && end.t.After(start.t) {
+			it.duration = int(end.t.Sub(start.t).Minutes())
+		}
+	case endOK && comp == "VTODO":
+		it.setDay(end.t)
+		if !end.allDay {
+			it.startTime = end.t.Format("15:04")
+		}
+	case startOK:
+		it.setDay(start.t)
+	}
+}
+
+// setDay places the item on the day of t.
+func (it *item) setDay(t time.Time) {
+	it.date, it.precision = t.Format(dateFormat), "day"
+}
+
+// dateValue is a parsed DATE or DATE-TIME in local time.
+type dateValue struct {
+	t      time.Time
+	allDay bool
+}
+
+// parseDateProp parses a DATE or DATE-TIME property. UTC and TZID times
+// are converted to local time; unknown time zones and floating times are
+// read as local time.
+func parseDateProp(p *property) (dateValue, bool) {
+	if p == nil {
+		return dateValue{}, false
+	}
+	v := strings.TrimSpace(p.value)
+	if len(v) == len(icsDate) {
+		t, err := time.ParseInLocation(icsDate, v, time.Local)
+		return dateValue{t: t, allDay: true}, err == nil
+	}
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse(icsUTC, v)
+		return dateValue{t: t.Local()}, err == nil
+	}
+	loc := time.Local
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation(icsDateTime, v, loc)
+	return dateValue{t: t.Local()}, err == nil
+}
+
+// parseDuration parses an iCalendar DURATION such as "PT1H30M" or "P1D".
+func parseDuration(s string) (time.Duration, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "+")
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if !strings.HasPrefix(s, "P") {
+		return 0, false
+	}
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, c := range s[1:] {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, false
+		}
+		num = ""
+		switch {
+		case c == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case c == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, false
+		}
+	}
+	if num != "" {
+		return 0, false
+	}
+	if neg {
+		d = -d
+	}
+	return d, true
+}
+
+// fromICSPriority maps an iCalendar PRIORITY (1 highest ... 9 lowest,
+// 0 undefined) to a todo priority, the inverse of Priority.
+func fromICSPriority(p int) int {
+	switch {
+	case p >= 1 && p <= 2:
+		return 1
+	case p >= 3 && p <= 4:
+		return 2
+	case p == 5:
+		return 3
+	case p >= 6 && p <= 9:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// splitEscaped splits a list value on commas that are not escaped.
+func splitEscaped(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescapeText reverses escapeText.
+func unescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,233 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+const invite = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:meeting-1@example.com\r\n" +
+	"SUMMARY:Quarterly review\\, Q3\r\n" +
+	"DESCRIPTION:Bring the\\nnumbers\r\n" +
+	"LOCATION:Room 4\r\n" +
+	"DTSTART:20261020T140000\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:task-1@example.com\r\n" +
+	"SUMMARY:File a very long expense report that needs folding across more t\r\n" +
+	" han one line\r\n" +
+	"DUE;VALUE=DATE:20261031\r\n" +
+	"PRIORITY:2\r\n" +
+	"CATEGORIES:Work,Finance\r\n" +
+	"STATUS:COMPLETED\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday@example.com\r\n" +
+	"SUMMARY:Company holiday\r\n" +
+	"DTSTART;VALUE=DATE:20261224\r\n" +
+	"DTEND;VALUE=DATE:20261225\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cancelled@example.com\r\n" +
+	"SUMMARY:Cancelled sync\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART:20261021T100000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestImport(t *testing.T) {
+	s := newStore(t)
+	res, err := Import(strings.NewReader(invite), s)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if res != (ImportResult{Added: 3, Skipped: 1}) {
+		t.Errorf("result = %+v", res)
+	}
+
+	meeting := s.FindByUID("meeting-1@example.com")
+	if meeting == nil {
+		t.Fatal("event not imported")
+	}
+	if meeting.Text != "Quarterly review, Q3" || meeting.Date != "2026-10-20" || meeting.StartTime != "14:00" || meeting.Duration != 90 {
+		t.Errorf("event = %+v", meeting)
+	}
+	if meeting.Body != "Location: Room 4\n\nBring the\nnumbers" {
+		t.Errorf("event body = %q", meeting.Body)
+	}
+
+	task := s.FindByUID("task-1@example.com")
+	if task == nil || !strings.HasSuffix(task.Text, "more than one line") || task.Date != "2026-10-31" || task.Priority != 1 || !task.Done {
+		t.Errorf("todo = %+v", task)
+	}
+	if !task.HasTag("work") || !task.HasTag("finance") {
+		t.Errorf("todo tags = %v", task.Tags)
+	}
+	if h := s.FindByUID("holiday@example.com"); h == nil || h.Date != "2026-12-24" || h.DatePrecision != "day" || h.HasTime() {
+		t.Errorf("all-day event = %+v", h)
+	}
+
+	// Re-importing updates the todos in place.
+	changed := strings.Replace(invite, "Quarterly review", "Annual review", 1)
+	res, err = Import(strings.NewReader(changed), s)
+	if err != nil {
+		t.Fatalf("re-import: %v", err)
+	}
+	if res.Added != 0 || res.Updated != 3 || len(s.Todos()) != 3 {
+		t.Errorf("re-import = %+v with %d todos", res, len(s.Todos()))
+	}
+	if got := s.Find(meeting.ID); got.Text != "Annual review, Q3" {
+		t.Errorf("re-import did not update the event: %+v", got)
+	}
+
+	// The whole import is one undoable operation.
+	if label, _ := s.Undo(); label != "import" {
+		t.Errorf("undo label = %q", label)
+	}
+	if got := s.Find(meeting.ID); got.Text != "Quarterly review, Q3" {
+		t.Errorf("undo did not revert the re-import: %+v", got)
+	}
+}
+
+func TestImportRoundTrip(t *testing.T) {
+	src := newStore(t)
+	month := src.Add("Taxes", "2026-11-01", "month", 3)
+	src.SetUID(month.ID, "taxes@example.com")
+	year := src.Add("Plan trip", "2027-01-01", "year", 4)
+	src.SetUID(year.ID, "trip@example.com")
+	timed := src.Add("Dentist", "2026-10-20", "day", 2)
+	src.SetTime(timed.ID, "09:30", 0)
+
+	var buf bytes.Buffer
+	if err := Export(&buf, src, time.Now(), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	dst := newStore(t)
+	if _, err := Import(&buf, dst); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	if got := dst.FindByUID("taxes@example.com"); got == nil || got.DatePrecision != "month" || got.Date != "2026-11-01" || got.Priority != 3 {
+		t.Errorf("month todo = %+v", got)
+	}
+	if got := dst.FindByUID("trip@example.com"); got == nil || got.DatePrecision != "year" || got.Date != "2027-01-01" || got.Priority != 4 {
+		t.Errorf("year todo = %+v", got)
+	}
+	got := dst.FindByUID(localUID("todo", timed.ID, src.DatabaseID()))
+	if got == nil || got.Date != "2026-10-20" || got.StartTime != "09:30" || got.Priority != 2 {
+		t.Errorf("timed todo = %+v", got)
+	}
+}
+
+func TestImportOwnExport(t *testing.T) {
+	s := newStore(t)
+	s.Add("Dentist", "2026-10-20", "day", 2)
+	tpl, err := s.AddTemplate("Standup", "")
+	if err != nil {
+		t.Fatalf("add template: %v", err)
+	}
+	sched, err := s.AddSchedule(tpl.ID, "weekly", "mon,fri", "{}")
+	if err != nil {
+		t.Fatalf("add schedule: %v", err)
+	}
+	standup := s.AddScheduledTodo("Standup", "2026-10-23", "", sched.ID)
+
+	var buf bytes.Buffer
+	if err := Export(&buf, s, time.Now(), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	s.Update(standup.ID, "Standup, moved", "2026-10-23", "day", 0)
+	res, err := Import(&buf, s)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if want := (ImportResult{Updated: 2, Skipped: 1}); res != want {
+		t.Errorf("result = %+v, want %+v", res, want)
+	}
+	if got := len(s.Todos()); got != 2 {
+		t.Errorf("%d todos after re-import, want 2", got)
+	}
+	if got := s.Find(standup.ID); got.Text != "Standup" {
+		t.Errorf("scheduled todo = %+v, want it overwritten", got)
+	}
+}
+
+func TestImportOtherDatabase(t *testing.T) {
+	src := newStore(t)
+	src.Add("Dentist", "2026-10-20", "day", 2)
+	tpl, _ := src.AddTemplate("Standup", "")
+	src.AddSchedule(tpl.ID, "weekly", "mon,fri", "{}")
+
+	var buf bytes.Buffer
+	if err := Export(&buf, src, time.Now(), nil); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	// The same IDs in another database belong to unrelated todos and
+	// schedules.
+	dst := newStore(t)
+	local := dst.Add("Local", "2026-10-21", "day", 0)
+	dstTpl, _ := dst.AddTemplate("Review", "")
+	dst.AddSchedule(dstTpl.ID, "daily", "", "{}")
+	res, err := Import(&buf, dst)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if want := (ImportResult{Added: 2}); res != want {
+		t.Errorf("result = %+v, want %+v", res, want)
+	}
+	if got := dst.Find(local.ID); got.Text != "Local" {
+		t.Errorf("local todo overwritten: %+v", got)
+	}
+}
+
+func TestImportSkipsTrashed(t *testing.T) {
+	s := newStore(t)
+	const file = "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:call@example.com\r\nSUMMARY:Call\r\nDUE;VALUE=DATE:20261020\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+	if _, err := Import(strings.NewReader(file), s); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	call := s.FindByUID("call@example.com")
+	s.Delete(call.ID)
+
+	res, err := Import(strings.NewReader(file), s)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if want := (ImportResult{Skipped: 1}); res != want {
+		t.Errorf("result = %+v, want %+v", res, want)
+	}
+	if s.Find(call.ID) != nil || len(s.Todos()) != 0 {
+		t.Error("re-import brought back or re-added the trashed todo")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT15M":   15 * time.Minute,
+		"PT1H30M": 90 * time.Minute,
+		"P1D":     24 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"-PT5M":   -5 * time.Minute,
+		"P1DT2H":  26 * time.Hour,
+		"PT":      0,
+	}
+	for in, want := range tests {
+		if got, ok := parseDuration(in); !ok || got != want {
+			t.Errorf("parseDuration(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+	for _, bad := range []string{"", "1H", "PT5", "P5M"} {
+		if _, ok := parseDuration(bad); ok {
+			t.Errorf("parseDuration(%q) succeeded", bad)
+		}
+	}
+}
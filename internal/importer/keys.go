@@ -0,0 +1,33 @@
+package importer
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines key bindings for the import overlay.
+type KeyMap struct {
+	Import key.Binding
+	Cancel key.Binding
+}
+
+// ShortHelp returns key bindings for the short help view.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Import, k.Cancel}
+}
+
+// FullHelp returns key bindings for the full help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// DefaultKeyMap returns the default import key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Import: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "import"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
@@ -0,0 +1,173 @@
+// Package importer implements the overlay for importing todos from files
+// written by other tools.
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/antti/todo-calendar/internal/ics"
+	"github.com/antti/todo-calendar/internal/store"
+	"github.com/antti/todo-calendar/internal/theme"
+	"github.com/antti/todo-calendar/internal/todotxt"
+)
+
+// CloseMsg is emitted when the user presses Esc to close the import overlay.
+type CloseMsg struct{}
+
+// Model represents the import overlay.
+type Model struct {
+	input  textinput.Model
+	store  store.TodoStore
+	result string // outcome of the last import
+	err    error  // error of the last import
+	width  int
+	height int
+	keys   KeyMap
+	styles Styles
+}
+
+// New creates a new import overlay model.
+func New(s store.TodoStore, t theme.Theme) Model {
+	ti := textinput.New()
+	ti.Placeholder = "~/Downloads/invite.ics"
+	ti.Prompt = "File: "
+	ti.Focus()
+
+	return Model{
+		input:  ti,
+		store:  s,
+		keys:   DefaultKeyMap(),
+		styles: NewStyles(t),
+	}
+}
+
+// Init returns the initial command (starts cursor blinking).
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// SetSize stores dimensions for layout.
+func (m *Model) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// SetTheme replaces the styles with ones built from the given theme.
+func (m *Model) SetTheme(t theme.Theme) {
+	m.styles = NewStyles(t)
+}
+
+// HelpBindings returns overlay-specific key bindings for help bar display.
+func (m Model) HelpBindings() []key.Binding {
+	return m.keys.ShortHelp()
+}
+
+// Update handles messages for the import overlay.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Cancel):
+			return m, func() tea.Msg { return CloseMsg{} }
+
+		case key.Matches(msg, m.keys.Import):
+			m.result, m.err = m.importFile(m.input.Value())
+			if m.err == nil {
+				m.input.SetValue("")
+			}
+			return m, nil
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// importFile imports the file at path, picking the format from its
+// extension (.txt for todo.txt), and describes the outcome.
+func (m Model) importFile(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fmt.Errorf("enter the path of a file to import")
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".ics", ".ical", ".ifb", ".txt":
+	default:
+		return "", fmt.Errorf("unsupported file type %q (expected .ics or .txt)", filepath.Ext(path))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if ext == ".txt" {
+		changes, err := todotxt.Plan(f, m.store)
+		if err != nil {
+			return "", err
+		}
+		res := todotxt.Apply(m.store, changes)
+		return fmt.Sprintf("Imported %s: %d added, %d updated, %d unchanged", filepath.Base(path), res.Added, res.Updated, res.Unchanged), nil
+	}
+	res, err := ics.Import(f, m.store)
+	if err != nil {
+		return "", err
+	}
+	msg := fmt.Sprintf("Imported %s: %d added, %d updated, %d skipped", filepath.Base(path), res.Added, res.Updated, res.Skipped)
+	if res.Failed > 0 {
+		msg += fmt.Sprintf(", %d failed", res.Failed)
+	}
+	return msg, nil
+}
+
+// View renders the import overlay.
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render("Import"))
+	b.WriteString("\n\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString(m.styles.Error.Render(m.err.Error()))
+	case m.result != "":
+		b.WriteString(m.styles.Result.Render(m.result))
+	default:
+		b.WriteString(m.styles.Hint.Render("iCalendar (.ics) and todo.txt (.txt) files. Importing a file again updates the todos it added."))
+	}
+
+	return m.verticalCenter(b.String())
+}
+
+// verticalCenter centers the content vertically within the available height.
+func (m Model) verticalCenter(content string) string {
+	if m.height > 0 {
+		lines := strings.Count(content, "\n") + 1
+		topPad := (m.height - lines) / 2
+		if topPad > 0 {
+			content = strings.Repeat("\n", topPad) + content
+		}
+	}
+	return content
+}
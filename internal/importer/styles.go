@@ -0,0 +1,24 @@
+package importer
+
+import (
+	"github.com/antti/todo-calendar/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles holds themed lipgloss styles for the import overlay.
+type Styles struct {
+	Title  lipgloss.Style
+	Hint   lipgloss.Style
+	Result lipgloss.Style
+	Error  lipgloss.Style
+}
+
+// NewStyles builds import styles from the given theme.
+func NewStyles(t theme.Theme) Styles {
+	return Styles{
+		Title:  lipgloss.NewStyle().Bold(true).Foreground(t.AccentFg),
+		Hint:   lipgloss.NewStyle().Foreground(t.MutedFg),
+		Result: lipgloss.NewStyle().Foreground(t.NormalFg),
+		Error:  lipgloss.NewStyle().Foreground(t.HolidayFg),
+	}
+}
@@ -0,0 +1,286 @@
+// Package mdnotes writes dated todos into markdown notes, one file per day
+// or month as used by Obsidian daily notes, and reads checkbox changes
+// made in those notes back into the store.
+//
+// The todos go into a block delimited by HTML comments, so the rest of a
+// note is left alone. Every todo line ends with an ID comment that links it
+// back to its todo:
+//
+//	<!-- todo-calendar:start -->
+//	- [ ] Dentist 14:00 #health <!-- todo:42 -->
+//	    Bring the referral.
+//	<!-- todo-calendar:end -->
+package mdnotes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/recurring"
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+const (
+	blockStart = "<!-- todo-calendar:start -->"
+	blockEnd   = "<!-- todo-calendar:end -->"
+
+	// bodyIndent nests body lines under their todo's list item.
+	bodyIndent = "    "
+
+	// dayNoteLen is the length of a day note's file name, YYYY-MM-DD.md.
+	dayNoteLen = len("2006-01-02.md")
+)
+
+// todoLine matches a todo line written by Export.
+var todoLine = regexp.MustCompile(`^- \[([ xX])\] .*<!-- todo:(\d+) -->\s*$`)
+
+// Result counts the notes changed by Export.
+type Result struct {
+	Written int // notes created or updated
+	Removed int // notes deleted because only an emptied block was left
+}
+
+// Export writes the dated todos outside the trash and the archive into
+// the notes in dir. With period "month" day todos go into the month's note
+// (YYYY-MM.md) under a heading per day; otherwise each day has its own
+// note (YYYY-MM-DD.md). Month and year todos go into YYYY-MM.md and
+// YYYY.md. Blocks of notes without todos are removed.
+func Export(s store.TodoStore, dir, period string) (Result, error) {
+	if dir == "" {
+		return Result{}, fmt.Errorf("no notes directory configured (set notes_dir)")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Result{}, err
+	}
+
+	notes := make(map[string][]store.Todo)
+	for _, t := range s.Todos() {
+		if t.HasDate() {
+			name := noteName(t, period)
+			notes[name] = append(notes[name], t)
+		}
+	}
+	existing, err := notesWithBlock(dir)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, name := range existing {
+		if _, ok := notes[name]; !ok {
+			notes[name] = nil
+		}
+	}
+
+	var res Result
+	for name, todos := range notes {
+		path := filepath.Join(dir, name)
+		old, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return res, err
+		}
+		updated := replaceBlock(string(old), renderBlock(todos, len(name) != dayNoteLen))
+		switch {
+		case updated == string(old):
+		case strings.TrimSpace(updated) == "":
+			if err := os.Remove(path); err != nil {
+				return res, err
+			}
+			res.Removed++
+		default:
+			if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+				return res, err
+			}
+			res.Written++
+		}
+	}
+	return res, nil
+}
+
+// Import reads the checkboxes of the todo lines in the notes in dir and
+// completes or reopens the matching todos, as a single undoable
+// operation. It returns how many todos changed.
+func Import(s store.TodoStore, dir string) (int, error) {
+	if dir == "" {
+		return 0, fmt.Errorf("no notes directory configured (set notes_dir)")
+	}
+	names, err := notesWithBlock(dir)
+	if err != nil {
+		return 0, err
+	}
+	checked := make(map[int]bool)
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return 0, err
+		}
+		inBlock := false
+		for _, line := range strings.Split(string(data), "\n") {
+			switch strings.TrimSpace(line) {
+			case blockStart:
+				inBlock = true
+				continue
+			case blockEnd:
+				inBlock = false
+				continue
+			}
+			if m := todoLine.FindStringSubmatch(line); inBlock && m != nil {
+				id, _ := strconv.Atoi(m[2])
+				checked[id] = m[1] != " "
+			}
+		}
+	}
+
+	changed := 0
+	s.Batch("sync notes", func() {
+		for id, done := range checked {
+			if t := s.Find(id); t != nil && t.Done != done {
+				recurring.Toggle(s, id, time.Now())
+				changed++
+			}
+		}
+	})
+	return changed, nil
+}
+
+// noteName returns the file name of the note a dated todo belongs to.
+func noteName(t store.Todo, period string) string {
+	switch {
+	case t.IsYearPrecision():
+		return t.Date[:4] + ".md"
+	case t.IsMonthPrecision() || period == "month":
+		return t.Date[:7] + ".md"
+	default:
+		return t.Date + ".md"
+	}
+}
+
+// notesWithBlock returns the names of the markdown files in dir that
+// contain a todo block.
+func notesWithBlock(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(string(data), blockStart) {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// renderBlock renders todos as a delimited block of markdown, or "" for
+// no todos. With dayHeadings, day todos are grouped under a heading per
+// day after the month and year todos.
+func renderBlock(todos []store.Todo, dayHeadings bool) string {
+	if len(todos) == 0 {
+		return ""
+	}
+	sort.SliceStable(todos, func(i, j int) bool {
+		a, b := todos[i], todos[j]
+		if a.DatePrecision != b.DatePrecision {
+			return a.DatePrecision != "day" // month and year todos first
+		}
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.HasTime() != b.HasTime() {
+			return a.HasTime()
+		}
+		return a.StartTime < b.StartTime
+	})
+
+	var b strings.Builder
+	b.WriteString(blockStart + "\n")
+	day := ""
+	for _, t := range todos {
+		if dayHeadings && t.DatePrecision == "day" && t.Date != day {
+			day = t.Date
+			b.WriteString("\n### " + day + "\n\n")
+		}
+		b.WriteString(renderTodo(t))
+	}
+	b.WriteString(blockEnd + "\n")
+	return b.String()
+}
+
+// renderTodo renders a todo as a checkbox line followed by its body,
+// indented to nest under the list item.
+func renderTodo(t store.Todo) string {
+	var b strings.Builder
+	check := " "
+	if t.Done {
+		check = "x"
+	}
+	b.WriteString("- [" + check + "] " + strings.Join(strings.Fields(t.Text), " "))
+	if t.HasTime() {
+		b.WriteString(" " + t.StartTime)
+	}
+	for _, tag := range t.Tags {
+		b.WriteString(" #" + tag)
+	}
+	b.WriteString(" <!-- todo:" + strconv.Itoa(t.ID) + " -->\n")
+
+	body := strings.TrimRight(t.Body, " \t\n")
+	if body != "" {
+		for _, line := range strings.Split(body, "\n") {
+			if strings.TrimSpace(line) == "" {
+				b.WriteString("\n")
+				continue
+			}
+			b.WriteString(bodyIndent + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// replaceBlock replaces the todo block in a note with block, appending it
+// if the note has none. An empty block removes the existing one.
+func replaceBlock(content, block string) string {
+	start := strings.Index(content, blockStart)
+	if start < 0 {
+		if block == "" {
+			return content
+		}
+		if content == "" {
+			return block
+		}
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + "\n" + block
+	}
+
+	end := len(content)
+	if i := strings.Index(content[start:], blockEnd); i >= 0 {
+		end = start + i + len(blockEnd)
+		if end < len(content) && content[end] == '\n' {
+			end++
+		}
+	}
+	before, after := content[:start], content[end:]
+	if block == "" {
+		// Drop the blank line that separated the block from the text before it.
+		before = strings.TrimRight(before, "\n")
+		if before != "" {
+			before += "\n"
+		}
+	}
+	return before + block + after
+}
@@ -0,0 +1,142 @@
+package mdnotes
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+func newStore(t *testing.T) *store.SQLiteStore {
+	t.Helper()
+	s, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func readNote(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestExportDaily(t *testing.T) {
+	s := newStore(t)
+	dir := t.TempDir()
+	dentist := s.Add("Dentist", "2026-10-20", "day", 0)
+	s.SetTime(dentist.ID, "14:00", 30)
+	s.SetTags(dentist.ID, []string{"health"})
+	s.UpdateBody(dentist.ID, "Bring the referral.\n\n- [ ] insurance card")
+	report := s.Add("Report", "2026-10-20", "day", 0)
+	s.Toggle(report.ID)
+	s.Add("Taxes", "2026-10-01", "month", 0)
+	s.Add("Someday", "", "", 0)
+
+	// Text outside the block is kept.
+	if err := os.WriteFile(filepath.Join(dir, "2026-10-20.md"), []byte("# Tuesday\n\nNotes.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Export(s, dir, "day")
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if res.Written != 2 {
+		t.Errorf("Export = %+v, want 2 notes written", res)
+	}
+	want := "# Tuesday\n\nNotes.\n\n" + blockStart + "\n" +
+		"- [ ] Dentist 14:00 #health <!-- todo:" + strconv.Itoa(dentist.ID) + " -->\n" +
+		"    Bring the referral.\n\n" +
+		"    - [ ] insurance card\n" +
+		"- [x] Report <!-- todo:" + strconv.Itoa(report.ID) + " -->\n" +
+		blockEnd + "\n"
+	if got := readNote(t, dir, "2026-10-20.md"); got != want {
+		t.Errorf("day note = %q, want %q", got, want)
+	}
+	if got := readNote(t, dir, "2026-10.md"); !strings.Contains(got, "- [ ] Taxes") {
+		t.Errorf("month note = %q", got)
+	}
+
+	// Exporting again changes nothing; moving a todo empties its old note.
+	if res, _ := Export(s, dir, "day"); res != (Result{}) {
+		t.Errorf("second Export = %+v", res)
+	}
+	s.Update(dentist.ID, "Dentist", "2026-10-21", "day", 0)
+	s.Delete(report.ID)
+	if res, _ := Export(s, dir, "day"); res != (Result{Written: 2}) {
+		t.Errorf("Export after moving = %+v", res)
+	}
+	if got := readNote(t, dir, "2026-10-20.md"); got != "# Tuesday\n\nNotes.\n" {
+		t.Errorf("emptied note = %q", got)
+	}
+	s.Delete(dentist.ID)
+	if res, _ := Export(s, dir, "day"); res.Removed != 1 {
+		t.Errorf("Export = %+v, want the note holding only the block removed", res)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026-10-21.md")); !os.IsNotExist(err) {
+		t.Error("note with only an empty block was kept")
+	}
+}
+
+func TestExportMonthly(t *testing.T) {
+	s := newStore(t)
+	dir := t.TempDir()
+	s.Add("Later", "2026-10-21", "day", 0)
+	s.Add("Earlier", "2026-10-20", "day", 0)
+	s.Add("Taxes", "2026-10-01", "month", 0)
+
+	if _, err := Export(s, dir, "month"); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	got := readNote(t, dir, "2026-10.md")
+	taxes := strings.Index(got, "Taxes")
+	earlier := strings.Index(got, "### 2026-10-20\n\n- [ ] Earlier")
+	later := strings.Index(got, "### 2026-10-21\n\n- [ ] Later")
+	if taxes < 0 || earlier < taxes || later < earlier {
+		t.Errorf("month note = %q", got)
+	}
+}
+
+func TestImport(t *testing.T) {
+	s := newStore(t)
+	dir := t.TempDir()
+	a := s.Add("Dentist", "2026-10-20", "day", 0)
+	b := s.Add("Report", "2026-10-20", "day", 0)
+	s.Toggle(b.ID)
+	if _, err := Export(s, dir, "day"); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	path := filepath.Join(dir, "2026-10-20.md")
+	note := readNote(t, dir, "2026-10-20.md")
+	note = strings.Replace(note, "- [ ] Dentist", "- [x] Dentist", 1)
+	note = strings.Replace(note, "- [x] Report", "- [ ] Report", 1)
+	// Lines outside the block are ignored.
+	note += "\n- [x] Elsewhere <!-- todo:" + strconv.Itoa(b.ID) + " -->\n"
+	if err := os.WriteFile(path, []byte(note), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := Import(s, dir)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if n != 2 || !s.Find(a.ID).Done || s.Find(b.ID).Done {
+		t.Errorf("Import changed %d todos: %+v %+v", n, s.Find(a.ID), s.Find(b.ID))
+	}
+	if n, _ := Import(s, dir); n != 0 {
+		t.Errorf("second Import changed %d todos", n)
+	}
+	if label, _ := s.Undo(); label != "sync notes" || s.Find(a.ID).Done {
+		t.Errorf("undo = %q", label)
+	}
+}
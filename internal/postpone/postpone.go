@@ -0,0 +1,172 @@
+// Package postpone computes the dates todos move to when they are
+// postponed, either by a fixed step or to a date typed by the user.
+package postpone
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+const dateFormat = "2006-01-02"
+
+// Step is a fixed amount to postpone a todo by.
+type Step int
+
+const (
+	Day     Step = iota // one day
+	Weekday             // to the next Monday to Friday
+	Week                // seven days
+	Month               // one calendar month
+)
+
+// ParseStep parses a step name: "day", "weekday", "week" or "month".
+func ParseStep(name string) (Step, bool) {
+	switch strings.ToLower(name) {
+	case "day":
+		return Day, true
+	case "weekday":
+		return Weekday, true
+	case "week":
+		return Week, true
+	case "month":
+		return Month, true
+	}
+	return 0, false
+}
+
+// Date returns the date and precision todo t gets when postponed by step.
+// Steps count from the todo's date, or from today if that is later, so
+// postponing an overdue todo always moves it into the future. A month
+// todo moves to the following month and a year todo to the following year
+// whatever the step; floating todos count from today and become day
+// todos. If skip is non-nil, day todos that land on a day it reports
+// (such as a holiday) move on to the next day it does not.
+func Date(t store.Todo, step Step, today time.Time, skip func(time.Time) bool) (date, precision string) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	from := today
+	if d, err := time.Parse(dateFormat, t.Date); err == nil && d.After(from) {
+		from = d
+	}
+
+	switch t.DatePrecision {
+	case "year":
+		year := today.Year()
+		if d, err := time.Parse(dateFormat, t.Date); err == nil && d.Year() > year {
+			year = d.Year()
+		}
+		return time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC).Format(dateFormat), "year"
+	case "month":
+		start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if d, err := time.Parse(dateFormat, t.Date); err == nil && d.After(start) {
+			start = time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+		return start.AddDate(0, 1, 0).Format(dateFormat), "month"
+	}
+
+	var d time.Time
+	switch step {
+	case Weekday:
+		d = from.AddDate(0, 0, 1)
+		for isWeekend(d) {
+			d = d.AddDate(0, 0, 1)
+		}
+	case Week:
+		d = from.AddDate(0, 0, 7)
+	case Month:
+		d = addMonth(from)
+	default:
+		d = from.AddDate(0, 0, 1)
+	}
+	if skip != nil {
+		for i := 0; i < 366 && (skip(d) || step == Weekday && isWeekend(d)); i++ {
+			d = d.AddDate(0, 0, 1)
+		}
+	}
+	return d.Format(dateFormat), "day"
+}
+
+// Parse parses a date typed when postponing to a picked date: a day in
+// layout or as YYYY-MM-DD, a month (YYYY-MM), a year (YYYY), "today",
+// "tomorrow", a weekday name for the next such day, or an offset from
+// today such as "+3" or "+3d" (days), "+2w" (weeks) or "+1m" (months).
+func Parse(input string, today time.Time, layout string) (date, precision string, err error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch input {
+	case "today":
+		return today.Format(dateFormat), "day", nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1).Format(dateFormat), "day", nil
+	}
+	if wd, ok := weekdays[input]; ok {
+		days := (int(wd)-int(today.Weekday())+6)%7 + 1
+		return today.AddDate(0, 0, days).Format(dateFormat), "day", nil
+	}
+	if rest, ok := strings.CutPrefix(input, "+"); ok {
+		unit := "d"
+		if rest != "" && strings.ContainsAny(rest[len(rest)-1:], "dwm") {
+			rest, unit = rest[:len(rest)-1], rest[len(rest)-1:]
+		}
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 0 {
+			return "", "", fmt.Errorf("invalid offset %q (want e.g. +3d, +2w or +1m)", input)
+		}
+		d := today
+		switch unit {
+		case "w":
+			d = d.AddDate(0, 0, 7*n)
+		case "m":
+			for i := 0; i < n; i++ {
+				d = addMonth(d)
+			}
+		default:
+			d = d.AddDate(0, 0, n)
+		}
+		return d.Format(dateFormat), "day", nil
+	}
+
+	for _, l := range []string{layout, dateFormat} {
+		if t, err := time.Parse(l, input); err == nil {
+			return t.Format(dateFormat), "day", nil
+		}
+	}
+	if t, err := time.Parse("2006-01", input); err == nil {
+		return t.Format(dateFormat), "month", nil
+	}
+	if t, err := time.Parse("2006", input); err == nil {
+		return t.Format(dateFormat), "year", nil
+	}
+	return "", "", fmt.Errorf("invalid date %q", input)
+}
+
+// weekdays maps the accepted weekday names to weekdays.
+var weekdays = map[string]time.Weekday{
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+	"sun": time.Sunday, "sunday": time.Sunday,
+}
+
+// isWeekend reports whether d is a Saturday or Sunday.
+func isWeekend(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+// addMonth returns the same day of the next month, or its last day when
+// the month is shorter (January 31 becomes February 28 or 29).
+func addMonth(d time.Time) time.Time {
+	last := time.Date(d.Year(), d.Month()+2, 0, 0, 0, 0, 0, time.UTC).Day()
+	day := d.Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(d.Year(), d.Month()+1, day, 0, 0, 0, 0, time.UTC)
+}
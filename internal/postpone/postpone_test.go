@@ -0,0 +1,71 @@
+package postpone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+func TestDate(t *testing.T) {
+	today := time.Date(2026, 10, 16, 15, 0, 0, 0, time.Local) // a Friday
+	holiday := func(d time.Time) bool { return d.Format(dateFormat) == "2026-10-19" }
+
+	tests := []struct {
+		name          string
+		date, prec    string
+		step          Step
+		skip          func(time.Time) bool
+		want, wantPre string
+	}{
+		{"day", "2026-10-20", "day", Day, nil, "2026-10-21", "day"},
+		{"overdue counts from today", "2026-09-01", "day", Day, nil, "2026-10-17", "day"},
+		{"floating", "", "", Day, nil, "2026-10-17", "day"},
+		{"weekday skips the weekend", "2026-10-16", "day", Weekday, nil, "2026-10-19", "day"},
+		{"weekday skips holidays", "2026-10-16", "day", Weekday, holiday, "2026-10-20", "day"},
+		{"week", "2026-10-16", "day", Week, nil, "2026-10-23", "day"},
+		{"month", "2026-10-20", "day", Month, nil, "2026-11-20", "day"},
+		{"month clamps to the last day", "2027-01-31", "day", Month, nil, "2027-02-28", "day"},
+		{"holiday skipped", "2026-10-18", "day", Day, holiday, "2026-10-20", "day"},
+		{"month todo", "2026-12-01", "month", Day, nil, "2027-01-01", "month"},
+		{"overdue month todo", "2026-08-01", "month", Week, nil, "2026-11-01", "month"},
+		{"year todo", "2026-01-01", "year", Month, nil, "2027-01-01", "year"},
+	}
+	for _, tt := range tests {
+		todo := store.Todo{Date: tt.date, DatePrecision: tt.prec}
+		got, prec := Date(todo, tt.step, today, tt.skip)
+		if got != tt.want || prec != tt.wantPre {
+			t.Errorf("%s: Date = %s (%s), want %s (%s)", tt.name, got, prec, tt.want, tt.wantPre)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	today := time.Date(2026, 10, 16, 15, 0, 0, 0, time.Local) // a Friday
+	tests := []struct {
+		input, want, prec string
+	}{
+		{"today", "2026-10-16", "day"},
+		{"Tomorrow", "2026-10-17", "day"},
+		{"mon", "2026-10-19", "day"},
+		{"friday", "2026-10-23", "day"},
+		{"+3", "2026-10-19", "day"},
+		{"+2w", "2026-10-30", "day"},
+		{"+1m", "2026-11-16", "day"},
+		{"24.12.2026", "2026-12-24", "day"},
+		{"2026-12-24", "2026-12-24", "day"},
+		{"2027-03", "2027-03-01", "month"},
+		{"2027", "2027-01-01", "year"},
+	}
+	for _, tt := range tests {
+		got, prec, err := Parse(tt.input, today, "02.01.2006")
+		if err != nil || got != tt.want || prec != tt.prec {
+			t.Errorf("Parse(%q) = %s (%s), %v; want %s (%s)", tt.input, got, prec, err, tt.want, tt.prec)
+		}
+	}
+	for _, input := range []string{"", "soon", "+x", "+-1d", "2026-13-01"} {
+		if _, _, err := Parse(input, today, "02.01.2006"); err == nil {
+			t.Errorf("Parse(%q) succeeded", input)
+		}
+	}
+}
@@ -8,6 +8,9 @@ type KeyMap struct {
 	Down     key.Binding
 	PageUp   key.Binding
 	PageDown key.Binding
+	NextItem key.Binding
+	PrevItem key.Binding
+	Check    key.Binding
 	Close    key.Binding
 }
 
@@ -30,6 +33,18 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("pgdown", "f"),
 			key.WithHelp("pgdn", "page down"),
 		),
+		NextItem: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next subtask"),
+		),
+		PrevItem: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "prev subtask"),
+		),
+		Check: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space", "toggle subtask"),
+		),
 		Close: key.NewBinding(
 			key.WithKeys("esc", "q"),
 			key.WithHelp("esc", "close"),
@@ -42,3 +57,10 @@ func HelpBindings() []key.Binding {
 	km := DefaultKeyMap()
 	return []key.Binding{km.Up, km.Down, km.PageUp, km.PageDown, km.Close}
 }
+
+// ChecklistHelpBindings returns the preview key bindings shown when the
+// body contains subtasks.
+func ChecklistHelpBindings() []key.Binding {
+	km := DefaultKeyMap()
+	return []key.Binding{km.NextItem, km.Check, km.Up, km.Down, km.Close}
+}
@@ -1,6 +1,7 @@
 package preview
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -9,18 +10,31 @@ import (
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/antti/todo-calendar/internal/checklist"
+	"github.com/antti/todo-calendar/internal/store"
 	"github.com/antti/todo-calendar/internal/theme"
 )
 
 // CloseMsg is emitted when the user closes the preview overlay.
 type CloseMsg struct{}
 
+// ChecklistToggledMsg is emitted when the user checks or unchecks a subtask.
+// Body is the todo body with the checkbox flipped; the app persists it.
+type ChecklistToggledMsg struct {
+	TodoID int
+	Body   string
+}
+
 // Model represents the markdown preview overlay.
 type Model struct {
+	todoID    int
 	title     string
 	viewport  viewport.Model
 	renderer  *glamour.TermRenderer
 	rawBody   string
+	items     []checklist.Item
+	history   []store.Todo // occurrences of a repeating todo, by date
+	cursor    int
 	width     int
 	height    int
 	keys      KeyMap
@@ -28,16 +42,17 @@ type Model struct {
 	themeName string
 }
 
-// New creates a new preview model for the given todo title and body.
-func New(title, body, themeName string, t theme.Theme, width, height int) Model {
+// New creates a new preview model for the given todo ID, title and body.
+func New(todoID int, title, body, themeName string, t theme.Theme, width, height int) Model {
 	s := NewStyles(t)
 	keys := DefaultKeyMap()
+	items := checklist.Parse(body)
 
 	contentWidth := width - 4
 	if contentWidth < 10 {
 		contentWidth = 10
 	}
-	contentHeight := height - 4
+	contentHeight := height - 4 - checklistHeight(items)
 	if contentHeight < 1 {
 		contentHeight = 1
 	}
@@ -61,10 +76,12 @@ func New(title, body, themeName string, t theme.Theme, width, height int) Model
 	vp.SetContent(rendered)
 
 	return Model{
+		todoID:    todoID,
 		title:     title,
 		viewport:  vp,
 		renderer:  renderer,
 		rawBody:   body,
+		items:     items,
 		width:     width,
 		height:    height,
 		keys:      keys,
@@ -80,6 +97,18 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		if key.Matches(msg, m.keys.Close) {
 			return m, func() tea.Msg { return CloseMsg{} }
 		}
+		if len(m.items) > 0 {
+			switch {
+			case key.Matches(msg, m.keys.NextItem):
+				m.cursor = (m.cursor + 1) % len(m.items)
+				return m, nil
+			case key.Matches(msg, m.keys.PrevItem):
+				m.cursor = (m.cursor - 1 + len(m.items)) % len(m.items)
+				return m, nil
+			case key.Matches(msg, m.keys.Check):
+				return m.toggleItem()
+			}
+		}
 		// Forward to viewport for scrolling
 		var cmd tea.Cmd
 		m.viewport, cmd = m.viewport.Update(msg)
@@ -103,6 +132,10 @@ func (m Model) View() string {
 	b.WriteString(titleBar)
 	b.WriteString("\n")
 
+	if len(m.items) > 0 {
+		b.WriteString(m.checklistView())
+	}
+
 	b.WriteString(m.viewport.View())
 
 	return m.styles.Border.
@@ -117,18 +150,79 @@ func (m *Model) SetTheme(t theme.Theme) {
 	m.rebuildContent()
 }
 
+// SetHistory lists the occurrences of the previewed todo's series below
+// its body.
+func (m *Model) SetHistory(todos []store.Todo) {
+	m.history = todos
+	m.rebuildContent()
+}
+
 // HelpBindings returns preview key bindings for the help bar.
 func (m Model) HelpBindings() []key.Binding {
+	if len(m.items) > 0 {
+		return ChecklistHelpBindings()
+	}
 	return HelpBindings()
 }
 
+// toggleItem flips the subtask under the cursor and asks the app to persist
+// the updated body.
+func (m Model) toggleItem() (Model, tea.Cmd) {
+	item := m.items[m.cursor]
+	m.rawBody = checklist.Toggle(m.rawBody, item.Line)
+	m.items = checklist.Parse(m.rawBody)
+	if m.cursor >= len(m.items) {
+		m.cursor = max(0, len(m.items)-1)
+	}
+	m.rebuildContent()
+	id, body := m.todoID, m.rawBody
+	return m, func() tea.Msg { return ChecklistToggledMsg{TodoID: id, Body: body} }
+}
+
+// checklistHeight returns the number of lines the subtask list takes up
+// above the rendered body: a header, one line per item and a blank line.
+func checklistHeight(items []checklist.Item) int {
+	if len(items) == 0 {
+		return 0
+	}
+	return len(items) + 2
+}
+
+// checklistView renders the interactive subtask list with its progress.
+func (m Model) checklistView() string {
+	var b strings.Builder
+	done, total := 0, len(m.items)
+	for _, it := range m.items {
+		if it.Done {
+			done++
+		}
+	}
+	b.WriteString(m.styles.Hint.Render(fmt.Sprintf("Subtasks %d/%d", done, total)))
+	b.WriteString("\n")
+	for i, it := range m.items {
+		if i == m.cursor {
+			b.WriteString(m.styles.Cursor.Render("> "))
+		} else {
+			b.WriteString("  ")
+		}
+		if it.Done {
+			b.WriteString(m.styles.ItemDone.Render("[x] " + it.Text))
+		} else {
+			b.WriteString("[ ] " + it.Text)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
 // rebuildContent recreates the renderer and viewport content for the current dimensions.
 func (m *Model) rebuildContent() {
 	contentWidth := m.width - 4
 	if contentWidth < 10 {
 		contentWidth = 10
 	}
-	contentHeight := m.height - 4
+	contentHeight := m.height - 4 - checklistHeight(m.items)
 	if contentHeight < 1 {
 		contentHeight = 1
 	}
@@ -151,6 +245,38 @@ func (m *Model) rebuildContent() {
 		rendered = m.rawBody
 	}
 
+	if len(m.history) > 0 {
+		rendered += "\n\n" + m.historyView()
+	}
+
 	m.viewport = viewport.New(contentWidth, contentHeight)
 	m.viewport.SetContent(rendered)
 }
+
+// historyView renders the occurrences of a repeating todo, marking the
+// previewed one.
+func (m Model) historyView() string {
+	var b strings.Builder
+	b.WriteString(m.styles.Hint.Render(fmt.Sprintf("History (%d occurrences)", len(m.history))))
+	for _, t := range m.history {
+		b.WriteString("\n")
+		if t.ID == m.todoID {
+			b.WriteString(m.styles.Cursor.Render("> "))
+		} else {
+			b.WriteString("  ")
+		}
+		date := t.Date
+		if date == "" {
+			date = "no date"
+		}
+		if t.Done {
+			b.WriteString(m.styles.ItemDone.Render("[x] " + date))
+			if done, _, ok := strings.Cut(t.CompletedAt, " "); ok {
+				b.WriteString(m.styles.Hint.Render("  done " + done))
+			}
+		} else {
+			b.WriteString("[ ] " + date)
+		}
+	}
+	return b.String()
+}
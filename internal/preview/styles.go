@@ -10,9 +10,11 @@ import (
 
 // Styles holds themed lipgloss styles for the preview overlay.
 type Styles struct {
-	Title  lipgloss.Style
-	Border lipgloss.Style
-	Hint   lipgloss.Style
+	Title    lipgloss.Style
+	Border   lipgloss.Style
+	Hint     lipgloss.Style
+	Cursor   lipgloss.Style
+	ItemDone lipgloss.Style
 }
 
 // NewStyles builds preview styles from the given theme.
@@ -28,6 +30,11 @@ func NewStyles(t theme.Theme) Styles {
 			Padding(0, 1),
 		Hint: lipgloss.NewStyle().
 			Foreground(t.MutedFg),
+		Cursor: lipgloss.NewStyle().
+			Foreground(t.AccentFg),
+		ItemDone: lipgloss.NewStyle().
+			Foreground(t.CompletedFg).
+			Strikethrough(true),
 	}
 }
 
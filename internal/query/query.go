@@ -0,0 +1,268 @@
+// Package query parses the search overlay's query language: free text
+// mixed with field filters such as "p:1", "done:no",
+// "date:2026-10..2026-12", "prec:month", "has:body", "recurring:yes" and
+// "#tag". A filter prefixed with "-" is negated. The store translates a
+// parsed Query into SQL.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Field names a todo attribute a Filter tests.
+type Field string
+
+const (
+	FieldPriority  Field = "p"         // Min..Max priority (0 = none)
+	FieldDone      Field = "done"      // Value "yes" or "no"
+	FieldDate      Field = "date"      // From..To, or undated when both are ""
+	FieldPrecision Field = "prec"      // Value "day", "month", "year" or "none"
+	FieldHas       Field = "has"       // Value "body", "time", "tags", "project" or "reminders"
+	FieldRecurring Field = "recurring" // Value "yes" or "no"
+	FieldTag       Field = "tag"       // Value is the tag name
+)
+
+// aliases maps the accepted spellings of each field to the field.
+var aliases = map[string]Field{
+	"p":         FieldPriority,
+	"prio":      FieldPriority,
+	"priority":  FieldPriority,
+	"done":      FieldDone,
+	"date":      FieldDate,
+	"due":       FieldDate,
+	"prec":      FieldPrecision,
+	"precision": FieldPrecision,
+	"has":       FieldHas,
+	"recurring": FieldRecurring,
+	"tag":       FieldTag,
+}
+
+// Query is a parsed search query: free text that has to match, and
+// filters that all have to hold.
+type Query struct {
+	Text    string
+	Filters []Filter
+}
+
+// Empty reports whether the query has neither text nor filters.
+func (q Query) Empty() bool {
+	return q.Text == "" && len(q.Filters) == 0
+}
+
+// Filter is a single field filter of a Query.
+type Filter struct {
+	Field  Field
+	Negate bool
+	Value  string // for done, prec, has, recurring and tag
+	Min    int    // priority range
+	Max    int
+	From   string // date range, as YYYY-MM-DD, inclusive; "" is open
+	To     string
+}
+
+// String returns the filter in its canonical query syntax.
+func (f Filter) String() string {
+	var s string
+	switch f.Field {
+	case FieldTag:
+		s = "#" + f.Value
+	case FieldPriority:
+		s = "p:" + strconv.Itoa(f.Min)
+		if f.Max != f.Min {
+			s += ".." + strconv.Itoa(f.Max)
+		}
+	case FieldDate:
+		switch {
+		case f.From == "" && f.To == "":
+			s = "date:none"
+		case f.From == f.To:
+			s = "date:" + f.From
+		default:
+			s = "date:" + f.From + ".." + f.To
+		}
+	default:
+		s = string(f.Field) + ":" + f.Value
+	}
+	if f.Negate {
+		s = "-" + s
+	}
+	return s
+}
+
+// Parse splits input into free text and filters. Words of the form
+// "field:value" with a known field, and "#tag" words, are filters; all
+// other words are text. Relative dates (today, tomorrow, yesterday) are
+// resolved against now.
+func Parse(input string, now time.Time) (Query, error) {
+	var q Query
+	var words []string
+	for _, word := range strings.Fields(input) {
+		negate := false
+		term := word
+		if len(term) > 1 && term[0] == '-' {
+			negate = true
+			term = term[1:]
+		}
+
+		if strings.HasPrefix(term, "#") {
+			tag := NormalizeTag(term)
+			if tag == "" {
+				continue
+			}
+			q.Filters = append(q.Filters, Filter{Field: FieldTag, Negate: negate, Value: tag})
+			continue
+		}
+
+		name, value, ok := strings.Cut(term, ":")
+		field, known := aliases[strings.ToLower(name)]
+		if !ok || !known {
+			words = append(words, word)
+			continue
+		}
+		f, err := parseFilter(field, strings.ToLower(value), now)
+		if err != nil {
+			return Query{}, err
+		}
+		f.Negate = negate
+		q.Filters = append(q.Filters, f)
+	}
+	q.Text = strings.Join(words, " ")
+	return q, nil
+}
+
+// parseFilter parses the value of a field filter.
+func parseFilter(field Field, value string, now time.Time) (Filter, error) {
+	f := Filter{Field: field}
+	switch field {
+	case FieldPriority:
+		lo, hi, isRange := strings.Cut(value, "..")
+		if !isRange {
+			hi = lo
+		}
+		first, err1 := parsePriority(lo)
+		last, err2 := parsePriority(hi)
+		if err1 != nil || err2 != nil || first > last {
+			return f, fmt.Errorf("invalid priority %q (want 0-4 or a range like 1..2)", value)
+		}
+		f.Min, f.Max = first, last
+
+	case FieldDone, FieldRecurring:
+		switch value {
+		case "yes", "y", "true":
+			f.Value = "yes"
+		case "no", "n", "false":
+			f.Value = "no"
+		default:
+			return f, fmt.Errorf("invalid %s value %q (want yes or no)", field, value)
+		}
+
+	case FieldDate:
+		if value == "none" {
+			return f, nil
+		}
+		lo, hi, isRange := strings.Cut(value, "..")
+		if !isRange {
+			hi = lo
+		}
+		if lo != "" {
+			start, _, err := parseDate(lo, now)
+			if err != nil {
+				return f, err
+			}
+			f.From = start
+		}
+		if hi != "" {
+			_, end, err := parseDate(hi, now)
+			if err != nil {
+				return f, err
+			}
+			f.To = end
+		}
+		if f.From == "" && f.To == "" || f.From != "" && f.To != "" && f.From > f.To {
+			return f, fmt.Errorf("invalid date range %q", value)
+		}
+
+	case FieldPrecision:
+		if value != "day" && value != "month" && value != "year" && value != "none" {
+			return f, fmt.Errorf("invalid precision %q (want day, month, year or none)", value)
+		}
+		f.Value = value
+
+	case FieldHas:
+		switch value {
+		case "body", "notes":
+			f.Value = "body"
+		case "time":
+			f.Value = "time"
+		case "tag", "tags":
+			f.Value = "tags"
+		case "project":
+			f.Value = "project"
+		case "reminder", "reminders":
+			f.Value = "reminders"
+		default:
+			return f, fmt.Errorf("invalid has value %q (want body, time, tags, project or reminders)", value)
+		}
+
+	case FieldTag:
+		f.Value = NormalizeTag(value)
+		if f.Value == "" {
+			return f, fmt.Errorf("empty tag")
+		}
+	}
+	return f, nil
+}
+
+// parsePriority parses a priority level, 0 (none) to 4.
+func parsePriority(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(s, "p"))
+	if err != nil || n < 0 || n > 4 {
+		return 0, fmt.Errorf("invalid priority %q", s)
+	}
+	return n, nil
+}
+
+// parseDate parses a date filter bound and returns the first and last day
+// it covers: a day (YYYY-MM-DD, today, tomorrow, yesterday), a month
+// (YYYY-MM) or a year (YYYY).
+func parseDate(s string, now time.Time) (string, string, error) {
+	const day = "2006-01-02"
+	switch s {
+	case "today":
+		return now.Format(day), now.Format(day), nil
+	case "tomorrow":
+		d := now.AddDate(0, 0, 1).Format(day)
+		return d, d, nil
+	case "yesterday":
+		d := now.AddDate(0, 0, -1).Format(day)
+		return d, d, nil
+	}
+	if t, err := time.Parse(day, s); err == nil {
+		return t.Format(day), t.Format(day), nil
+	}
+	if t, err := time.Parse("2006-01", s); err == nil {
+		return t.Format(day), t.AddDate(0, 1, -1).Format(day), nil
+	}
+	if t, err := time.Parse("2006", s); err == nil {
+		return t.Format(day), t.AddDate(1, 0, -1).Format(day), nil
+	}
+	return "", "", fmt.Errorf("invalid date %q (want YYYY-MM-DD, YYYY-MM, YYYY, today, tomorrow or yesterday)", s)
+}
+
+// NormalizeTag converts user input into a canonical tag name: lowercase,
+// without a leading '#', with whitespace and commas replaced by '-'.
+// Returns "" if nothing usable remains.
+func NormalizeTag(tag string) string {
+	tag = strings.TrimLeft(strings.TrimSpace(tag), "#")
+	tag = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == ',' {
+			return '-'
+		}
+		return unicode.ToLower(r)
+	}, tag)
+	return strings.Trim(tag, "-")
+}
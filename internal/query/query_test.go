@@ -0,0 +1,56 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input   string
+		text    string
+		filters []string // canonical String() of each filter
+	}{
+		{"dentist", "dentist", nil},
+		{"p:1 report", "report", []string{"p:1"}},
+		{"priority:1..2", "", []string{"p:1..2"}},
+		{"done:no #Work", "", []string{"done:no", "#work"}},
+		{"date:2026-10..2026-12", "", []string{"date:2026-10-01..2026-12-31"}},
+		{"date:2026", "", []string{"date:2026-01-01..2026-12-31"}},
+		{"date:today", "", []string{"date:2026-10-17"}},
+		{"due:..2026-10", "", []string{"date:..2026-10-31"}},
+		{"date:tomorrow..", "", []string{"date:2026-10-18.."}},
+		{"date:none", "", []string{"date:none"}},
+		{"prec:month has:body recurring:yes", "", []string{"prec:month", "has:body", "recurring:yes"}},
+		{"-has:tags -#home", "", []string{"-has:tags", "-#home"}},
+		{"meeting re: budget 10:30", "meeting re: budget 10:30", nil},
+		{"- p:0", "-", []string{"p:0"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input, now)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		var filters []string
+		for _, f := range q.Filters {
+			filters = append(filters, f.String())
+		}
+		if q.Text != tt.text || strings.Join(filters, " ") != strings.Join(tt.filters, " ") {
+			t.Errorf("Parse(%q) = text %q, filters %v; want %q, %v", tt.input, q.Text, filters, tt.text, tt.filters)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"p:5", "p:2..1", "p:high", "done:maybe", "date:2026-13", "date:2026-12..2026-01",
+		"date:..", "prec:week", "has:colour", "recurring:", "tag:",
+	} {
+		if _, err := Parse(input, time.Now()); err == nil {
+			t.Errorf("Parse(%q) succeeded", input)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package recurring
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/antti/todo-calendar/internal/store"
@@ -10,53 +12,176 @@ import (
 
 const dateFormat = "2006-01-02"
 
-// windowDays is the number of days to look ahead (today + 6).
-const windowDays = 7
+// DefaultLookahead is the number of days schedules look ahead (today
+// through today+6) unless they set their own.
+const DefaultLookahead = 7
 
-// AutoCreate generates scheduled todos for the next 7 days (today through
-// today+6). It iterates all schedules, checks cadence matching, deduplicates,
-// and fills template placeholders from stored defaults.
-func AutoCreate(s store.TodoStore) {
-	AutoCreateForDate(s, time.Now())
+// maxDays bounds both the look-ahead of a schedule and how far back missed
+// occurrences are caught up.
+const maxDays = 366
+
+// Catch-up modes of a schedule (store.Schedule.CatchUp): what happens to
+// occurrences that passed while the app was not running.
+const (
+	CatchUpNone    = ""        // leave them out
+	CatchUpAll     = "all"     // create a todo for each
+	CatchUpSummary = "summary" // create one todo listing them
+)
+
+// CatchUpModes lists the catch-up modes in display order.
+var CatchUpModes = []string{CatchUpNone, CatchUpAll, CatchUpSummary}
+
+// CatchUpLabel returns a short description of a catch-up mode.
+func CatchUpLabel(mode string) string {
+	switch mode {
+	case CatchUpAll:
+		return "create missed"
+	case CatchUpSummary:
+		return "summarize missed"
+	default:
+		return "skip missed"
+	}
+}
+
+// Lookahead returns the number of days a schedule creates todos for,
+// today included.
+func Lookahead(sched store.Schedule) int {
+	switch {
+	case sched.LookaheadDays <= 0:
+		return DefaultLookahead
+	case sched.LookaheadDays > maxDays:
+		return maxDays
+	default:
+		return sched.LookaheadDays
+	}
+}
+
+// AutoCreate generates scheduled todos for the look-ahead window of each
+// schedule (7 days, today through today+6, by default). It iterates all
+// schedules that are not paused, catches up occurrences missed since the
+// last run, checks cadence matching within each schedule's start and end
+// dates, maximum number of occurrences and exceptions, applies each schedule's holiday policy with the
+// holidays isHoliday reports (nil for none), deduplicates, and fills
+// template placeholders from stored defaults.
+func AutoCreate(s store.TodoStore, isHoliday func(time.Time) bool) {
+	AutoCreateForDate(s, time.Now(), isHoliday)
 }
 
 // AutoCreateForDate is the testable core of AutoCreate, accepting an explicit
 // "today" time so tests can pin the date.
-func AutoCreateForDate(s store.TodoStore, today time.Time) {
+func AutoCreateForDate(s store.TodoStore, today time.Time, isHoliday func(time.Time) bool) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
 	schedules := s.ListSchedules()
 	for _, sched := range schedules {
-		ruleStr := buildRuleString(sched.CadenceType, sched.CadenceValue)
-		rule, err := ParseRule(ruleStr)
-		if err != nil {
-			// Bad cadence -- skip silently.
+		if sched.Paused {
+			// Occurrences while paused are not missed: a resumed schedule
+			// starts from the day it is resumed.
+			if yesterday := today.AddDate(0, 0, -1).Format(dateFormat); yesterday > sched.GeneratedThrough {
+				s.SetGeneratedThrough(sched.ID, yesterday)
+			}
 			continue
 		}
-
-		tpl := s.FindTemplate(sched.TemplateID)
-		if tpl == nil {
-			// Orphan schedule -- template deleted. Skip.
+		rule, title, body, ok := Resolve(s, sched)
+		if !ok {
 			continue
 		}
+		occ := NewOccurrences(sched, rule)
 
-		defaults := parseDefaults(sched.PlaceholderDefaults)
-		body, err := tmpl.ExecuteTemplate(tpl.Content, defaults)
-		if err != nil {
-			// Template execution failed -- skip.
-			continue
+		// Occurrences between the last day generated and today passed
+		// while the app was not running.
+		if last, err := time.ParseInLocation(dateFormat, sched.GeneratedThrough, today.Location()); err == nil {
+			from := last.AddDate(0, 0, 1)
+			if limit := today.AddDate(0, 0, -maxDays); from.Before(limit) {
+				from = limit
+			}
+			catchUp(s, sched, occ, title, body, from, today, isHoliday)
 		}
 
-		for i := 0; i < windowDays; i++ {
-			d := today.AddDate(0, 0, i)
-			if !rule.MatchesDate(d) {
-				continue
-			}
-			dateStr := d.Format(dateFormat)
-			if s.TodoExistsForSchedule(sched.ID, dateStr) {
-				continue
+		end := today.AddDate(0, 0, Lookahead(sched))
+		for d := today; d.Before(end); d = d.AddDate(0, 0, 1) {
+			if date, ok := pendingOccurrence(s, sched, occ, d, isHoliday); ok {
+				s.AddShiftedTodo(title, date, d.Format(dateFormat), body, sched.ID)
 			}
-			s.AddScheduledTodo(tpl.Name, dateStr, body, sched.ID)
 		}
+		through := end.AddDate(0, 0, -1).Format(dateFormat)
+		if through > sched.GeneratedThrough {
+			s.SetGeneratedThrough(sched.ID, through)
+		}
+	}
+}
+
+// catchUp handles the occurrences of a schedule from from up to (not
+// including) today that have no todo, according to its catch-up mode.
+// Occurrences a holiday policy moves to today or later are not missed and
+// get their todo in any mode.
+func catchUp(s store.TodoStore, sched store.Schedule, occ Occurrences, title, body string, from, today time.Time, isHoliday func(time.Time) bool) {
+	var missed []string
+	for d := from; d.Before(today); d = d.AddDate(0, 0, 1) {
+		date, ok := pendingOccurrence(s, sched, occ, d, isHoliday)
+		if !ok {
+			continue
+		}
+		switch {
+		case date >= today.Format(dateFormat) || sched.CatchUp == CatchUpAll:
+			s.AddShiftedTodo(title, date, d.Format(dateFormat), body, sched.ID)
+		case sched.CatchUp == CatchUpSummary:
+			missed = append(missed, date)
+		}
+	}
+	if len(missed) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("Occurrences missed while todo-calendar was not running:\n\n")
+	for _, date := range missed {
+		d, _ := time.Parse(dateFormat, date)
+		b.WriteString("- [ ] " + d.Format("Mon Jan 2, 2006") + "\n")
+	}
+	s.Batch("catch up", func() {
+		t := s.Add(fmt.Sprintf("Missed: %s (%d)", title, len(missed)), today.Format(dateFormat), "day", 0)
+		if t.ID != 0 {
+			s.UpdateBody(t.ID, b.String())
+		}
+	})
+}
+
+// pendingOccurrence reports whether the schedule has an occurrence on d
+// that still needs a todo, and the date ("YYYY-MM-DD") its holiday policy
+// gives that todo.
+func pendingOccurrence(s store.TodoStore, sched store.Schedule, occ Occurrences, d time.Time, isHoliday func(time.Time) bool) (string, bool) {
+	if !occ.Matches(d) {
+		return "", false
+	}
+	date, ok := occ.Due(d, sched.HolidayPolicy, isHoliday)
+	// Todos remember the occurrence they were created for, so a shifted
+	// one is not created again.
+	if !ok || s.TodoExistsForSchedule(sched.ID, d.Format(dateFormat)) {
+		return "", false
+	}
+	return date.Format(dateFormat), true
+}
+
+// Resolve returns the cadence of a schedule and the title and body of the
+// todos it creates. ok is false for schedules that create nothing: a bad
+// cadence, a deleted template or a template that fails to execute.
+func Resolve(s store.TodoStore, sched store.Schedule) (rule ScheduleRule, title, body string, ok bool) {
+	rule, err := ParseRule(buildRuleString(sched.CadenceType, sched.CadenceValue))
+	if err != nil {
+		return ScheduleRule{}, "", "", false
+	}
+
+	tpl := s.FindTemplate(sched.TemplateID)
+	if tpl == nil {
+		// Orphan schedule -- template deleted.
+		return ScheduleRule{}, "", "", false
+	}
+
+	body, err = tmpl.ExecuteTemplate(tpl.Content, parseDefaults(sched.PlaceholderDefaults))
+	if err != nil {
+		return ScheduleRule{}, "", "", false
 	}
+	return rule, tpl.Name, body, true
 }
 
 // buildRuleString concatenates cadence type and value into the rule string
@@ -65,6 +190,7 @@ func AutoCreateForDate(s store.TodoStore, today time.Time) {
 //	"daily" + "" -> "daily"
 //	"weekly" + "mon,fri" -> "weekly:mon,fri"
 //	"monthly" + "15" -> "monthly:15"
+//	"rrule" + "FREQ=MONTHLY;BYDAY=-1FR" -> "rrule:FREQ=MONTHLY;BYDAY=-1FR"
 func buildRuleString(cadenceType, cadenceValue string) string {
 	if cadenceValue == "" {
 		return cadenceType
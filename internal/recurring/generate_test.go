@@ -2,9 +2,11 @@ package recurring
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/antti/todo-calendar/internal/query"
 	"github.com/antti/todo-calendar/internal/store"
 )
 
@@ -15,13 +17,15 @@ type fakeStore struct {
 	templates  map[int]*store.Template
 	existing   map[string]bool // key: "scheduleID:date"
 	added      []addedTodo
+	plain      []addedTodo // todos added with Add, e.g. catch-up summaries
 }
 
 type addedTodo struct {
-	text       string
-	date       string
-	body       string
-	scheduleID int
+	text         string
+	date         string
+	scheduleDate string
+	body         string
+	scheduleID   int
 }
 
 func (f *fakeStore) ListSchedules() []store.Schedule          { return f.schedules }
@@ -30,22 +34,53 @@ func (f *fakeStore) TodoExistsForSchedule(scheduleID int, date string) bool {
 	key := fakeKey(scheduleID, date)
 	return f.existing[key]
 }
+func (f *fakeStore) FindScheduled(scheduleID int, date string) *store.Todo { return nil }
 func (f *fakeStore) AddScheduledTodo(text, date, body string, scheduleID int) store.Todo {
-	f.added = append(f.added, addedTodo{text: text, date: date, body: body, scheduleID: scheduleID})
+	return f.AddShiftedTodo(text, date, date, body, scheduleID)
+}
+func (f *fakeStore) AddShiftedTodo(text, date, scheduleDate, body string, scheduleID int) store.Todo {
+	f.added = append(f.added, addedTodo{text: text, date: date, scheduleDate: scheduleDate, body: body, scheduleID: scheduleID})
 	// Mark as existing so dedup works within same run
 	if f.existing == nil {
 		f.existing = make(map[string]bool)
 	}
-	f.existing[fakeKey(scheduleID, date)] = true
+	f.existing[fakeKey(scheduleID, scheduleDate)] = true
 	return store.Todo{}
 }
+func (f *fakeStore) SetHolidayPolicy(id int, policy string) error { return nil }
+func (f *fakeStore) SetScheduleWindow(id, lookaheadDays int, catchUp string) error {
+	return nil
+}
+func (f *fakeStore) SetGeneratedThrough(id int, date string) error {
+	for i := range f.schedules {
+		if f.schedules[i].ID == id {
+			f.schedules[i].GeneratedThrough = date
+		}
+	}
+	return nil
+}
+func (f *fakeStore) SetScheduleRange(id int, startDate, endDate string, maxOccurrences int) error {
+	return nil
+}
+func (f *fakeStore) SetSchedulePaused(id int, paused bool) error {
+	for i := range f.schedules {
+		if f.schedules[i].ID == id {
+			f.schedules[i].Paused = paused
+		}
+	}
+	return nil
+}
+func (f *fakeStore) SetScheduleException(id int, date string, skip bool) error { return nil }
 
 func fakeKey(scheduleID int, date string) string {
 	return fmt.Sprintf("%d:%s", scheduleID, date)
 }
 
 // Stub methods to satisfy store.TodoStore interface.
-func (f *fakeStore) Add(text, date, datePrecision string, priority int) store.Todo { return store.Todo{} }
+func (f *fakeStore) Add(text, date, datePrecision string, priority int) store.Todo {
+	f.plain = append(f.plain, addedTodo{text: text, date: date})
+	return store.Todo{ID: len(f.plain)}
+}
 func (f *fakeStore) Toggle(id int)                                     {}
 func (f *fakeStore) Delete(id int)                                     {}
 func (f *fakeStore) Find(id int) *store.Todo                           { return nil }
@@ -60,7 +95,12 @@ func (f *fakeStore) IncompleteTodosPerDay(y int, m time.Month) map[int]int { ret
 func (f *fakeStore) TotalTodosPerDay(y int, m time.Month) map[int]int { return nil }
 func (f *fakeStore) TodoCountsByMonth() []store.MonthCount            { return nil }
 func (f *fakeStore) FloatingTodoCounts() store.FloatingCount          { return store.FloatingCount{} }
-func (f *fakeStore) UpdateBody(id int, body string)                   {}
+func (f *fakeStore) UpdateBody(id int, body string) {
+	if id > 0 && id <= len(f.plain) {
+		f.plain[id-1].body = body
+	}
+}
+func (f *fakeStore) SetTime(id int, startTime string, duration int)   {}
 func (f *fakeStore) AddTemplate(name, content string) (store.Template, error) {
 	return store.Template{}, nil
 }
@@ -77,8 +117,54 @@ func (f *fakeStore) UpdateSchedule(id int, cadenceType, cadenceValue, placeholde
 }
 func (f *fakeStore) HighestPriorityPerDay(y int, m time.Month) map[int]int { return nil }
 func (f *fakeStore) SwapOrder(id1, id2 int)      {}
-func (f *fakeStore) SearchTodos(query string) []store.Todo { return nil }
+func (f *fakeStore) SearchTodos(text string) []store.SearchResult { return nil }
+func (f *fakeStore) QueryTodos(q query.Query) []store.SearchResult { return nil }
+func (f *fakeStore) SaveSearch(name, q string) (store.SavedSearch, error) {
+	return store.SavedSearch{}, nil
+}
+func (f *fakeStore) ListSavedSearches() []store.SavedSearch { return nil }
+func (f *fakeStore) DeleteSavedSearch(id int)               {}
+func (f *fakeStore) SavedSearchCounts() []store.SavedSearchCount { return nil }
 func (f *fakeStore) EnsureSortOrder()             {}
+func (f *fakeStore) SetTags(id int, tags []string)  {}
+func (f *fakeStore) AddTag(id int, tag string)       {}
+func (f *fakeStore) RemoveTag(id int, tag string)    {}
+func (f *fakeStore) TodosWithTag(tag string) []store.Todo { return nil }
+func (f *fakeStore) ListTags() []string              { return nil }
+func (f *fakeStore) AddProject(name string) (store.Project, error) {
+	return store.Project{}, nil
+}
+func (f *fakeStore) ListProjects() []store.Project              { return nil }
+func (f *fakeStore) FindProject(id int) *store.Project          { return nil }
+func (f *fakeStore) FindProjectByName(name string) *store.Project { return nil }
+func (f *fakeStore) RenameProject(id int, name string) error    { return nil }
+func (f *fakeStore) DeleteProject(id int)                       {}
+func (f *fakeStore) SetProject(id int, projectID int)           {}
+func (f *fakeStore) ProjectCounts() []store.ProjectCount        { return nil }
+func (f *fakeStore) SetReminders(id int, offsets []int)             {}
+func (f *fakeStore) ListReminders() []store.Reminder                  { return nil }
+func (f *fakeStore) MarkReminderFired(id int, firedFor string)        {}
+func (f *fakeStore) TrashedTodos() []store.Todo                        { return nil }
+func (f *fakeStore) Restore(id int)                                   {}
+func (f *fakeStore) Purge(id int)                                     {}
+func (f *fakeStore) PurgeTrash(before time.Time) int                  { return 0 }
+func (f *fakeStore) Archive(id int)                                   {}
+func (f *fakeStore) Unarchive(id int)                                 {}
+func (f *fakeStore) ArchiveCompleted(before time.Time) int            { return 0 }
+func (f *fakeStore) ArchivedTodos(from, to string) []store.Todo       { return nil }
+func (f *fakeStore) OverdueTodos(today string) []store.Todo           { return nil }
+func (f *fakeStore) RollOver(id int, today string)                    {}
+func (f *fakeStore) SetRepeat(id int, repeat string)                   {}
+func (f *fakeStore) AddOccurrence(id int, date string) store.Todo      { return store.Todo{} }
+func (f *fakeStore) SeriesTodos(seriesID int) []store.Todo             { return nil }
+func (f *fakeStore) FindByUID(uid string) *store.Todo                  { return nil }
+func (f *fakeStore) SetUID(id int, uid string)                         {}
+func (f *fakeStore) DatabaseID() string                                { return "" }
+func (f *fakeStore) Backup() (*store.Backup, error)                    { return nil, nil }
+func (f *fakeStore) RestoreBackup(b *store.Backup) error               { return nil }
+func (f *fakeStore) Batch(label string, fn func())                    { fn() }
+func (f *fakeStore) Undo() (string, bool)                             { return "", false }
+func (f *fakeStore) Redo() (string, bool)                             { return "", false }
 func (f *fakeStore) Save() error                  { return nil }
 
 func TestAutoCreateDailySchedule(t *testing.T) {
@@ -94,7 +180,7 @@ func TestAutoCreateDailySchedule(t *testing.T) {
 	}
 
 	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC) // Monday
-	AutoCreateForDate(fs, today)
+	AutoCreateForDate(fs, today, nil)
 
 	if len(fs.added) != 7 {
 		t.Fatalf("daily schedule: want 7 todos, got %d", len(fs.added))
@@ -129,7 +215,7 @@ func TestAutoCreateWeeklySchedule(t *testing.T) {
 	}
 
 	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC) // Monday
-	AutoCreateForDate(fs, today)
+	AutoCreateForDate(fs, today, nil)
 
 	if len(fs.added) != 2 {
 		t.Fatalf("weekly schedule: want 2 todos, got %d", len(fs.added))
@@ -156,7 +242,7 @@ func TestAutoCreateMonthlySchedule(t *testing.T) {
 	}
 
 	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
-	AutoCreateForDate(fs, today)
+	AutoCreateForDate(fs, today, nil)
 
 	if len(fs.added) != 1 {
 		t.Fatalf("monthly schedule: want 1 todo, got %d", len(fs.added))
@@ -179,7 +265,7 @@ func TestAutoCreateMonthlyNotInWindow(t *testing.T) {
 	}
 
 	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
-	AutoCreateForDate(fs, today)
+	AutoCreateForDate(fs, today, nil)
 
 	if len(fs.added) != 0 {
 		t.Fatalf("monthly schedule (out of window): want 0 todos, got %d", len(fs.added))
@@ -199,10 +285,10 @@ func TestAutoCreateDedup(t *testing.T) {
 	}
 
 	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
-	AutoCreateForDate(fs, today)
+	AutoCreateForDate(fs, today, nil)
 	count1 := len(fs.added)
 
-	AutoCreateForDate(fs, today)
+	AutoCreateForDate(fs, today, nil)
 	count2 := len(fs.added)
 
 	if count1 != 7 {
@@ -232,7 +318,7 @@ func TestAutoCreatePlaceholderDefaults(t *testing.T) {
 	}
 
 	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
-	AutoCreateForDate(fs, today)
+	AutoCreateForDate(fs, today, nil)
 
 	if len(fs.added) != 7 {
 		t.Fatalf("want 7 todos, got %d", len(fs.added))
@@ -256,7 +342,7 @@ func TestAutoCreateEmptyPlaceholderDefaults(t *testing.T) {
 	}
 
 	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
-	AutoCreateForDate(fs, today)
+	AutoCreateForDate(fs, today, nil)
 
 	if len(fs.added) != 7 {
 		t.Fatalf("want 7 todos, got %d", len(fs.added))
@@ -279,7 +365,7 @@ func TestAutoCreateMissingTemplate(t *testing.T) {
 	}
 
 	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
-	AutoCreateForDate(fs, today) // Should not panic
+	AutoCreateForDate(fs, today, nil) // Should not panic
 
 	if len(fs.added) != 0 {
 		t.Fatalf("missing template: want 0 todos, got %d", len(fs.added))
@@ -299,9 +385,73 @@ func TestAutoCreateBadCadence(t *testing.T) {
 	}
 
 	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
-	AutoCreateForDate(fs, today) // Should not panic
+	AutoCreateForDate(fs, today, nil) // Should not panic
 
 	if len(fs.added) != 0 {
 		t.Fatalf("bad cadence: want 0 todos, got %d", len(fs.added))
 	}
 }
+
+func TestAutoCreateLookahead(t *testing.T) {
+	fs := &fakeStore{
+		schedules: []store.Schedule{
+			{ID: 1, TemplateID: 10, CadenceType: "weekly", CadenceValue: "mon", PlaceholderDefaults: "{}", LookaheadDays: 30},
+		},
+		templates: map[int]*store.Template{10: {ID: 10, Name: "Plan week", Content: ""}},
+		existing:  make(map[string]bool),
+	}
+	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC) // Monday
+	AutoCreateForDate(fs, today, nil)
+
+	// 30 days from Feb 9 reach Mar 10: five Mondays.
+	if len(fs.added) != 5 || fs.added[4].date != "2026-03-09" {
+		t.Fatalf("30-day look-ahead: got %+v", fs.added)
+	}
+	if got := fs.schedules[0].GeneratedThrough; got != "2026-03-10" {
+		t.Errorf("generated through %s, want 2026-03-10", got)
+	}
+}
+
+func TestAutoCreateCatchUp(t *testing.T) {
+	// Generated through Feb 1, the app next runs on Feb 12: the daily
+	// occurrences of Feb 2-11 were missed.
+	newStore := func(mode string) *fakeStore {
+		return &fakeStore{
+			schedules: []store.Schedule{
+				{ID: 1, TemplateID: 10, CadenceType: "weekdays", PlaceholderDefaults: "{}", LookaheadDays: 1, CatchUp: mode, GeneratedThrough: "2026-02-01"},
+			},
+			templates: map[int]*store.Template{10: {ID: 10, Name: "Standup", Content: ""}},
+			existing:  make(map[string]bool),
+		}
+	}
+	today := time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC) // Thursday
+
+	fs := newStore(CatchUpNone)
+	AutoCreateForDate(fs, today, nil)
+	if len(fs.added) != 1 || fs.added[0].date != "2026-02-12" || len(fs.plain) != 0 {
+		t.Errorf("no catch-up: want only today's todo, got %+v %+v", fs.added, fs.plain)
+	}
+
+	fs = newStore(CatchUpAll)
+	AutoCreateForDate(fs, today, nil)
+	if len(fs.added) != 9 || fs.added[0].date != "2026-02-02" {
+		t.Errorf("catch up all: want 8 missed weekdays and today, got %+v", fs.added)
+	}
+
+	fs = newStore(CatchUpSummary)
+	AutoCreateForDate(fs, today, nil)
+	if len(fs.added) != 1 || len(fs.plain) != 1 {
+		t.Fatalf("catch up summary: want today's todo and a summary, got %+v %+v", fs.added, fs.plain)
+	}
+	sum := fs.plain[0]
+	if sum.text != "Missed: Standup (8)" || sum.date != "2026-02-12" ||
+		!strings.Contains(sum.body, "- [ ] Mon Feb 2, 2026") || !strings.Contains(sum.body, "- [ ] Wed Feb 11, 2026") {
+		t.Errorf("summary todo = %+v", sum)
+	}
+
+	// The next run has nothing left to catch up.
+	AutoCreateForDate(fs, today, nil)
+	if len(fs.plain) != 1 {
+		t.Errorf("summary created twice: %+v", fs.plain)
+	}
+}
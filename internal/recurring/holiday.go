@@ -0,0 +1,94 @@
+package recurring
+
+import "time"
+
+// Holiday policies of a schedule (store.Schedule.HolidayPolicy): what
+// happens to an occurrence that falls on a weekend or holiday.
+const (
+	HolidayCreate   = ""         // create the todo anyway
+	HolidaySkip     = "skip"     // create no todo for it
+	HolidayPrevious = "previous" // move it to the previous workday
+	HolidayNext     = "next"     // move it to the next workday
+)
+
+// HolidayPolicies lists the holiday policies in display order.
+var HolidayPolicies = []string{HolidayCreate, HolidaySkip, HolidayPrevious, HolidayNext}
+
+// HolidayPolicyLabel returns a short description of a holiday policy.
+func HolidayPolicyLabel(policy string) string {
+	switch policy {
+	case HolidaySkip:
+		return "skip holidays"
+	case HolidayPrevious:
+		return "previous workday"
+	case HolidayNext:
+		return "next workday"
+	default:
+		return "create anyway"
+	}
+}
+
+// IsWorkday reports whether d is a Monday to Friday that isHoliday does
+// not report. A nil isHoliday knows no holidays.
+func IsWorkday(d time.Time, isHoliday func(time.Time) bool) bool {
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	return isHoliday == nil || !isHoliday(d)
+}
+
+// ShiftDate returns the date an occurrence on d moves to under policy:
+// d itself on workdays and for HolidayCreate, otherwise the previous or
+// next workday. ok is false when the policy skips the occurrence.
+func ShiftDate(d time.Time, policy string, isHoliday func(time.Time) bool) (date time.Time, ok bool) {
+	if IsWorkday(d, isHoliday) {
+		return d, true
+	}
+	step := 0
+	switch policy {
+	case HolidaySkip:
+		return d, false
+	case HolidayPrevious:
+		step = -1
+	case HolidayNext:
+		step = 1
+	default:
+		return d, true
+	}
+	for i := 0; i < 366; i++ {
+		d = d.AddDate(0, 0, step)
+		if IsWorkday(d, isHoliday) {
+			return d, true
+		}
+	}
+	return d, false
+}
+
+// shiftOccurrence is ShiftDate for an occurrence on d of a schedule that
+// matches has occurrences on, except that it also reports false when
+// another occurrence between d and the workday it moves to (that workday
+// included) moves there as well: a run of weekends and holidays yields a
+// single todo, created for the occurrence nearest the workday.
+func shiftOccurrence(matches func(time.Time) bool, d time.Time, policy string, isHoliday func(time.Time) bool) (time.Time, bool) {
+	date, ok := ShiftDate(d, policy, isHoliday)
+	if !ok || sameDay(date, d) {
+		return date, ok
+	}
+	step := 1
+	if date.Before(d) {
+		step = -1
+	}
+	for x := d.AddDate(0, 0, step); ; x = x.AddDate(0, 0, step) {
+		if matches(x) {
+			return date, false
+		}
+		if sameDay(x, date) {
+			return date, true
+		}
+	}
+}
+
+// sameDay reports whether a and b fall on the same calendar day.
+func sameDay(a, b time.Time) bool {
+	return a.Format(dateFormat) == b.Format(dateFormat)
+}
@@ -0,0 +1,96 @@
+package recurring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// newYear reports January 1 as a holiday.
+func newYear(d time.Time) bool {
+	return d.Month() == time.January && d.Day() == 1
+}
+
+func TestShiftDate(t *testing.T) {
+	tests := []struct {
+		date   string
+		policy string
+		want   string // "" = skipped
+	}{
+		{"2026-01-01", HolidayCreate, "2026-01-01"},
+		{"2026-01-01", HolidaySkip, ""},
+		{"2026-01-01", HolidayPrevious, "2025-12-31"},
+		{"2026-01-01", HolidayNext, "2026-01-02"},
+		{"2026-01-02", HolidaySkip, "2026-01-02"},     // a workday
+		{"2026-01-03", HolidayNext, "2026-01-05"},     // Saturday
+		{"2026-01-04", HolidayPrevious, "2026-01-02"}, // Sunday
+		{"2028-01-01", HolidayPrevious, "2027-12-31"},
+	}
+	for _, tt := range tests {
+		d, _ := time.Parse(dateFormat, tt.date)
+		got, ok := ShiftDate(d, tt.policy, newYear)
+		gotStr := ""
+		if ok {
+			gotStr = got.Format(dateFormat)
+		}
+		if gotStr != tt.want {
+			t.Errorf("ShiftDate(%s, %q) = %q, want %q", tt.date, tt.policy, gotStr, tt.want)
+		}
+	}
+}
+
+func TestAutoCreateHolidayPolicy(t *testing.T) {
+	today := time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC) // Monday
+	tests := []struct {
+		policy string
+		want   string // date of the New Year's todo; "" = none
+	}{
+		{HolidayCreate, "2026-01-01"},
+		{HolidaySkip, ""},
+		{HolidayPrevious, "2025-12-31"},
+		{HolidayNext, "2026-01-02"},
+	}
+	for _, tt := range tests {
+		fs := &fakeStore{
+			schedules: []store.Schedule{
+				{ID: 1, TemplateID: 10, CadenceType: "monthly", CadenceValue: "1", PlaceholderDefaults: "{}", HolidayPolicy: tt.policy},
+			},
+			templates: map[int]*store.Template{10: {ID: 10, Name: "Pay rent", Content: ""}},
+			existing:  make(map[string]bool),
+		}
+		AutoCreateForDate(fs, today, newYear)
+		if tt.want == "" {
+			if len(fs.added) != 0 {
+				t.Errorf("%q: want no todo, got %+v", tt.policy, fs.added)
+			}
+			continue
+		}
+		if len(fs.added) != 1 || fs.added[0].date != tt.want || fs.added[0].scheduleDate != "2026-01-01" {
+			t.Errorf("%q: want a todo on %s for 2026-01-01, got %+v", tt.policy, tt.want, fs.added)
+		}
+
+		// The shifted todo is not created again on the next run.
+		AutoCreateForDate(fs, today.AddDate(0, 0, 1), newYear)
+		if len(fs.added) != 1 {
+			t.Errorf("%q: shifted todo created twice: %+v", tt.policy, fs.added)
+		}
+	}
+}
+
+func TestAutoCreateHolidayPolicyMergesWeekend(t *testing.T) {
+	// Saturday and Sunday both move to Monday; only Sunday's occurrence,
+	// the nearest one, creates the todo.
+	fs := &fakeStore{
+		schedules: []store.Schedule{
+			{ID: 1, TemplateID: 10, CadenceType: "weekly", CadenceValue: "sat,sun", PlaceholderDefaults: "{}", HolidayPolicy: HolidayNext},
+		},
+		templates: map[int]*store.Template{10: {ID: 10, Name: "Clean up", Content: ""}},
+		existing:  make(map[string]bool),
+	}
+	AutoCreateForDate(fs, time.Date(2026, 2, 13, 0, 0, 0, 0, time.UTC), nil) // Friday
+
+	if len(fs.added) != 1 || fs.added[0].date != "2026-02-16" || fs.added[0].scheduleDate != "2026-02-15" {
+		t.Errorf("want one todo on 2026-02-16 for 2026-02-15, got %+v", fs.added)
+	}
+}
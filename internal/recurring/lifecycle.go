@@ -0,0 +1,126 @@
+package recurring
+
+import (
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// maxSearchDays bounds the search for the last of a schedule's maximum
+// number of occurrences, and for its upcoming occurrences.
+const maxSearchDays = 10 * maxDays
+
+// Occurrences decides which days a schedule has occurrences on: the days
+// its rule matches within its start and end dates and its maximum number
+// of occurrences, less its exceptions.
+type Occurrences struct {
+	rule        ScheduleRule
+	first, last time.Time // zero for no bound
+	exceptions  map[string]bool
+}
+
+// NewOccurrences returns the occurrences of sched, whose cadence is rule.
+// The maximum number of occurrences counts from the start date, or from
+// the day the schedule was created without one; like an iCalendar COUNT,
+// it includes the occurrences exceptions skip.
+func NewOccurrences(sched store.Schedule, rule ScheduleRule) Occurrences {
+	o := Occurrences{rule: rule, exceptions: make(map[string]bool)}
+	for _, d := range sched.Exceptions {
+		o.exceptions[d] = true
+	}
+	if d, err := time.ParseInLocation(dateFormat, sched.StartDate, time.Local); err == nil {
+		o.first = d
+	}
+	if d, err := time.ParseInLocation(dateFormat, sched.EndDate, time.Local); err == nil {
+		o.last = d
+	}
+
+	if sched.MaxOccurrences > 0 {
+		d := o.first
+		if d.IsZero() {
+			d, _ = time.ParseInLocation(dateFormat, sched.CreatedAt, time.Local)
+		}
+		count := 0
+		for i := 0; i < maxSearchDays && !d.IsZero(); i, d = i+1, d.AddDate(0, 0, 1) {
+			if !o.last.IsZero() && d.After(o.last) {
+				break
+			}
+			if rule.MatchesDate(d) {
+				count++
+				if count == sched.MaxOccurrences {
+					o.last = d
+					break
+				}
+			}
+		}
+	}
+	return o
+}
+
+// InRange reports whether the rule matches d within the start and end
+// dates and the maximum number of occurrences, exceptions included.
+func (o Occurrences) InRange(d time.Time) bool {
+	d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.Local)
+	if !o.first.IsZero() && d.Before(o.first) {
+		return false
+	}
+	if !o.last.IsZero() && d.After(o.last) {
+		return false
+	}
+	return o.rule.MatchesDate(d)
+}
+
+// IsException reports whether the occurrence on d is skipped.
+func (o Occurrences) IsException(d time.Time) bool {
+	return o.exceptions[d.Format(dateFormat)]
+}
+
+// Matches reports whether the schedule has an occurrence on d.
+func (o Occurrences) Matches(d time.Time) bool {
+	return o.InRange(d) && !o.IsException(d)
+}
+
+// Ended reports whether the schedule has no occurrences from d on.
+func (o Occurrences) Ended(d time.Time) bool {
+	return !o.last.IsZero() && o.last.Before(time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.Local))
+}
+
+// Bounds returns the first and last day the schedule can have occurrences
+// on; a zero time has no bound.
+func (o Occurrences) Bounds() (first, last time.Time) {
+	return o.first, o.last
+}
+
+// Due returns the day the todo for the occurrence on d is for under a
+// holiday policy; ok is false when the policy creates no todo for it.
+func (o Occurrences) Due(d time.Time, policy string, isHoliday func(time.Time) bool) (time.Time, bool) {
+	return shiftOccurrence(o.Matches, d, policy, isHoliday)
+}
+
+// Occurrence is an upcoming occurrence of a schedule.
+type Occurrence struct {
+	Date      time.Time // the day the rule matches
+	Due       time.Time // the day its todo is for, after the holiday policy
+	Exception bool      // skipped as an exception
+	Skipped   bool      // the holiday policy creates no todo for it
+}
+
+// Upcoming returns up to n occurrences of a schedule from from on,
+// including the ones exceptions and its holiday policy skip.
+func Upcoming(sched store.Schedule, rule ScheduleRule, from time.Time, n int, isHoliday func(time.Time) bool) []Occurrence {
+	o := NewOccurrences(sched, rule)
+	d := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	var list []Occurrence
+	for i := 0; i < maxSearchDays && len(list) < n && !o.Ended(d); i, d = i+1, d.AddDate(0, 0, 1) {
+		if !o.InRange(d) {
+			continue
+		}
+		occ := Occurrence{Date: d, Due: d, Exception: o.IsException(d)}
+		if !occ.Exception {
+			due, ok := o.Due(d, sched.HolidayPolicy, isHoliday)
+			occ.Due, occ.Skipped = due, !ok
+		}
+		list = append(list, occ)
+	}
+	return list
+}
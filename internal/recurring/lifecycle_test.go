@@ -0,0 +1,117 @@
+package recurring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// createdDates returns the occurrence dates of the todos fs created.
+func createdDates(fs *fakeStore) []string {
+	var dates []string
+	for _, a := range fs.added {
+		dates = append(dates, a.scheduleDate)
+	}
+	return dates
+}
+
+func equalDates(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAutoCreateLifecycle(t *testing.T) {
+	today := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC) // Monday
+	tests := []struct {
+		name  string
+		sched store.Schedule
+		want  []string
+	}{
+		{
+			name:  "start and end dates",
+			sched: store.Schedule{CadenceType: "daily", StartDate: "2026-02-11", EndDate: "2026-02-13"},
+			want:  []string{"2026-02-11", "2026-02-12", "2026-02-13"},
+		},
+		{
+			// Feb 2 is the first of three occurrences, so Feb 9 and 16 remain.
+			name:  "max occurrences",
+			sched: store.Schedule{CadenceType: "weekly", CadenceValue: "mon", StartDate: "2026-02-02", MaxOccurrences: 3, LookaheadDays: 60},
+			want:  []string{"2026-02-09", "2026-02-16"},
+		},
+		{
+			name:  "exceptions",
+			sched: store.Schedule{CadenceType: "daily", LookaheadDays: 4, Exceptions: []string{"2026-02-10", "2026-02-11"}},
+			want:  []string{"2026-02-09", "2026-02-12"},
+		},
+		{
+			name:  "paused",
+			sched: store.Schedule{CadenceType: "daily", Paused: true},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		sched := tt.sched
+		sched.ID, sched.TemplateID, sched.PlaceholderDefaults, sched.CreatedAt = 1, 10, "{}", "2026-01-01"
+		fs := &fakeStore{
+			schedules: []store.Schedule{sched},
+			templates: map[int]*store.Template{10: {ID: 10, Name: "Task", Content: ""}},
+			existing:  make(map[string]bool),
+		}
+		AutoCreateForDate(fs, today, nil)
+		if got := createdDates(fs); !equalDates(got, tt.want) {
+			t.Errorf("%s: created %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAutoCreateResumeSkipsPausedDays(t *testing.T) {
+	fs := &fakeStore{
+		schedules: []store.Schedule{
+			{ID: 1, TemplateID: 10, CadenceType: "daily", PlaceholderDefaults: "{}", LookaheadDays: 1, CatchUp: CatchUpAll, GeneratedThrough: "2026-02-01", Paused: true},
+		},
+		templates: map[int]*store.Template{10: {ID: 10, Name: "Water plants", Content: ""}},
+		existing:  make(map[string]bool),
+	}
+	AutoCreateForDate(fs, time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC), nil)
+	if len(fs.added) != 0 || fs.schedules[0].GeneratedThrough != "2026-02-08" {
+		t.Fatalf("paused schedule: created %v, generated through %s", fs.added, fs.schedules[0].GeneratedThrough)
+	}
+
+	// Resumed two days later, only the days since the last run are caught up.
+	fs.SetSchedulePaused(1, false)
+	AutoCreateForDate(fs, time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC), nil)
+	if got, want := createdDates(fs), []string{"2026-02-09", "2026-02-10", "2026-02-11"}; !equalDates(got, want) {
+		t.Errorf("resumed schedule: created %v, want %v", got, want)
+	}
+}
+
+func TestUpcoming(t *testing.T) {
+	sched := store.Schedule{
+		CadenceType: "monthly", CadenceValue: "1", CreatedAt: "2025-06-01",
+		HolidayPolicy: HolidayNext, MaxOccurrences: 9, Exceptions: []string{"2025-12-01"},
+	}
+	rule, _ := ParseRule("monthly:1")
+	got := Upcoming(sched, rule, time.Date(2025, 11, 15, 0, 0, 0, 0, time.Local), 5, newYear)
+
+	// The ninth occurrence from June 2025 on, Feb 1, is the last one.
+	if len(got) != 3 {
+		t.Fatalf("got %d occurrences, want 3: %+v", len(got), got)
+	}
+	if d := got[0].Date.Format(dateFormat); d != "2025-12-01" || !got[0].Exception {
+		t.Errorf("first = %s exception %v, want the 2025-12-01 exception", d, got[0].Exception)
+	}
+	if d, due := got[1].Date.Format(dateFormat), got[1].Due.Format(dateFormat); d != "2026-01-01" || due != "2026-01-02" {
+		t.Errorf("second = %s due %s, want 2026-01-01 due 2026-01-02", d, due)
+	}
+	if d, due := got[2].Date.Format(dateFormat), got[2].Due.Format(dateFormat); d != "2026-02-01" || due != "2026-02-02" {
+		t.Errorf("third = %s due %s, want 2026-02-01 due 2026-02-02", d, due)
+	}
+}
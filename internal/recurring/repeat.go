@@ -0,0 +1,103 @@
+package recurring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// nextLimit is how many days ahead Next looks for the next date of a
+// calendar rule; four years cover yearly rules on February 29.
+const nextLimit = 4*366 + 1
+
+// Repeat is the rule of a repeating todo: either a calendar cadence, as
+// used by schedules, or a number of days after each occurrence is
+// completed.
+type Repeat struct {
+	Rule      ScheduleRule // calendar cadence; unused when AfterDays is set
+	AfterDays int          // days from completion to the next occurrence
+}
+
+// ParseRepeat parses the repeat rule of a todo: "after:N" for N days
+// after completion, or any rule ParseRule accepts ("daily",
+// "weekly:mon,fri", "FREQ=MONTHLY;BYDAY=-1FR", ...).
+func ParseRepeat(s string) (Repeat, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutPrefix(s, "after:"); ok {
+		n, err := strconv.Atoi(strings.TrimSuffix(days, "d"))
+		if err != nil || n < 1 || n > 3660 {
+			return Repeat{}, fmt.Errorf("invalid repeat %q (want after:N with N days, e.g. after:3)", s)
+		}
+		return Repeat{AfterDays: n}, nil
+	}
+	rule, err := ParseRule(s)
+	if err != nil {
+		return Repeat{}, err
+	}
+	return Repeat{Rule: rule}, nil
+}
+
+// AnchorRepeat anchors the repeat rule of a todo dated date ("YYYY-MM-DD",
+// "" for floating) on that date, or on today for a floating todo (see
+// AnchorRule).
+func AnchorRepeat(rule, date string, today time.Time) string {
+	start := today
+	if d, err := time.ParseInLocation(dateFormat, date, time.Local); err == nil {
+		start = d
+	}
+	return AnchorRule(rule, start)
+}
+
+// String returns the canonical form of the rule, as stored in Todo.Repeat.
+func (r Repeat) String() string {
+	if r.AfterDays > 0 {
+		return "after:" + strconv.Itoa(r.AfterDays)
+	}
+	return r.Rule.String()
+}
+
+// Next returns the date ("YYYY-MM-DD") of the occurrence that follows t
+// when t is completed today. After-completion rules count from today;
+// calendar rules pick their first date after both t's date and today, so
+// completing an overdue todo does not create another overdue one. ok is
+// false when the rule has no further dates, e.g. past its UNTIL.
+func (r Repeat) Next(t store.Todo, today time.Time) (date string, ok bool) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+	if r.AfterDays > 0 {
+		return today.AddDate(0, 0, r.AfterDays).Format(dateFormat), true
+	}
+	from := today
+	if d, err := time.ParseInLocation(dateFormat, t.Date, time.Local); err == nil && d.After(from) {
+		from = d
+	}
+	for i := 1; i <= nextLimit; i++ {
+		d := from.AddDate(0, 0, i)
+		if r.Rule.MatchesDate(d) {
+			return d.Format(dateFormat), true
+		}
+	}
+	return "", false
+}
+
+// Toggle toggles the completion of the todo with the given ID. Completing
+// a repeating todo adds its next occurrence (see Repeat.Next), and one
+// undo reverts both.
+func Toggle(s store.TodoStore, id int, today time.Time) {
+	s.Batch("toggle", func() {
+		s.Toggle(id)
+		t := s.Find(id)
+		if t == nil || !t.Done || t.Repeat == "" {
+			return
+		}
+		r, err := ParseRepeat(t.Repeat)
+		if err != nil {
+			return
+		}
+		if date, ok := r.Next(*t, today); ok {
+			s.AddOccurrence(id, date)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package recurring
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+func TestParseRepeat(t *testing.T) {
+	tests := map[string]string{
+		"after:3":                 "after:3",
+		"after:10d":               "after:10",
+		"weekly:tue":              "weekly:tue",
+		"FREQ=MONTHLY;BYDAY=-1FR": "rrule:FREQ=MONTHLY;BYDAY=-1FR",
+	}
+	for in, want := range tests {
+		r, err := ParseRepeat(in)
+		if err != nil {
+			t.Fatalf("ParseRepeat(%q) error: %v", in, err)
+		}
+		if got := r.String(); got != want {
+			t.Errorf("ParseRepeat(%q).String() = %q, want %q", in, got, want)
+		}
+	}
+	for _, in := range []string{"", "after:", "after:0", "after:x", "hourly"} {
+		if _, err := ParseRepeat(in); err == nil {
+			t.Errorf("ParseRepeat(%q): expected error", in)
+		}
+	}
+}
+
+func TestAnchorRepeat(t *testing.T) {
+	today := time.Date(2026, 10, 17, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		rule, date, want string
+	}{
+		{"FREQ=DAILY;INTERVAL=2", "2026-10-20", "FREQ=DAILY;INTERVAL=2;DTSTART=20261020"},
+		{"RRULE:FREQ=DAILY;INTERVAL=2", "", "RRULE:FREQ=DAILY;INTERVAL=2;DTSTART=20261017"},
+		{"FREQ=DAILY;INTERVAL=2;DTSTART=20260101", "2026-10-20", "FREQ=DAILY;INTERVAL=2;DTSTART=20260101"},
+		{"weekly:tue", "2026-10-20", "weekly:tue"},
+		{"after:3", "", "after:3"},
+	}
+	for _, tt := range tests {
+		if got := AnchorRepeat(tt.rule, tt.date, today); got != tt.want {
+			t.Errorf("AnchorRepeat(%q, %q) = %q, want %q", tt.rule, tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestRepeatNext(t *testing.T) {
+	today := time.Date(2026, 10, 17, 15, 0, 0, 0, time.Local) // Saturday
+	tests := []struct {
+		repeat string
+		date   string
+		want   string
+	}{
+		{"after:3", "2026-10-10", "2026-10-20"},
+		{"after:1", "", "2026-10-18"},
+		{"daily", "2026-10-17", "2026-10-18"},
+		// Overdue todos continue after today, not after their date.
+		{"weekly:tue", "2026-10-06", "2026-10-20"},
+		// Todos ahead of today continue after their date.
+		{"weekly:tue", "2026-10-20", "2026-10-27"},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "2026-10-30", "2026-11-27"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRepeat(tt.repeat)
+		if err != nil {
+			t.Fatalf("ParseRepeat(%q) error: %v", tt.repeat, err)
+		}
+		got, ok := r.Next(store.Todo{Date: tt.date}, today)
+		if !ok || got != tt.want {
+			t.Errorf("%q from %q: got %q, %v, want %q", tt.repeat, tt.date, got, ok, tt.want)
+		}
+	}
+
+	r, _ := ParseRepeat("FREQ=DAILY;UNTIL=20261017")
+	if got, ok := r.Next(store.Todo{Date: "2026-10-17"}, today); ok {
+		t.Errorf("ended rule: got %q, want no next date", got)
+	}
+}
+
+func TestToggleRepeating(t *testing.T) {
+	s, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+	today := time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)
+
+	todo := s.Add("Water plants", "2026-10-17", "day", 2)
+	s.SetTags(todo.ID, []string{"home"})
+	s.SetRepeat(todo.ID, "after:3")
+
+	Toggle(s, todo.ID, today)
+	series := s.SeriesTodos(todo.ID)
+	if len(series) != 2 {
+		t.Fatalf("series = %+v, want 2 occurrences", series)
+	}
+	done, next := series[0], series[1]
+	if !done.Done || done.Repeat != "" {
+		t.Errorf("completed occurrence = %+v, want done without a repeat rule", done)
+	}
+	if next.Done || next.Date != "2026-10-20" || next.Repeat != "after:3" || next.Priority != 2 ||
+		len(next.Tags) != 1 || next.Tags[0] != "home" || next.SeriesID != todo.ID {
+		t.Errorf("next occurrence = %+v", next)
+	}
+
+	// Reopening the completed occurrence does not add another one.
+	Toggle(s, todo.ID, today)
+	Toggle(s, todo.ID, today)
+	if n := len(s.SeriesTodos(todo.ID)); n != 2 {
+		t.Errorf("series has %d occurrences after reopening, want 2", n)
+	}
+
+	// One undo reverts the completion and the new occurrence.
+	Toggle(s, next.ID, today)
+	if n := len(s.SeriesTodos(todo.ID)); n != 3 {
+		t.Fatalf("series has %d occurrences, want 3", n)
+	}
+	if label, ok := s.Undo(); !ok || label != "toggle" {
+		t.Errorf("Undo() = %q, %v, want toggle", label, ok)
+	}
+	if n := len(s.SeriesTodos(todo.ID)); n != 2 {
+		t.Errorf("series has %d occurrences after undo, want 2", n)
+	}
+	if t2 := s.Find(next.ID); t2 == nil || t2.Done || t2.Repeat != "after:3" {
+		t.Errorf("undone occurrence = %+v", t2)
+	}
+}
@@ -0,0 +1,488 @@
+package recurring
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rruleDate is the layout of UNTIL and DTSTART values.
+const rruleDate = "20060102"
+
+// Recurrence is an iCalendar (RFC 5545) recurrence rule, limited to whole
+// days: FREQ of DAILY, WEEKLY, MONTHLY or YEARLY with INTERVAL, BYDAY,
+// BYMONTHDAY, BYMONTH, BYSETPOS, COUNT and UNTIL. Weeks start on Monday.
+//
+// Start is the rule's DTSTART. iCalendar keeps it outside the rule, but a
+// schedule has nowhere else to store it, so it is written into the rule
+// as DTSTART=YYYYMMDD. Intervals and counts are taken from it, and rules
+// without a BYDAY or BYMONTHDAY take their day from it; other rules may
+// leave it out. Unlike iCalendar, Start is only an occurrence when it
+// matches the rule.
+type Recurrence struct {
+	Freq       string       // "DAILY", "WEEKLY", "MONTHLY" or "YEARLY"
+	Interval   int          // periods between occurrences, at least 1
+	ByDay      []WeekdayNum // weekdays, optionally the nth of the month or year
+	ByMonthDay []int        // 1-31, or -1 (last day) to -31
+	ByMonth    []int        // 1-12
+	BySetPos   []int        // picks from each period's days; -1 is the last
+	Count      int          // number of occurrences; 0 means unlimited
+	Until      time.Time    // last possible occurrence; zero means unlimited
+	Start      time.Time    // DTSTART; zero when unset
+}
+
+// WeekdayNum is a BYDAY entry: a weekday and, for monthly and yearly
+// rules, which of its occurrences in the month or year (2 is the second,
+// -1 the last). N is 0 for every occurrence.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// rruleDays maps iCalendar day codes to weekdays.
+var rruleDays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// rruleDayCodes is the inverse of rruleDays, indexed by time.Weekday.
+var rruleDayCodes = [7]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// AnchorRule adds a DTSTART of start to a recurrence rule ("FREQ=..." or
+// "RRULE:...") that names no start of its own, so rules that count
+// intervals or occurrences count from start. Other rules are returned
+// unchanged.
+func AnchorRule(rule string, start time.Time) string {
+	rule = strings.TrimSpace(rule)
+	upper := strings.ToUpper(rule)
+	if !strings.HasPrefix(upper, "FREQ=") && !strings.HasPrefix(upper, "RRULE:") || strings.Contains(upper, "DTSTART=") {
+		return rule
+	}
+	return rule + ";DTSTART=" + start.Format(rruleDate)
+}
+
+// ParseRecurrence parses an iCalendar recurrence rule such as
+// "FREQ=MONTHLY;BYDAY=-1FR", with or without the "RRULE:" prefix and
+// optionally with a DTSTART=YYYYMMDD part. Names and values are not case
+// sensitive.
+func ParseRecurrence(s string) (*Recurrence, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("empty recurrence rule")
+	}
+
+	r := &Recurrence{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q (want NAME=VALUE)", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%s is given twice", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			switch value {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				r.Freq = value
+			default:
+				err = fmt.Errorf("unsupported FREQ %q (want DAILY, WEEKLY, MONTHLY or YEARLY)", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = parseRuleInt(name, value, 1, 1000)
+		case "COUNT":
+			r.Count, err = parseRuleInt(name, value, 1, 10000)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseRuleInts(name, value, 31, true)
+		case "BYMONTH":
+			r.ByMonth, err = parseRuleInts(name, value, 12, false)
+		case "BYSETPOS":
+			r.BySetPos, err = parseRuleInts(name, value, 366, true)
+		case "UNTIL":
+			r.Until, err = parseRuleDate(name, value)
+		case "DTSTART":
+			r.Start, err = parseRuleDate(name, value)
+		case "WKST":
+			if value != "MO" {
+				err = fmt.Errorf("unsupported WKST %q (weeks start on MO)", value)
+			}
+		default:
+			err = fmt.Errorf("unsupported rule part %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// validate checks the combinations of parts RFC 5545 rules out or that
+// cannot be evaluated.
+func (r *Recurrence) validate() error {
+	if r.Freq == "" {
+		return fmt.Errorf("recurrence rule requires FREQ")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("COUNT and UNTIL cannot be combined")
+	}
+	if r.Freq == "WEEKLY" && len(r.ByMonthDay) > 0 {
+		return fmt.Errorf("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	if len(r.BySetPos) > 0 && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 && len(r.ByMonth) == 0 {
+		return fmt.Errorf("BYSETPOS requires BYDAY, BYMONTHDAY or BYMONTH")
+	}
+	maxN := 53
+	if r.Freq == "MONTHLY" || len(r.ByMonth) > 0 {
+		maxN = 5
+	}
+	for _, wd := range r.ByDay {
+		if wd.N == 0 {
+			continue
+		}
+		if r.Freq != "MONTHLY" && r.Freq != "YEARLY" {
+			return fmt.Errorf("numbered BYDAY such as %s requires FREQ=MONTHLY or FREQ=YEARLY", wd)
+		}
+		if wd.N > maxN || wd.N < -maxN {
+			return fmt.Errorf("BYDAY %s is out of range", wd)
+		}
+	}
+	if r.Start.IsZero() && r.needsStart() {
+		return fmt.Errorf("rule with INTERVAL, COUNT or no BYDAY/BYMONTHDAY requires DTSTART=YYYYMMDD")
+	}
+	if !r.Start.IsZero() && !r.Until.IsZero() && r.Until.Before(r.Start) {
+		return fmt.Errorf("UNTIL is before DTSTART")
+	}
+	return nil
+}
+
+// needsStart reports whether the rule depends on its DTSTART.
+func (r *Recurrence) needsStart() bool {
+	if r.Interval > 1 || r.Count > 0 {
+		return true
+	}
+	return r.Freq != "DAILY" && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0
+}
+
+// MatchesDate reports whether the rule has an occurrence on the date of d.
+func (r *Recurrence) MatchesDate(d time.Time) bool {
+	d = civilDay(d)
+	if !r.Start.IsZero() && d.Before(r.Start) {
+		return false
+	}
+	if !r.Until.IsZero() && d.After(r.Until) {
+		return false
+	}
+	if r.Interval > 1 && r.periodIndex(d)%r.Interval != 0 {
+		return false
+	}
+	if !slices.ContainsFunc(r.occurrences(d), d.Equal) {
+		return false
+	}
+	return r.Count == 0 || r.occurrenceNumber(d) <= r.Count
+}
+
+// String returns the rule in the form ParseRecurrence reads, with the
+// parts in a fixed order and DTSTART last.
+func (r *Recurrence) String() string {
+	s := r.RRule()
+	if !r.Start.IsZero() {
+		s += ";DTSTART=" + r.Start.Format(rruleDate)
+	}
+	return s
+}
+
+// RRule returns the iCalendar RRULE value, without the "RRULE:" prefix and
+// without DTSTART.
+func (r *Recurrence) RRule() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.ByMonth))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = wd.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format(rruleDate))
+	}
+	return strings.Join(parts, ";")
+}
+
+// String returns the BYDAY form of the entry, e.g. "TU", "2MO" or "-1FR".
+func (wd WeekdayNum) String() string {
+	if wd.N == 0 {
+		return rruleDayCodes[wd.Day]
+	}
+	return strconv.Itoa(wd.N) + rruleDayCodes[wd.Day]
+}
+
+// period returns the first day of the FREQ period (day, Monday-based week,
+// month or year) containing d and the first day after it.
+func (r *Recurrence) period(d time.Time) (start, end time.Time) {
+	switch r.Freq {
+	case "WEEKLY":
+		start = d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7)
+	case "MONTHLY":
+		start = time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	case "YEARLY":
+		start = time.Date(d.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(1, 0, 0)
+	default:
+		return d, d.AddDate(0, 0, 1)
+	}
+}
+
+// periodIndex returns how many periods lie between the period of Start and
+// the period of d.
+func (r *Recurrence) periodIndex(d time.Time) int {
+	switch r.Freq {
+	case "WEEKLY":
+		from, _ := r.period(r.Start)
+		to, _ := r.period(d)
+		return daysBetween(from, to) / 7
+	case "MONTHLY":
+		return (d.Year()-r.Start.Year())*12 + int(d.Month()) - int(r.Start.Month())
+	case "YEARLY":
+		return d.Year() - r.Start.Year()
+	default:
+		return daysBetween(r.Start, d)
+	}
+}
+
+// occurrences returns the days of the period containing d that the BY
+// parts select, in order, ignoring INTERVAL, COUNT, UNTIL and Start.
+// Without BYDAY and BYMONTHDAY, the day comes from Start, as in RFC 5545.
+func (r *Recurrence) occurrences(d time.Time) []time.Time {
+	byDay, byMonthDay, byMonth := r.ByDay, r.ByMonthDay, r.ByMonth
+	if len(byDay) == 0 && len(byMonthDay) == 0 {
+		switch r.Freq {
+		case "WEEKLY":
+			byDay = []WeekdayNum{{Day: r.Start.Weekday()}}
+		case "MONTHLY":
+			byMonthDay = []int{r.Start.Day()}
+		case "YEARLY":
+			byMonthDay = []int{r.Start.Day()}
+			if len(byMonth) == 0 {
+				byMonth = []int{int(r.Start.Month())}
+			}
+		}
+	}
+
+	// Numbered weekdays count within the month for monthly rules and for
+	// yearly rules limited to months, and within the year otherwise.
+	inMonth := r.Freq == "MONTHLY" || len(r.ByMonth) > 0
+
+	start, end := r.period(d)
+	var days []time.Time
+	for t := start; t.Before(end); t = t.AddDate(0, 0, 1) {
+		if len(byMonth) > 0 && !slices.Contains(byMonth, int(t.Month())) {
+			continue
+		}
+		if len(byMonthDay) > 0 && !matchesMonthDay(t, byMonthDay) {
+			continue
+		}
+		if len(byDay) > 0 && !matchesByDay(t, byDay, inMonth) {
+			continue
+		}
+		days = append(days, t)
+	}
+	if len(r.BySetPos) == 0 {
+		return days
+	}
+
+	var picked []time.Time
+	for _, pos := range r.BySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(days) + pos
+		}
+		if i >= 0 && i < len(days) {
+			picked = append(picked, days[i])
+		}
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i].Before(picked[j]) })
+	return picked
+}
+
+// occurrenceNumber returns the position of d, an occurrence of the rule,
+// in the series starting at Start. Counting stops once it passes Count.
+func (r *Recurrence) occurrenceNumber(d time.Time) int {
+	n := 0
+	for p, _ := r.period(r.Start); !p.After(d); p = r.nextPeriod(p) {
+		for _, t := range r.occurrences(p) {
+			if t.Before(r.Start) {
+				continue
+			}
+			n++
+			if !t.Before(d) || n > r.Count {
+				return n
+			}
+		}
+	}
+	return n
+}
+
+// nextPeriod returns the first day of the period INTERVAL periods after
+// the one starting on p.
+func (r *Recurrence) nextPeriod(p time.Time) time.Time {
+	switch r.Freq {
+	case "WEEKLY":
+		return p.AddDate(0, 0, 7*r.Interval)
+	case "MONTHLY":
+		return p.AddDate(0, r.Interval, 0)
+	case "YEARLY":
+		return p.AddDate(r.Interval, 0, 0)
+	default:
+		return p.AddDate(0, 0, r.Interval)
+	}
+}
+
+// matchesMonthDay reports whether t is one of the BYMONTHDAY days.
+func matchesMonthDay(t time.Time, days []int) bool {
+	last := lastDayOfMonth(t.Year(), t.Month())
+	for _, md := range days {
+		if md > 0 && t.Day() == md || md < 0 && t.Day() == last+md+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesByDay reports whether t is one of the BYDAY days. Numbered
+// entries count within t's month if inMonth is set, else within its year.
+func matchesByDay(t time.Time, days []WeekdayNum, inMonth bool) bool {
+	day, length := t.YearDay(), time.Date(t.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	if inMonth {
+		day, length = t.Day(), lastDayOfMonth(t.Year(), t.Month())
+	}
+	for _, wd := range days {
+		if wd.Day != t.Weekday() {
+			continue
+		}
+		switch {
+		case wd.N == 0,
+			wd.N > 0 && (day-1)/7+1 == wd.N,
+			wd.N < 0 && -((length-day)/7+1) == wd.N:
+			return true
+		}
+	}
+	return false
+}
+
+// parseByDay parses a BYDAY list such as "MO,WE" or "2MO,-1FR".
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+		code, num := item[len(item)-2:], item[:len(item)-2]
+		day, ok := rruleDays[code]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q (want MO, TU, WE, TH, FR, SA or SU)", item)
+		}
+		wd := WeekdayNum{Day: day}
+		if num != "" {
+			n, err := strconv.Atoi(num)
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("invalid BYDAY %q", item)
+			}
+			wd.N = n
+		}
+		days = append(days, wd)
+	}
+	return days, nil
+}
+
+// parseRuleInt parses an integer part value in the range lo-hi.
+func parseRuleInt(name, value string, lo, hi int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < lo || n > hi {
+		return 0, fmt.Errorf("invalid %s %q (want %d-%d)", name, value, lo, hi)
+	}
+	return n, nil
+}
+
+// parseRuleInts parses a comma-separated list of integers from 1 to max,
+// or from -max to -1 too if negative is set.
+func parseRuleInts(name, value string, max int, negative bool) ([]int, error) {
+	var nums []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n > max || n < -max || n < 0 && !negative {
+			return nil, fmt.Errorf("invalid %s %q", name, item)
+		}
+		nums = append(nums, n)
+	}
+	return nums, nil
+}
+
+// parseRuleDate parses an UNTIL or DTSTART value, YYYYMMDD optionally
+// followed by a time, which is ignored.
+func parseRuleDate(name, value string) (time.Time, error) {
+	date, _, _ := strings.Cut(value, "T")
+	t, err := time.Parse(rruleDate, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q (want YYYYMMDD)", name, value)
+	}
+	return t, nil
+}
+
+// civilDay returns the date of t as midnight UTC, so days can be compared
+// and counted without time zone offsets.
+func civilDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween returns the number of days from a to b, both civil days.
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours()) / 24
+}
+
+// joinInts formats integers as a comma-separated list.
+func joinInts(nums []int) string {
+	s := make([]string, len(nums))
+	for i, n := range nums {
+		s[i] = strconv.Itoa(n)
+	}
+	return strings.Join(s, ",")
+}
@@ -9,9 +9,10 @@ import (
 
 // ScheduleRule represents a recurring cadence for todo creation.
 type ScheduleRule struct {
-	Type       string   // "daily", "weekdays", "weekly", "monthly"
-	Days       []string // for weekly: lowercase day names (e.g., "mon", "fri")
-	DayOfMonth int      // for monthly: 1-31 (0 means unused)
+	Type       string      // "daily", "weekdays", "weekly", "monthly", "rrule"
+	Days       []string    // for weekly: lowercase day names (e.g., "mon", "fri")
+	DayOfMonth int         // for monthly: 1-31 (0 means unused)
+	Recur      *Recurrence // for rrule: the iCalendar recurrence rule
 }
 
 // validDays maps lowercase day abbreviations to time.Weekday.
@@ -32,10 +33,15 @@ var validDays = map[string]time.Weekday{
 //   - "weekdays"
 //   - "weekly:mon,wed,fri"
 //   - "monthly:15"
+//   - "rrule:FREQ=MONTHLY;BYDAY=-1FR" (see ParseRecurrence); a bare
+//     "FREQ=..." or "RRULE:FREQ=..." is read the same way
 func ParseRule(s string) (ScheduleRule, error) {
 	if s == "" {
 		return ScheduleRule{}, fmt.Errorf("empty schedule rule")
 	}
+	if upper := strings.ToUpper(s); strings.HasPrefix(upper, "FREQ=") || strings.HasPrefix(upper, "RRULE:") {
+		s = "rrule:" + s
+	}
 
 	parts := strings.SplitN(s, ":", 2)
 	typ := parts[0]
@@ -72,6 +78,16 @@ func ParseRule(s string) (ScheduleRule, error) {
 		}
 		return ScheduleRule{Type: "monthly", DayOfMonth: day}, nil
 
+	case "rrule":
+		if len(parts) < 2 || parts[1] == "" {
+			return ScheduleRule{}, fmt.Errorf("rrule rule requires a recurrence (e.g., rrule:FREQ=WEEKLY;BYDAY=TU)")
+		}
+		recur, err := ParseRecurrence(parts[1])
+		if err != nil {
+			return ScheduleRule{}, err
+		}
+		return ScheduleRule{Type: "rrule", Recur: recur}, nil
+
 	default:
 		return ScheduleRule{}, fmt.Errorf("unknown schedule type %q", typ)
 	}
@@ -105,6 +121,9 @@ func (r ScheduleRule) MatchesDate(d time.Time) bool {
 		}
 		return d.Day() == target
 
+	case "rrule":
+		return r.Recur.MatchesDate(d)
+
 	default:
 		return false
 	}
@@ -122,6 +141,39 @@ func (r ScheduleRule) String() string {
 		return "weekly:" + strings.Join(r.Days, ",")
 	case "monthly":
 		return "monthly:" + strconv.Itoa(r.DayOfMonth)
+	case "rrule":
+		return "rrule:" + r.Recur.String()
+	default:
+		return ""
+	}
+}
+
+// RRule returns the iCalendar (RFC 5545) recurrence rule equivalent to the
+// rule, without the "RRULE:" prefix. Monthly rules on days past the 28th
+// pick the last existing day of shorter months, matching MatchesDate.
+func (r ScheduleRule) RRule() string {
+	switch r.Type {
+	case "daily":
+		return "FREQ=DAILY"
+	case "weekdays":
+		return "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"
+	case "weekly":
+		days := make([]string, len(r.Days))
+		for i, d := range r.Days {
+			days[i] = strings.ToUpper(d[:2])
+		}
+		return "FREQ=WEEKLY;BYDAY=" + strings.Join(days, ",")
+	case "monthly":
+		if r.DayOfMonth <= 28 {
+			return "FREQ=MONTHLY;BYMONTHDAY=" + strconv.Itoa(r.DayOfMonth)
+		}
+		days := make([]string, 0, r.DayOfMonth-27)
+		for d := 28; d <= r.DayOfMonth; d++ {
+			days = append(days, strconv.Itoa(d))
+		}
+		return "FREQ=MONTHLY;BYMONTHDAY=" + strings.Join(days, ",") + ";BYSETPOS=-1"
+	case "rrule":
+		return r.Recur.RRule()
 	default:
 		return ""
 	}
@@ -326,3 +326,148 @@ func TestStringRoundTrip(t *testing.T) {
 		}
 	}
 }
+
+// --- RRule tests ---
+
+func TestRRule(t *testing.T) {
+	tests := map[string]string{
+		"daily":          "FREQ=DAILY",
+		"weekdays":       "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+		"weekly:mon,fri": "FREQ=WEEKLY;BYDAY=MO,FR",
+		"monthly:15":     "FREQ=MONTHLY;BYMONTHDAY=15",
+		"monthly:28":     "FREQ=MONTHLY;BYMONTHDAY=28",
+		"monthly:30":     "FREQ=MONTHLY;BYMONTHDAY=28,29,30;BYSETPOS=-1",
+	}
+	for in, want := range tests {
+		r, err := ParseRule(in)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) error: %v", in, err)
+		}
+		if got := r.RRule(); got != want {
+			t.Errorf("RRule(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// --- rrule tests ---
+
+func TestRRuleMatchesDate(t *testing.T) {
+	tests := []struct {
+		rule string
+		d    time.Time
+		want bool
+	}{
+		// Every other Tuesday.
+		{"rrule:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;DTSTART=20261006", date(2026, 10, 6), true},
+		{"rrule:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;DTSTART=20261006", date(2026, 10, 13), false},
+		{"rrule:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;DTSTART=20261006", date(2026, 10, 20), true},
+		{"rrule:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;DTSTART=20261006", date(2026, 9, 22), false},
+		// Last Friday of the month.
+		{"FREQ=MONTHLY;BYDAY=-1FR", date(2026, 10, 30), true},
+		{"FREQ=MONTHLY;BYDAY=-1FR", date(2026, 10, 23), false},
+		{"FREQ=MONTHLY;BYDAY=-1FR", date(2026, 11, 27), true},
+		// First workday of the quarter.
+		{"FREQ=MONTHLY;BYMONTH=1,4,7,10;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1", date(2026, 10, 1), true},
+		{"FREQ=MONTHLY;BYMONTH=1,4,7,10;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1", date(2026, 10, 2), false},
+		{"FREQ=MONTHLY;BYMONTH=1,4,7,10;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1", date(2026, 11, 2), false},
+		{"FREQ=MONTHLY;BYMONTH=1,4,7,10;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1", date(2028, 1, 1), false},
+		{"FREQ=MONTHLY;BYMONTH=1,4,7,10;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1", date(2028, 1, 3), true},
+		// Second Monday of the month, fourth Thursday of November.
+		{"FREQ=MONTHLY;BYDAY=2MO", date(2026, 10, 12), true},
+		{"FREQ=MONTHLY;BYDAY=2MO", date(2026, 10, 5), false},
+		{"FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", date(2026, 11, 26), true},
+		{"FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", date(2026, 11, 19), false},
+		// Months without the day are skipped rather than clamped.
+		{"FREQ=MONTHLY;BYMONTHDAY=31", date(2026, 10, 31), true},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", date(2026, 11, 30), false},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", date(2026, 11, 30), true},
+		// The day comes from DTSTART without BYDAY or BYMONTHDAY.
+		{"FREQ=MONTHLY;DTSTART=20260115", date(2026, 10, 15), true},
+		{"FREQ=MONTHLY;DTSTART=20260115", date(2026, 10, 16), false},
+		{"FREQ=YEARLY;DTSTART=20260317", date(2027, 3, 17), true},
+		{"FREQ=YEARLY;DTSTART=20260317", date(2027, 4, 17), false},
+		// COUNT and UNTIL.
+		{"FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3;DTSTART=20261007", date(2026, 10, 7), true},
+		{"FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3;DTSTART=20261007", date(2026, 10, 14), true},
+		{"FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3;DTSTART=20261007", date(2026, 10, 19), false},
+		{"FREQ=DAILY;UNTIL=20261020", date(2026, 10, 20), true},
+		{"FREQ=DAILY;UNTIL=20261020T235959Z", date(2026, 10, 21), false},
+	}
+	for _, tt := range tests {
+		r, err := ParseRule(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) error: %v", tt.rule, err)
+		}
+		if got := r.MatchesDate(tt.d); got != tt.want {
+			t.Errorf("%q on %s: got %v, want %v", tt.rule, tt.d.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestRRuleMatchesLegacyRules(t *testing.T) {
+	// The iCalendar form of every simple rule selects the same days.
+	for _, s := range []string{"daily", "weekdays", "weekly:mon,fri", "monthly:15", "monthly:31"} {
+		r, _ := ParseRule(s)
+		rr, err := ParseRule(r.RRule())
+		if err != nil {
+			t.Fatalf("ParseRule(%q) error: %v", r.RRule(), err)
+		}
+		for d := date(2026, 1, 1); d.Year() == 2026; d = d.AddDate(0, 0, 1) {
+			if r.MatchesDate(d) != rr.MatchesDate(d) {
+				t.Errorf("%q and %q differ on %s", s, r.RRule(), d.Format("2006-01-02"))
+			}
+		}
+	}
+}
+
+func TestRRuleString(t *testing.T) {
+	tests := map[string]string{
+		"rrule:freq=weekly;interval=2;byday=tu;dtstart=20261006":   "rrule:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;DTSTART=20261006",
+		"RRULE:FREQ=MONTHLY;BYDAY=-1FR":                            "rrule:FREQ=MONTHLY;BYDAY=-1FR",
+		"FREQ=MONTHLY;BYSETPOS=1;BYDAY=MO,TU,WE,TH,FR;BYMONTH=1,4": "rrule:FREQ=MONTHLY;BYMONTH=1,4;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1",
+		"FREQ=DAILY;UNTIL=20261231T000000Z":                        "rrule:FREQ=DAILY;UNTIL=20261231",
+	}
+	for in, want := range tests {
+		r, err := ParseRule(in)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) error: %v", in, err)
+		}
+		if got := r.String(); got != want {
+			t.Errorf("String(%q) = %q, want %q", in, got, want)
+		}
+		r2, err := ParseRule(want)
+		if err != nil || r2.String() != want {
+			t.Errorf("round-trip of %q failed: %q, %v", want, r2.String(), err)
+		}
+	}
+
+	r, _ := ParseRule("rrule:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;DTSTART=20261006")
+	if got := r.RRule(); got != "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU" {
+		t.Errorf("RRule() = %q, want it without DTSTART", got)
+	}
+}
+
+func TestParseRuleRRuleErrors(t *testing.T) {
+	for _, s := range []string{
+		"rrule:",
+		"rrule:BYDAY=MO",
+		"FREQ=HOURLY",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU",
+		"FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=2TU",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTH=13;BYMONTHDAY=1",
+		"FREQ=DAILY;COUNT=3;UNTIL=20261231;DTSTART=20260101",
+		"FREQ=DAILY;UNTIL=2026-12-31",
+		"FREQ=DAILY;DTSTART=20261231;UNTIL=20260101",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		if _, err := ParseRule(s); err == nil {
+			t.Errorf("ParseRule(%q): expected error", s)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package remind
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// Notification is a single reminder to deliver.
+type Notification struct {
+	TodoID int
+	Title  string // the todo text
+	Body   string // e.g. "Due today at 09:30"
+	Due    string // due time as "YYYY-MM-DD HH:MM"
+}
+
+// Notifier delivers notifications to the user.
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// Notifier kinds accepted by New and the reminder_notifier config option.
+const (
+	KindNotifySend = "notify-send" // desktop notification via notify-send
+	KindCommand    = "command"     // custom shell command
+	KindStdout     = "stdout"      // one line per notification on stdout
+)
+
+// New returns the notifier of the given kind. command is the shell command
+// for KindCommand; w receives the output of KindStdout.
+func New(kind, command string, w io.Writer) (Notifier, error) {
+	switch kind {
+	case "", KindNotifySend:
+		return NotifySend{Path: "notify-send"}, nil
+	case KindCommand:
+		if command == "" {
+			return nil, fmt.Errorf("reminder notifier %q needs a command", kind)
+		}
+		return Shell{Command: command}, nil
+	case KindStdout:
+		return Writer{W: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown reminder notifier %q (want notify-send, command or stdout)", kind)
+	}
+}
+
+// NotifySend shows a desktop notification with the notify-send command.
+type NotifySend struct {
+	Path string // notify-send executable
+}
+
+// Notify runs notify-send with the notification title and body.
+func (n NotifySend) Notify(note Notification) error {
+	cmd := exec.Command(n.Path, "--app-name=todo-calendar", note.Title, note.Body)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify-send: %w: %s", err, out)
+	}
+	return nil
+}
+
+// Shell runs a custom shell command for each notification. The notification
+// is passed in the TODO_ID, TODO_TITLE, TODO_BODY and TODO_DUE environment
+// variables.
+type Shell struct {
+	Command string
+}
+
+// Notify runs the command with "sh -c".
+func (s Shell) Notify(note Notification) error {
+	cmd := exec.Command("sh", "-c", s.Command)
+	cmd.Env = append(os.Environ(),
+		"TODO_ID="+strconv.Itoa(note.TodoID),
+		"TODO_TITLE="+note.Title,
+		"TODO_BODY="+note.Body,
+		"TODO_DUE="+note.Due,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("reminder command: %w: %s", err, out)
+	}
+	return nil
+}
+
+// Writer writes one tab-separated line per notification, for logs and tests.
+type Writer struct {
+	W io.Writer
+}
+
+// Notify writes "due<TAB>id<TAB>title<TAB>body".
+func (w Writer) Notify(note Notification) error {
+	_, err := fmt.Fprintf(w.W, "%s\t%d\t%s\t%s\n", note.Due, note.TodoID, note.Title, note.Body)
+	return err
+}
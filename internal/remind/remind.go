@@ -0,0 +1,200 @@
+// Package remind fires notifications for todo reminders. A reminder is an
+// offset in minutes before a todo is due; the due time is the todo's start
+// time, or a configurable time of day for untimed todos. Fired reminders are
+// recorded in the store so they are not repeated after a restart.
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// DefaultTime is the due time used for untimed todos when none is configured.
+const DefaultTime = "09:00"
+
+// missedAfter is how long after a todo is due its reminders still fire.
+// Older reminders (e.g. while nothing was running) are dropped silently.
+const missedAfter = time.Hour
+
+// dueLayout is the format of Notification.Due and Reminder.FiredFor.
+const dueLayout = "2006-01-02 15:04"
+
+// ParseOffsets parses a comma- or space-separated list of reminder offsets
+// such as "15m, 1h, 1d" into minutes. Plain numbers are minutes and "0"
+// means at the due time. The result is sorted and free of duplicates.
+func ParseOffsets(s string) ([]int, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	seen := make(map[int]bool)
+	var offsets []int
+	for _, f := range fields {
+		var minutes int
+		if days, ok := strings.CutSuffix(f, "d"); ok {
+			n, err := strconv.Atoi(days)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid reminder %q (want e.g. 15m, 1h or 1d)", f)
+			}
+			minutes = n * 24 * 60
+		} else {
+			n, err := store.ParseDuration(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid reminder %q (want e.g. 15m, 1h or 1d)", f)
+			}
+			minutes = n
+		}
+		if !seen[minutes] {
+			seen[minutes] = true
+			offsets = append(offsets, minutes)
+		}
+	}
+	sort.Ints(offsets)
+	return offsets, nil
+}
+
+// FormatOffsets formats reminder offsets for display and editing,
+// e.g. "15m, 1h, 1d". It is the inverse of ParseOffsets.
+func FormatOffsets(offsets []int) string {
+	parts := make([]string, 0, len(offsets))
+	for _, off := range offsets {
+		switch {
+		case off == 0:
+			parts = append(parts, "0m")
+		case off%(24*60) == 0:
+			parts = append(parts, fmt.Sprintf("%dd", off/(24*60)))
+		default:
+			parts = append(parts, store.FormatDuration(off))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DueAt returns when the todo is due in the given location: its date at its
+// start time, or at defaultTime for untimed todos. ok is false for todos
+// without a day date.
+func DueAt(t store.Todo, defaultTime string, loc *time.Location) (due time.Time, ok bool) {
+	if t.DatePrecision != "day" || t.Date == "" {
+		return time.Time{}, false
+	}
+	clock := t.StartTime
+	if clock == "" {
+		clock = defaultTime
+	}
+	if clock == "" {
+		clock = DefaultTime
+	}
+	due, err := time.ParseInLocation(dueLayout, t.Date+" "+clock, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return due, true
+}
+
+// Check delivers every reminder that is due at now and has not fired for
+// the todo's current due time, then records it as fired. Reminders that
+// fail to deliver stay pending and are retried on the next check. It
+// returns the number of notifications sent.
+func Check(s store.TodoStore, n Notifier, now time.Time, defaultTime string) (int, error) {
+	var errs []error
+	fired := 0
+	for _, r := range s.ListReminders() {
+		t := s.Find(r.TodoID)
+		if t == nil || t.Done {
+			continue
+		}
+		due, ok := DueAt(*t, defaultTime, now.Location())
+		if !ok {
+			continue
+		}
+		key := due.Format(dueLayout)
+		if r.FiredFor == key {
+			continue
+		}
+		if now.Before(due.Add(-time.Duration(r.Offset) * time.Minute)) {
+			continue
+		}
+		if now.After(due.Add(missedAfter)) {
+			s.MarkReminderFired(r.ID, key)
+			continue
+		}
+		note := Notification{
+			TodoID: t.ID,
+			Title:  t.Text,
+			Body:   describeDue(due, now, t.HasTime()),
+			Due:    key,
+		}
+		if err := n.Notify(note); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.MarkReminderFired(r.ID, key)
+		fired++
+	}
+	return fired, errors.Join(errs...)
+}
+
+// describeDue returns a short human description such as "Due today at 09:30"
+// or "Due tomorrow".
+func describeDue(due, now time.Time, timed bool) string {
+	day := due.Format("Mon 2 Jan")
+	today := now.Format("2006-01-02")
+	switch due.Format("2006-01-02") {
+	case today:
+		day = "today"
+	case now.AddDate(0, 0, 1).Format("2006-01-02"):
+		day = "tomorrow"
+	}
+	if timed {
+		return fmt.Sprintf("Due %s at %s", day, due.Format("15:04"))
+	}
+	return "Due " + day
+}
+
+// Run checks reminders immediately and then every interval until ctx is
+// done. Delivery errors are passed to onErr and do not stop the loop.
+func Run(ctx context.Context, s store.TodoStore, n Notifier, interval time.Duration, defaultTime string, onErr func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := Check(s, n, time.Now(), defaultTime); err != nil && onErr != nil {
+			onErr(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// TickMsg is sent periodically to trigger a reminder check in the TUI.
+type TickMsg time.Time
+
+// CheckedMsg reports the result of a reminder check run by CheckCmd.
+type CheckedMsg struct {
+	Fired int
+	Err   error
+}
+
+// ScheduleTick returns a tea.Cmd that sends a TickMsg after one minute.
+func ScheduleTick() tea.Cmd {
+	return tea.Tick(time.Minute, func(t time.Time) tea.Msg {
+		return TickMsg(t)
+	})
+}
+
+// CheckCmd returns a tea.Cmd that checks reminders in a goroutine, so slow
+// notifiers do not block the UI, and returns a CheckedMsg.
+func CheckCmd(s store.TodoStore, n Notifier, defaultTime string) tea.Cmd {
+	return func() tea.Msg {
+		fired, err := Check(s, n, time.Now(), defaultTime)
+		return CheckedMsg{Fired: fired, Err: err}
+	}
+}
@@ -0,0 +1,148 @@
+package remind
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+func newStore(t *testing.T) *store.SQLiteStore {
+	t.Helper()
+	s, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func at(clock string) time.Time {
+	t, _ := time.ParseInLocation("2006-01-02 15:04", "2026-10-20 "+clock, time.Local)
+	return t
+}
+
+// failNotifier fails every delivery.
+type failNotifier struct{}
+
+func (failNotifier) Notify(Notification) error { return errors.New("no notification daemon") }
+
+func TestParseAndFormatOffsets(t *testing.T) {
+	got, err := ParseOffsets("1h, 15m 1d,0,15")
+	if err != nil {
+		t.Fatalf("ParseOffsets: %v", err)
+	}
+	want := []int{0, 15, 60, 1440}
+	if len(got) != len(want) {
+		t.Fatalf("ParseOffsets = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ParseOffsets = %v, want %v", got, want)
+		}
+	}
+	if s := FormatOffsets(got); s != "0m, 15m, 1h, 1d" {
+		t.Errorf("FormatOffsets = %q", s)
+	}
+	for _, bad := range []string{"soon", "-5", "xd"} {
+		if _, err := ParseOffsets(bad); err == nil {
+			t.Errorf("ParseOffsets(%q) accepted", bad)
+		}
+	}
+}
+
+func TestCheckFiresOnce(t *testing.T) {
+	s := newStore(t)
+	todo := s.Add("Dentist", "2026-10-20", "day", 0)
+	s.SetTime(todo.ID, "10:00", 30)
+	s.SetReminders(todo.ID, []int{0, 15})
+
+	var out bytes.Buffer
+	n := Writer{W: &out}
+	steps := []struct {
+		now   string
+		fired int
+	}{
+		{"09:44", 0},
+		{"09:45", 1}, // 15 minutes before
+		{"09:50", 0}, // already fired
+		{"10:00", 1}, // at the due time
+	}
+	for _, st := range steps {
+		fired, err := Check(s, n, at(st.now), DefaultTime)
+		if err != nil || fired != st.fired {
+			t.Errorf("Check at %s = %d, %v; want %d", st.now, fired, err, st.fired)
+		}
+	}
+	if !strings.Contains(out.String(), "2026-10-20 10:00\t1\tDentist\tDue") {
+		t.Errorf("unexpected notifier output:\n%s", out.String())
+	}
+
+	// Rescheduling re-arms the reminders for the new due time.
+	s.SetTime(todo.ID, "11:00", 0)
+	if fired, _ := Check(s, n, at("10:45"), DefaultTime); fired != 1 {
+		t.Errorf("rescheduled reminder fired %d times, want 1", fired)
+	}
+
+	// Reminders more than an hour past due are dropped without notifying.
+	if fired, _ := Check(s, n, at("12:30"), DefaultTime); fired != 0 {
+		t.Errorf("missed reminder fired %d times, want 0", fired)
+	}
+	for _, r := range s.ListReminders() {
+		if r.FiredFor != "2026-10-20 11:00" {
+			t.Errorf("reminder %+v not marked for 11:00", r)
+		}
+	}
+}
+
+func TestCheckUntimedAndDone(t *testing.T) {
+	s := newStore(t)
+	untimed := s.Add("Pay rent", "2026-10-20", "day", 0)
+	s.SetReminders(untimed.ID, []int{60})
+	done := s.Add("Done already", "2026-10-20", "day", 0)
+	s.SetReminders(done.ID, []int{60})
+	s.Toggle(done.ID)
+	month := s.Add("Month todo", "2026-10-01", "month", 0)
+	s.SetReminders(month.ID, []int{0})
+
+	var out bytes.Buffer
+	if fired, _ := Check(s, Writer{W: &out}, at("07:59"), "09:00"); fired != 0 {
+		t.Errorf("fired %d before the default time minus offset", fired)
+	}
+	if fired, _ := Check(s, Writer{W: &out}, at("08:00"), "09:00"); fired != 1 {
+		t.Errorf("fired %d at 08:00, want 1:\n%s", fired, out.String())
+	}
+}
+
+func TestCheckRetriesFailedDelivery(t *testing.T) {
+	s := newStore(t)
+	todo := s.Add("Call", "2026-10-20", "day", 0)
+	s.SetTime(todo.ID, "10:00", 0)
+	s.SetReminders(todo.ID, []int{0})
+
+	if _, err := Check(s, failNotifier{}, at("10:00"), DefaultTime); err == nil {
+		t.Fatal("Check did not report the delivery error")
+	}
+	var out bytes.Buffer
+	if fired, _ := Check(s, Writer{W: &out}, at("10:01"), DefaultTime); fired != 1 {
+		t.Errorf("failed reminder not retried: fired %d", fired)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, kind := range []string{"", KindNotifySend, KindStdout} {
+		if _, err := New(kind, "", nil); err != nil {
+			t.Errorf("New(%q): %v", kind, err)
+		}
+	}
+	if _, err := New(KindCommand, "", nil); err == nil {
+		t.Error("New(command) without a command accepted")
+	}
+	if _, err := New("pager", "", nil); err == nil {
+		t.Error("New(unknown) accepted")
+	}
+}
@@ -7,12 +7,13 @@ type KeyMap struct {
 	Up     key.Binding
 	Down   key.Binding
 	Select key.Binding
+	Save   key.Binding
 	Cancel key.Binding
 }
 
 // ShortHelp returns key bindings for the short help view.
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.Select, k.Cancel}
+	return []key.Binding{k.Up, k.Down, k.Select, k.Save, k.Cancel}
 }
 
 // FullHelp returns key bindings for the full help view.
@@ -35,6 +36,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "jump"),
 		),
+		Save: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("C-s", "save view"),
+		),
 		Cancel: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "close"),
@@ -1,6 +1,7 @@
 package search
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -8,9 +9,11 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/antti/todo-calendar/internal/config"
 	"github.com/antti/todo-calendar/internal/fuzzy"
+	"github.com/antti/todo-calendar/internal/query"
 	"github.com/antti/todo-calendar/internal/store"
 	"github.com/antti/todo-calendar/internal/theme"
 )
@@ -25,10 +28,19 @@ type JumpMsg struct {
 // CloseMsg is emitted when the user presses Esc to close the search overlay.
 type CloseMsg struct{}
 
+// minFullTextQuery is the shortest query, in characters, searched with the
+// full-text index. Shorter queries are fuzzy-matched against titles.
+const minFullTextQuery = 3
+
 // Model represents the search overlay.
 type Model struct {
 	input      textinput.Model
-	results    []store.Todo
+	query      query.Query
+	err        error
+	naming     bool            // typing a name to save the query under
+	nameInput  textinput.Model
+	notice     string          // outcome of saving the query, until it is edited
+	results    []store.SearchResult
 	cursor     int
 	store      store.TodoStore
 	allTodos   []store.Todo
@@ -43,12 +55,17 @@ type Model struct {
 // New creates a new search overlay model.
 func New(s store.TodoStore, t theme.Theme, cfg config.Config) Model {
 	ti := textinput.New()
-	ti.Placeholder = "Search all todos..."
+	ti.Placeholder = "Search all todos (#tag, p:1, done:no, date:2026-10.. narrow it)..."
 	ti.Prompt = "? "
 	ti.Focus()
 
+	ni := textinput.New()
+	ni.Placeholder = "Name of the view"
+	ni.Prompt = "Save as: "
+
 	return Model{
 		input:         ti,
+		nameInput:     ni,
 		store:         s,
 		allTodos:      s.Todos(),
 		dateLayout:    cfg.DateLayout(),
@@ -78,13 +95,26 @@ func (m *Model) SetTheme(t theme.Theme) {
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.naming {
+			return m.updateNaming(msg)
+		}
 		switch {
 		case key.Matches(msg, m.keys.Cancel):
 			return m, func() tea.Msg { return CloseMsg{} }
 
+		case key.Matches(msg, m.keys.Save):
+			if m.err != nil || m.query.Empty() {
+				return m, nil
+			}
+			m.naming = true
+			m.notice = ""
+			m.nameInput.SetValue("")
+			m.input.Blur()
+			return m, m.nameInput.Focus()
+
 		case key.Matches(msg, m.keys.Select):
 			if len(m.results) > 0 && m.cursor >= 0 && m.cursor < len(m.results) {
-				r := m.results[m.cursor]
+				r := m.results[m.cursor].Todo
 				if r.HasDate() {
 					d, err := time.Parse("2006-01-02", r.Date)
 					if err == nil {
@@ -118,8 +148,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	// Forward to textinput and update results
 	var cmd tea.Cmd
+	prev := m.input.Value()
 	m.input, cmd = m.input.Update(msg)
-	m.results = m.fuzzySearch(m.input.Value())
+	if m.input.Value() != prev {
+		m.notice = ""
+	}
+	m.query, m.err = query.Parse(m.input.Value(), time.Now())
+	m.results = nil
+	if m.err == nil {
+		m.results = m.search(m.query)
+	}
 	// Clamp cursor
 	if m.cursor >= len(m.results) {
 		m.cursor = len(m.results) - 1
@@ -130,6 +168,28 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateNaming handles keys while the name of a view is typed: enter saves
+// the query as a saved search, esc goes back to the query.
+func (m Model) updateNaming(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		ss, err := m.store.SaveSearch(m.nameInput.Value(), m.input.Value())
+		if err != nil {
+			m.notice = err.Error()
+		} else {
+			m.notice = fmt.Sprintf("Saved view %q (v in the todo pane cycles views)", ss.Name)
+		}
+		fallthrough
+	case tea.KeyEsc:
+		m.naming = false
+		m.nameInput.Blur()
+		return m, m.input.Focus()
+	}
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd
+}
+
 // View renders the search overlay.
 func (m Model) View() string {
 	var b strings.Builder
@@ -141,23 +201,51 @@ func (m Model) View() string {
 	b.WriteString(m.input.View())
 	b.WriteString("\n\n")
 
-	query := m.input.Value()
+	switch {
+	case m.naming:
+		b.WriteString(m.nameInput.View())
+		b.WriteString("\n\n")
+	case m.notice != "":
+		b.WriteString(m.styles.Hint.Render(m.notice))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.query.Filters) > 0 {
+		for i, f := range m.query.Filters {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(m.styles.Chip.Render(f.String()))
+		}
+		b.WriteString("\n\n")
+	}
 
-	if query == "" {
+	if m.err != nil {
+		b.WriteString(m.styles.Error.Render(m.err.Error()))
+	} else if m.query.Empty() {
 		b.WriteString(m.styles.Empty.Render("Type to search across all months"))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Hint.Render("Filters: p:1..2 done:no date:2026-10..2026-12 prec:month has:body recurring:yes #tag (-filter negates)"))
 	} else if len(m.results) == 0 {
 		b.WriteString(m.styles.Empty.Render("(no matches)"))
 	} else {
-		maxVisible := m.height - 8
-		if maxVisible < 1 {
-			maxVisible = 1
+		maxLines := m.height - 8
+		if len(m.query.Filters) > 0 {
+			maxLines -= 2
 		}
-		visible := len(m.results)
-		if visible > maxVisible {
-			visible = maxVisible
+		if maxLines < 1 {
+			maxLines = 1
 		}
-		for i := 0; i < visible; i++ {
-			r := m.results[i]
+		lines := 0
+		for i, res := range m.results {
+			lines++
+			if res.Snippet != "" {
+				lines++
+			}
+			if lines > maxLines && i > 0 {
+				break
+			}
+			r := res.Todo
 
 			// Checkbox
 			check := "[ ]"
@@ -172,22 +260,42 @@ func (m Model) View() string {
 			dateStr := "No date"
 			if r.HasDate() {
 				dateStr = config.FormatDate(r.Date, m.dateLayout)
+				if r.HasTime() {
+					dateStr += " " + r.StartTime
+				}
+			}
+
+			// Tag chips
+			var chips string
+			for _, tag := range r.Tags {
+				chips += " " + m.styles.Tag.Render("#"+tag)
 			}
 
 			if i == m.cursor {
 				b.WriteString(m.styles.SelectedResult.Render("> "))
 				b.WriteString(badge)
-				b.WriteString(m.styles.SelectedResult.Render(check + " " + r.Text))
+				b.WriteString(m.styles.SelectedResult.Render(check + " "))
+				b.WriteString(renderMatches(res.Title, m.styles.SelectedResult, m.styles.Match))
+				b.WriteString(chips)
 				b.WriteString("  ")
 				b.WriteString(m.styles.SelectedDate.Render(dateStr))
 			} else {
 				b.WriteString("  ")
 				b.WriteString(badge)
-				b.WriteString(m.styles.ResultText.Render(check + " " + r.Text))
+				b.WriteString(m.styles.ResultText.Render(check + " "))
+				b.WriteString(renderMatches(res.Title, m.styles.ResultText, m.styles.Match))
+				b.WriteString(chips)
 				b.WriteString("  ")
 				b.WriteString(m.styles.ResultDate.Render(dateStr))
 			}
 			b.WriteString("\n")
+			// Body matches are shown below the title, indented past the
+			// cursor, priority badge and checkbox.
+			if res.Snippet != "" {
+				b.WriteString(strings.Repeat(" ", 2+lipgloss.Width(badge)+4))
+				b.WriteString(renderMatches(res.Snippet, m.styles.Snippet, m.styles.Match))
+				b.WriteString("\n")
+			}
 		}
 	}
 
@@ -245,11 +353,56 @@ func renderPriorityBars(priority int, style string, s Styles) string {
 	return result + " "
 }
 
-// fuzzySearch filters allTodos by fuzzy match and sorts by score (best first).
-func (m Model) fuzzySearch(query string) []store.Todo {
-	if query == "" {
+// renderMatches renders text marked up by the store's full-text search,
+// with the words between MatchStart and MatchEnd in the match style.
+func renderMatches(text string, base, match lipgloss.Style) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(text, store.MatchStart)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], store.MatchEnd)
+		if end < 0 {
+			break
+		}
+		end += start
+		if start > 0 {
+			b.WriteString(base.Render(text[:start]))
+		}
+		b.WriteString(match.Render(text[start+len(store.MatchStart) : end]))
+		text = text[end+len(store.MatchEnd):]
+	}
+	if text != "" {
+		b.WriteString(base.Render(text))
+	}
+	return b.String()
+}
+
+// search returns the todos matching q. The store applies the filters
+// and looks the text up in its full-text index; text shorter than
+// minFullTextQuery is fuzzy-matched against the titles instead.
+func (m Model) search(q query.Query) []store.SearchResult {
+	if q.Empty() {
 		return nil
 	}
+	if len([]rune(q.Text)) >= minFullTextQuery {
+		return m.store.QueryTodos(q)
+	}
+
+	candidates := m.allTodos
+	if len(q.Filters) > 0 {
+		candidates = nil
+		for _, r := range m.store.QueryTodos(query.Query{Filters: q.Filters}) {
+			candidates = append(candidates, r.Todo)
+		}
+	}
+	return fuzzySearch(candidates, q.Text)
+}
+
+// fuzzySearch filters todos by fuzzy match of their titles against text
+// and sorts them by score (best first).
+func fuzzySearch(todos []store.Todo, text string) []store.SearchResult {
 
 	type scored struct {
 		todo  store.Todo
@@ -257,8 +410,8 @@ func (m Model) fuzzySearch(query string) []store.Todo {
 	}
 
 	var matches []scored
-	for _, t := range m.allTodos {
-		if matched, score := fuzzy.Match(query, t.Text); matched {
+	for _, t := range todos {
+		if matched, score := fuzzy.Match(text, t.Text); matched {
 			matches = append(matches, scored{todo: t, score: score})
 		}
 	}
@@ -270,14 +423,20 @@ func (m Model) fuzzySearch(query string) []store.Todo {
 		return matches[i].todo.Date > matches[j].todo.Date
 	})
 
-	results := make([]store.Todo, len(matches))
+	results := make([]store.SearchResult, len(matches))
 	for i, m := range matches {
-		results[i] = m.todo
+		results[i] = store.SearchResult{Todo: m.todo, Title: m.todo.Text}
 	}
 	return results
 }
 
 // HelpBindings returns search-specific key bindings for help bar display.
 func (m Model) HelpBindings() []key.Binding {
-	return []key.Binding{m.keys.Up, m.keys.Down, m.keys.Select, m.keys.Cancel}
+	if m.naming {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "save")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+		}
+	}
+	return []key.Binding{m.keys.Up, m.keys.Down, m.keys.Select, m.keys.Save, m.keys.Cancel}
 }
@@ -13,6 +13,11 @@ type Styles struct {
 	SelectedResult lipgloss.Style
 	SelectedDate   lipgloss.Style
 	Hint           lipgloss.Style
+	Match          lipgloss.Style
+	Snippet        lipgloss.Style
+	Tag            lipgloss.Style
+	Chip           lipgloss.Style
+	Error          lipgloss.Style
 	Empty          lipgloss.Style
 	PriorityP1     lipgloss.Style
 	PriorityP2     lipgloss.Style
@@ -30,6 +35,11 @@ func NewStyles(t theme.Theme) Styles {
 		SelectedResult: lipgloss.NewStyle().Bold(true).Foreground(t.AccentFg),
 		SelectedDate:   lipgloss.NewStyle().Foreground(t.AccentFg),
 		Hint:           lipgloss.NewStyle().Foreground(t.MutedFg),
+		Match:          lipgloss.NewStyle().Bold(true).Underline(true).Foreground(t.AccentFg),
+		Snippet:        lipgloss.NewStyle().Foreground(t.MutedFg),
+		Tag:            lipgloss.NewStyle().Foreground(t.AccentFg),
+		Chip:           lipgloss.NewStyle().Bold(true).Foreground(t.NormalBg).Background(t.AccentFg).Padding(0, 1),
+		Error:          lipgloss.NewStyle().Foreground(t.PriorityP1Fg),
 		Empty:          lipgloss.NewStyle().Foreground(t.MutedFg),
 		PriorityP1:     lipgloss.NewStyle().Bold(true).Foreground(t.PriorityP1Fg),
 		PriorityP2:     lipgloss.NewStyle().Bold(true).Foreground(t.PriorityP2Fg),
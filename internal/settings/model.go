@@ -40,12 +40,13 @@ type StartGoogleAuthMsg struct{}
 type GoogleAuthDoneMsg struct{ State google.AuthState }
 
 // googleCalendarRow is the index of the Google Calendar action row.
-const googleCalendarRow = 7
+const googleCalendarRow = 8
 
 // Model represents the settings overlay.
 type Model struct {
 	options         []option
-	cursor          int // which option row is selected (0-6)
+	cfg             config.Config // settings without a row are kept as loaded
+	cursor          int // which option row is selected (0-8)
 	width           int
 	height          int
 	keys            KeyMap
@@ -120,30 +121,34 @@ func New(cfg config.Config, t theme.Theme, authState google.AuthState) Model {
 			{label: "Show Month Todos", values: boolValues, display: boolDisplay, index: boolIndex(cfg.ShowMonthTodos)},
 			{label: "Show Year Todos", values: boolValues, display: boolDisplay, index: boolIndex(cfg.ShowYearTodos)},
 			{label: "Priority Style", values: []string{"bars", "nerd"}, display: []string{"▁▃▅▇ Bars", "\U000F08BF Nerd Font"}, index: indexOf([]string{"bars", "nerd"}, cfg.PriorityStyle)},
+			{label: "Postpone Holidays", values: boolValues, display: []string{"Skip", "Allow"}, index: boolIndex(cfg.PostponeSkipHolidays)},
 			gcalOption,
 		},
+		cfg:             cfg,
 		keys:            DefaultKeyMap(),
 		styles:          NewStyles(t),
 		googleAuthState: authState,
 	}
 }
 
-// Config returns a config.Config reflecting the current option selections.
+// Config returns the loaded config.Config with the current option
+// selections applied; settings without a row keep their loaded values.
 func (m Model) Config() config.Config {
 	gcalEnabled := true // default when not AuthReady
 	if m.googleAuthState == google.AuthReady {
 		gcalEnabled = m.options[googleCalendarRow].values[m.options[googleCalendarRow].index] == "true"
 	}
-	return config.Config{
-		Theme:                 m.options[0].values[m.options[0].index],
-		Country:               m.options[1].values[m.options[1].index],
-		FirstDayOfWeek:        m.options[2].values[m.options[2].index],
-		DateFormat:             m.options[3].values[m.options[3].index],
-		ShowMonthTodos:         m.options[4].values[m.options[4].index] == "true",
-		ShowYearTodos:          m.options[5].values[m.options[5].index] == "true",
-		PriorityStyle:          m.options[6].values[m.options[6].index],
-		GoogleCalendarEnabled: gcalEnabled,
-	}
+	cfg := m.cfg
+	cfg.Theme = m.options[0].values[m.options[0].index]
+	cfg.Country = m.options[1].values[m.options[1].index]
+	cfg.FirstDayOfWeek = m.options[2].values[m.options[2].index]
+	cfg.DateFormat = m.options[3].values[m.options[3].index]
+	cfg.ShowMonthTodos = m.options[4].values[m.options[4].index] == "true"
+	cfg.ShowYearTodos = m.options[5].values[m.options[5].index] == "true"
+	cfg.PriorityStyle = m.options[6].values[m.options[6].index]
+	cfg.PostponeSkipHolidays = m.options[7].values[m.options[7].index] == "true"
+	cfg.GoogleCalendarEnabled = gcalEnabled
+	return cfg
 }
 
 // SetGoogleAuthState updates the stored auth state and display text,
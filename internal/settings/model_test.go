@@ -0,0 +1,38 @@
+package settings
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/antti/todo-calendar/internal/config"
+	"github.com/antti/todo-calendar/internal/google"
+	"github.com/antti/todo-calendar/internal/theme"
+)
+
+func TestChangingSettingKeepsOthers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RemindersEnabled = false
+	cfg.ReminderNotifier = "command"
+	cfg.ReminderCommand = "echo"
+	cfg.ReminderTime = "07:30"
+
+	m := New(cfg, theme.ForName(cfg.Theme), google.AuthNotConfigured)
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if cmd == nil {
+		t.Fatal("changing the theme emitted no message")
+	}
+	msg, ok := cmd().(SettingChangedMsg)
+	if !ok {
+		t.Fatalf("got %T, want SettingChangedMsg", cmd())
+	}
+
+	got := msg.Cfg
+	if got.Theme == cfg.Theme {
+		t.Errorf("theme not changed: %q", got.Theme)
+	}
+	got.Theme = cfg.Theme
+	if got != cfg {
+		t.Errorf("settings without a row changed:\n got  %+v\n want %+v", got, cfg)
+	}
+}
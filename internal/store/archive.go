@@ -0,0 +1,52 @@
+package store
+
+import "time"
+
+// Archive moves the completed todo with the given ID out of the main views.
+// Open todos are left alone.
+func (s *SQLiteStore) Archive(id int) {
+	before := s.snapshot(id)
+	now := time.Now().Format(timestampFormat)
+	s.db.Exec(`UPDATE todos SET archived_at = ?, completed_at = COALESCE(completed_at, ?)
+		WHERE id = ? AND done = 1 AND archived_at IS NULL`, now, now, id)
+	s.record("archive", id, before)
+}
+
+// Unarchive moves the todo with the given ID back into the main views.
+func (s *SQLiteStore) Unarchive(id int) {
+	before := s.snapshot(id)
+	s.db.Exec("UPDATE todos SET archived_at = NULL WHERE id = ?", id)
+	s.record("unarchive", id, before)
+}
+
+// ArchiveCompleted archives every todo outside the trash that was completed
+// before the given time and returns how many were archived.
+func (s *SQLiteStore) ArchiveCompleted(before time.Time) int {
+	now := time.Now().Format(timestampFormat)
+	result, err := s.db.Exec(`UPDATE todos SET archived_at = ?
+		WHERE done = 1 AND archived_at IS NULL AND deleted_at IS NULL AND completed_at < ?`,
+		now, before.Format(timestampFormat))
+	if err != nil {
+		return 0
+	}
+	n, _ := result.RowsAffected()
+	return int(n)
+}
+
+// ArchivedTodos returns the archived todos completed between from and to
+// (inclusive, YYYY-MM-DD), most recently completed first.
+func (s *SQLiteStore) ArchivedTodos(from, to string) []Todo {
+	rows, err := s.db.Query(
+		"SELECT "+todoColumns+` FROM todos
+		WHERE archived_at IS NOT NULL AND deleted_at IS NULL
+		AND substr(completed_at, 1, 10) BETWEEN ? AND ?
+		ORDER BY completed_at DESC, id DESC`,
+		from, to,
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	todos, _ := scanTodos(rows)
+	return todos
+}
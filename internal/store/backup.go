@@ -0,0 +1,357 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// BackupFormat identifies a todo-calendar backup document.
+	BackupFormat = "todo-calendar-backup"
+	// BackupVersion is the version of the backup document layout.
+	BackupVersion = 1
+
+	// migrationBackups is how many backups taken before schema migrations
+	// are kept.
+	migrationBackups = 5
+)
+
+// Backup is a full copy of the database: every row of every table, keyed
+// by column name. Schema is the database schema version the rows were
+// read from; restoring a backup of an older schema migrates it.
+type Backup struct {
+	Format    string                      `json:"format"`
+	Version   int                         `json:"version"`
+	Schema    int                         `json:"schema"`
+	CreatedAt string                      `json:"created_at"`
+	Tables    map[string][]map[string]any `json:"tables"`
+}
+
+// ReadBackup decodes and validates a backup document.
+func ReadBackup(r io.Reader) (*Backup, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var b Backup
+	if err := dec.Decode(&b); err != nil {
+		return nil, fmt.Errorf("read backup: %w", err)
+	}
+	if b.Format != BackupFormat {
+		return nil, fmt.Errorf("not a todo-calendar backup")
+	}
+	if b.Version < 1 || b.Version > BackupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", b.Version)
+	}
+	if b.Schema < 1 || b.Schema > schemaVersion {
+		return nil, fmt.Errorf("unsupported database schema %d (this version supports up to %d)", b.Schema, schemaVersion)
+	}
+	for _, rows := range b.Tables {
+		for _, row := range rows {
+			for col, v := range row {
+				if n, ok := v.(json.Number); ok {
+					row[col] = numberValue(n)
+				}
+			}
+		}
+	}
+	return &b, nil
+}
+
+// WriteBackup encodes a backup document as indented JSON.
+func WriteBackup(w io.Writer, b *Backup) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// numberValue converts a decoded JSON number back to the integer or real
+// it was read from.
+func numberValue(n json.Number) any {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+// Backup reads every table of the database into a backup document.
+func (s *SQLiteStore) Backup() (*Backup, error) {
+	version, err := s.schemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	tables, err := userTables(s.db)
+	if err != nil {
+		return nil, err
+	}
+	b := &Backup{
+		Format:    BackupFormat,
+		Version:   BackupVersion,
+		Schema:    version,
+		CreatedAt: time.Now().Format(timestampFormat),
+		Tables:    make(map[string][]map[string]any),
+	}
+	for _, table := range tables {
+		rows, err := dumpTable(s.db, table)
+		if err != nil {
+			return nil, err
+		}
+		b.Tables[table] = rows
+	}
+	return b, nil
+}
+
+// RestoreBackup replaces the contents of the database with the backup.
+// The rows are first loaded into a scratch database at the backup's
+// schema version and migrated from there, so backups taken by older
+// versions restore with the same data changes an upgrade would make. The
+// undo history is replaced by the backup's.
+func (s *SQLiteStore) RestoreBackup(b *Backup) error {
+	dir, err := os.MkdirTemp("", "todo-calendar-restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	scratch := filepath.Join(dir, "restore.db")
+	if err := loadBackup(scratch, b); err != nil {
+		return err
+	}
+
+	tables, err := userTables(s.db)
+	if err != nil {
+		return err
+	}
+
+	// ATTACH has to happen on the connection running the transaction and
+	// outside it.
+	ctx := context.Background()
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS backup", scratch); err != nil {
+		return fmt.Errorf("attach backup: %w", err)
+	}
+	defer conn.ExecContext(ctx, "DETACH DATABASE backup")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		return err
+	}
+	// Clearing sqlite_sequence restarts the AUTOINCREMENT counters at the
+	// restored rows' IDs.
+	for _, table := range append(tables, "sqlite_sequence") {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM main.%q", table)); err != nil {
+			return fmt.Errorf("clear %s: %w", table, err)
+		}
+	}
+	for _, table := range tables {
+		cols, err := tableColumns(tx, "main", table)
+		if err != nil {
+			return err
+		}
+		list := quoteColumns(cols)
+		if _, err := tx.Exec(fmt.Sprintf("INSERT INTO main.%q (%s) SELECT %s FROM backup.%q", table, list, list, table)); err != nil {
+			return fmt.Errorf("restore %s: %w", table, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// loadBackup creates a database at path with the backup's schema, fills
+// it with the backup's rows and migrates it to the current schema.
+func loadBackup(path string, b *Backup) error {
+	scratch, err := openDB(path)
+	if err != nil {
+		return err
+	}
+	defer scratch.Close()
+	s := &SQLiteStore{db: scratch}
+	if err := s.migrateTo(b.Schema); err != nil {
+		return fmt.Errorf("create schema %d: %w", b.Schema, err)
+	}
+
+	tables, err := userTables(scratch)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, table := range tables {
+		known[table] = true
+	}
+	for table := range b.Tables {
+		if !known[table] {
+			return fmt.Errorf("backup has unknown table %q", table)
+		}
+	}
+
+	tx, err := scratch.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		return err
+	}
+	// Migrations seed some tables (the default templates); the backup
+	// replaces those rows too.
+	for _, table := range tables {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %q", table)); err != nil {
+			return err
+		}
+	}
+	for _, table := range tables {
+		for _, row := range b.Tables[table] {
+			if err := insertRow(tx, table, row); err != nil {
+				return fmt.Errorf("restore %s: %w", table, err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	return s.migrateTo(schemaVersion)
+}
+
+// backupBeforeMigrate writes a backup of the database, at schema version,
+// into the backups directory next to it and deletes all but the newest
+// migrationBackups of them.
+func (s *SQLiteStore) backupBeforeMigrate(version int) error {
+	if s.path == "" {
+		return nil
+	}
+	b, err := s.Backup()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(filepath.Dir(s.path), "backups")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-schema%d.json", time.Now().Format("20060102-150405"), version)
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	if err := WriteBackup(f, b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	old, err := filepath.Glob(filepath.Join(dir, "*-schema*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(old)
+	for len(old) > migrationBackups {
+		os.Remove(old[0])
+		old = old[1:]
+	}
+	return nil
+}
+
+// userTables returns the names of the tables holding data, excluding
+// SQLite's internal tables and the full-text index, which is rebuilt from
+// the todos table by its triggers.
+func userTables(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE 'todos_fts%' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+// dumpTable reads all rows of a table, keyed by column name.
+func dumpTable(db *sql.DB, table string) ([]map[string]any, error) {
+	rows, err := db.Query(fmt.Sprintf("SELECT * FROM %q ORDER BY rowid", table))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", table, err)
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	dump := []map[string]any{}
+	for rows.Next() {
+		values := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(cols))
+		for i, col := range cols {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[col] = values[i]
+		}
+		dump = append(dump, row)
+	}
+	return dump, rows.Err()
+}
+
+// tableColumns returns the column names of a table in the given schema.
+func tableColumns(tx *sql.Tx, schema, table string) ([]string, error) {
+	rows, err := tx.Query(fmt.Sprintf("SELECT * FROM %s.%q LIMIT 0", schema, table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return rows.Columns()
+}
+
+// insertRow inserts a backed-up row. Columns missing from the row get
+// their defaults; columns the table lacks are an error.
+func insertRow(tx *sql.Tx, table string, row map[string]any) error {
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	args := make([]any, len(cols))
+	for i, col := range cols {
+		args[i] = row[col]
+	}
+	marks := strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+	_, err := tx.Exec(fmt.Sprintf("INSERT INTO %q (%s) VALUES (%s)", table, quoteColumns(cols), marks), args...)
+	return err
+}
+
+// quoteColumns joins column names into a quoted, comma-separated list.
+func quoteColumns(cols []string) string {
+	quoted := make([]string, len(cols))
+	for i, col := range cols {
+		quoted[i] = fmt.Sprintf("%q", col)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -1,6 +1,10 @@
 package store
 
-import "time"
+import (
+	"time"
+
+	"github.com/antti/todo-calendar/internal/query"
+)
 
 // TodoStore defines the contract for todo persistence.
 // Consumers depend on this interface, not the concrete backend.
@@ -21,6 +25,7 @@ type TodoStore interface {
 	TodoCountsByMonth() []MonthCount
 	FloatingTodoCounts() FloatingCount
 	UpdateBody(id int, body string)
+	SetTime(id int, startTime string, duration int)
 	AddTemplate(name, content string) (Template, error)
 	ListTemplates() []Template
 	FindTemplate(id int) *Template
@@ -33,10 +38,71 @@ type TodoStore interface {
 	DeleteSchedule(id int)
 	UpdateSchedule(id int, cadenceType, cadenceValue, placeholderDefaults string) error
 	TodoExistsForSchedule(scheduleID int, date string) bool
+	FindScheduled(scheduleID int, date string) *Todo
 	AddScheduledTodo(text, date, body string, scheduleID int) Todo
+	AddShiftedTodo(text, date, scheduleDate, body string, scheduleID int) Todo
+	SetHolidayPolicy(id int, policy string) error
+	SetScheduleWindow(id, lookaheadDays int, catchUp string) error
+	SetGeneratedThrough(id int, date string) error
+	SetScheduleRange(id int, startDate, endDate string, maxOccurrences int) error
+	SetSchedulePaused(id int, paused bool) error
+	SetScheduleException(id int, date string, skip bool) error
 	HighestPriorityPerDay(year int, month time.Month) map[int]int
 	SwapOrder(id1, id2 int)
-	SearchTodos(query string) []Todo
+	SearchTodos(text string) []SearchResult
+	QueryTodos(q query.Query) []SearchResult
+	// Tag operations
+	SetTags(id int, tags []string)
+	AddTag(id int, tag string)
+	RemoveTag(id int, tag string)
+	TodosWithTag(tag string) []Todo
+	ListTags() []string
+	// Project operations
+	AddProject(name string) (Project, error)
+	ListProjects() []Project
+	FindProject(id int) *Project
+	FindProjectByName(name string) *Project
+	RenameProject(id int, name string) error
+	DeleteProject(id int)
+	SetProject(id int, projectID int)
+	ProjectCounts() []ProjectCount
+	// Saved search operations
+	SaveSearch(name, q string) (SavedSearch, error)
+	ListSavedSearches() []SavedSearch
+	DeleteSavedSearch(id int)
+	SavedSearchCounts() []SavedSearchCount
+	// Reminder operations
+	SetReminders(id int, offsets []int)
+	ListReminders() []Reminder
+	MarkReminderFired(id int, firedFor string)
+	// Trash operations
+	TrashedTodos() []Todo
+	Restore(id int)
+	Purge(id int)
+	PurgeTrash(before time.Time) int
+	// Archive operations
+	Archive(id int)
+	Unarchive(id int)
+	ArchiveCompleted(before time.Time) int
+	ArchivedTodos(from, to string) []Todo
+	// Overdue operations
+	OverdueTodos(today string) []Todo
+	RollOver(id int, today string)
+	// Repeat operations
+	SetRepeat(id int, repeat string)
+	AddOccurrence(id int, date string) Todo
+	SeriesTodos(seriesID int) []Todo
+	// Import operations
+	FindByUID(uid string) *Todo
+	SetUID(id int, uid string)
+	DatabaseID() string
+	// Backup operations
+	Backup() (*Backup, error)
+	RestoreBackup(b *Backup) error
+	// Undo operations
+	Batch(label string, fn func())
+	Undo() (label string, ok bool)
+	Redo() (label string, ok bool)
 	EnsureSortOrder()
 	Save() error
 }
@@ -49,6 +115,32 @@ type MonthCount struct {
 	Completed int
 }
 
+// ProjectCount holds pending and completed todo counts for a project.
+type ProjectCount struct {
+	ProjectID int
+	Name      string
+	Pending   int
+	Completed int
+}
+
+// SavedSearchCount holds pending and completed counts of the todos
+// matching a saved search.
+type SavedSearchCount struct {
+	SearchID  int
+	Name      string
+	Pending   int
+	Completed int
+}
+
+// Reminder is a notification scheduled Offset minutes before a todo is due.
+// FiredFor is the due time ("YYYY-MM-DD HH:MM") it last fired for, or "".
+type Reminder struct {
+	ID       int
+	TodoID   int
+	Offset   int
+	FiredFor string
+}
+
 // FloatingCount holds pending and completed counts for undated todos.
 type FloatingCount struct {
 	Pending   int
@@ -0,0 +1,366 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// journalLimit is the number of operations kept for undo.
+const journalLimit = 100
+
+// The journal records every todo mutation as a pair of snapshots: the todo
+// before and after the change (NULL when it did not exist). Undoing an
+// operation restores the "before" snapshots of its entries in reverse
+// order; redoing it restores the "after" snapshots. Entries share an op_id
+// when they belong to the same user action (see Batch). Undone operations
+// stay in the journal for redo until a new operation is recorded. Entries
+// with a schedule_id snapshot the scheduleState of that schedule instead
+// of a todo; their todo_id is 0.
+
+// Batch runs fn and records every todo mutation it makes as a single
+// operation, so one Undo reverts all of them. label describes the
+// operation, e.g. "edit". Nested calls join the outer batch. Batch is not
+// safe for concurrent use.
+func (s *SQLiteStore) Batch(label string, fn func()) {
+	if s.batchLabel != "" {
+		fn()
+		return
+	}
+	s.batchLabel = label
+	defer func() {
+		s.batchOp = 0
+		s.batchLabel = ""
+	}()
+	fn()
+}
+
+// Undo reverts the most recent operation that has not been undone and
+// returns its label. ok is false when there is nothing to undo.
+func (s *SQLiteStore) Undo() (label string, ok bool) {
+	var op int
+	if err := s.db.QueryRow("SELECT COALESCE(MAX(op_id), 0) FROM journal WHERE undone = 0").Scan(&op); err != nil || op == 0 {
+		return "", false
+	}
+	return s.replay(op, true)
+}
+
+// Redo re-applies the most recently undone operation and returns its
+// label. ok is false when there is nothing to redo.
+func (s *SQLiteStore) Redo() (label string, ok bool) {
+	var op int
+	if err := s.db.QueryRow("SELECT COALESCE(MIN(op_id), 0) FROM journal WHERE undone = 1").Scan(&op); err != nil || op == 0 {
+		return "", false
+	}
+	return s.replay(op, false)
+}
+
+// nextOp discards the redo history and allocates a new operation id.
+func (s *SQLiteStore) nextOp() int {
+	s.db.Exec("DELETE FROM journal WHERE undone = 1")
+	var op int
+	s.db.QueryRow("SELECT COALESCE(MAX(op_id), 0) + 1 FROM journal").Scan(&op)
+	return op
+}
+
+// snapshot returns the todo with the given ID, including trashed todos, or
+// nil if it does not exist. Mutations take one before changing a todo and
+// pass it to record.
+func (s *SQLiteStore) snapshot(id int) *Todo {
+	row := s.db.QueryRow("SELECT "+todoColumns+" FROM todos WHERE id = ?", id)
+	t, err := scanTodo(row)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// record journals the change made to the todo with the given ID since
+// before was taken. Mutations that changed nothing are not recorded, so
+// they do not discard the redo history either.
+func (s *SQLiteStore) record(label string, id int, before *Todo) {
+	s.appendJournal(label, id, nil, encodeSnapshot(before), encodeSnapshot(s.snapshot(id)))
+}
+
+// appendJournal adds a journal entry for a todo, or for the schedule
+// scheduleID when it is not nil, to the current operation.
+func (s *SQLiteStore) appendJournal(label string, todoID int, scheduleID any, beforeJSON, afterJSON any) {
+	if beforeJSON == afterJSON {
+		return
+	}
+	op := s.batchOp
+	if s.batchLabel != "" {
+		if op == 0 {
+			op = s.nextOp()
+			s.batchOp = op
+		}
+		label = s.batchLabel
+	} else {
+		op = s.nextOp()
+	}
+	s.db.Exec(
+		"INSERT INTO journal (op_id, label, todo_id, schedule_id, before, after) VALUES (?, ?, ?, ?, ?, ?)",
+		op, label, todoID, scheduleID, beforeJSON, afterJSON,
+	)
+	s.db.Exec("DELETE FROM journal WHERE op_id <= ?", op-journalLimit)
+}
+
+// replay restores the todos touched by operation op to their state before
+// (undo) or after (redo) it, and flips the operation's undone flag.
+func (s *SQLiteStore) replay(op int, undo bool) (string, bool) {
+	order, column := "ASC", "after"
+	if undo {
+		order, column = "DESC", "before"
+	}
+	rows, err := s.db.Query("SELECT label, todo_id, schedule_id, "+column+" FROM journal WHERE op_id = ? ORDER BY id "+order, op)
+	if err != nil {
+		return "", false
+	}
+	type entry struct {
+		id       int
+		schedule sql.NullInt64
+		snap     sql.NullString
+	}
+	var label string
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&label, &e.id, &e.schedule, &e.snap); err != nil {
+			rows.Close()
+			return "", false
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", false
+	}
+	defer tx.Rollback()
+	for _, e := range entries {
+		restore := func() error { return restoreTodo(tx, e.id, e.snap) }
+		if e.schedule.Valid {
+			restore = func() error { return restoreSchedule(tx, int(e.schedule.Int64), e.snap) }
+		}
+		if err := restore(); err != nil {
+			return "", false
+		}
+	}
+	undone := 0
+	if undo {
+		undone = 1
+	}
+	if _, err := tx.Exec("UPDATE journal SET undone = ? WHERE op_id = ?", undone, op); err != nil {
+		return "", false
+	}
+	if err := tx.Commit(); err != nil {
+		return "", false
+	}
+	return label, true
+}
+
+// restoreTodo writes a journal snapshot back: the todo row, its tags and
+// its reminders. A NULL snapshot deletes the todo. References to projects
+// or schedules deleted in the meantime are dropped.
+func restoreTodo(tx *sql.Tx, id int, snap sql.NullString) error {
+	if !snap.Valid {
+		_, err := tx.Exec("DELETE FROM todos WHERE id = ?", id)
+		return err
+	}
+	var t Todo
+	if err := json.Unmarshal([]byte(snap.String), &t); err != nil {
+		return err
+	}
+	var date, scheduleDate, deletedAt, completedAt, archivedAt, uid, seriesID any
+	done := 0
+	if t.Done {
+		done = 1
+	}
+	if t.Date != "" {
+		date = t.Date
+	}
+	if t.ScheduleDate != "" {
+		scheduleDate = t.ScheduleDate
+	}
+	if t.DeletedAt != "" {
+		deletedAt = t.DeletedAt
+	}
+	if t.CompletedAt != "" {
+		completedAt = t.CompletedAt
+	}
+	if t.ArchivedAt != "" {
+		archivedAt = t.ArchivedAt
+	}
+	if t.UID != "" {
+		uid = t.UID
+	}
+	if t.SeriesID != 0 {
+		seriesID = t.SeriesID
+	}
+	// UIDs are unique: the restored todo takes its UID back from any todo
+	// that has been given it since.
+	if t.UID != "" {
+		if _, err := tx.Exec("UPDATE todos SET uid = NULL WHERE uid = ? AND id != ?", t.UID, id); err != nil {
+			return err
+		}
+	}
+	_, err := tx.Exec(`INSERT INTO todos (id, text, body, date, done, created_at, sort_order, schedule_id, schedule_date, date_precision, priority, project_id, start_time, duration, deleted_at, completed_at, archived_at, uid, repeat, series_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, (SELECT id FROM schedules WHERE id = ?), ?, ?, ?, (SELECT id FROM projects WHERE id = ?), ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET text = excluded.text, body = excluded.body, date = excluded.date,
+			done = excluded.done, created_at = excluded.created_at, sort_order = excluded.sort_order,
+			schedule_id = excluded.schedule_id, schedule_date = excluded.schedule_date,
+			date_precision = excluded.date_precision, priority = excluded.priority,
+			project_id = excluded.project_id, start_time = excluded.start_time, duration = excluded.duration,
+			deleted_at = excluded.deleted_at, completed_at = excluded.completed_at,
+			archived_at = excluded.archived_at, uid = excluded.uid,
+			repeat = excluded.repeat, series_id = excluded.series_id`,
+		id, t.Text, t.Body, date, done, t.CreatedAt, t.SortOrder, t.ScheduleID, scheduleDate,
+		t.DatePrecision, t.Priority, t.ProjectID, t.StartTime, t.Duration, deletedAt, completedAt, archivedAt, uid,
+		t.Repeat, seriesID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM todo_tags WHERE todo_id = ?", id); err != nil {
+		return err
+	}
+	for _, name := range t.Tags {
+		if err := insertTodoTag(tx, id, name); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM todo_tags)"); err != nil {
+		return err
+	}
+
+	keep := make([]string, 0, len(t.Reminders))
+	for _, off := range t.Reminders {
+		keep = append(keep, strconv.Itoa(off))
+		if _, err := tx.Exec("INSERT OR IGNORE INTO reminders (todo_id, offset_minutes) VALUES (?, ?)", id, off); err != nil {
+			return err
+		}
+	}
+	query := "DELETE FROM reminders WHERE todo_id = ?"
+	if len(keep) > 0 {
+		query += " AND offset_minutes NOT IN (" + strings.Join(keep, ",") + ")"
+	}
+	_, err = tx.Exec(query, id)
+	return err
+}
+
+// scheduleState is the part of a schedule the journal records: whether it
+// is paused, its exceptions and the todos it moved to the trash.
+type scheduleState struct {
+	Paused     bool     `json:"paused"`
+	Exceptions []string `json:"exceptions,omitempty"`
+	Trashed    []int    `json:"trashed,omitempty"`
+}
+
+// scheduleSnapshot returns the journal state of the schedule with the
+// given ID, or nil if it does not exist.
+func (s *SQLiteStore) scheduleSnapshot(id int) (*scheduleState, error) {
+	var st scheduleState
+	if err := s.db.QueryRow("SELECT paused FROM schedules WHERE id = ?", id).Scan(&st.Paused); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read schedule: %w", err)
+	}
+	rows, err := s.db.Query("SELECT date FROM schedule_exceptions WHERE schedule_id = ? ORDER BY date", id)
+	if err != nil {
+		return nil, fmt.Errorf("read schedule exceptions: %w", err)
+	}
+	for rows.Next() {
+		var d string
+		if err := rows.Scan(&d); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("read schedule exceptions: %w", err)
+		}
+		st.Exceptions = append(st.Exceptions, d)
+	}
+	rows.Close()
+	rows, err = s.db.Query("SELECT todo_id FROM schedule_trash WHERE schedule_id = ? ORDER BY todo_id", id)
+	if err != nil {
+		return nil, fmt.Errorf("read schedule trash: %w", err)
+	}
+	if st.Trashed, err = scanIDs(rows); err != nil {
+		return nil, fmt.Errorf("read schedule trash: %w", err)
+	}
+	return &st, nil
+}
+
+// recordSchedule journals the change made to the schedule with the given
+// ID since before was taken.
+func (s *SQLiteStore) recordSchedule(label string, id int, before *scheduleState) error {
+	after, err := s.scheduleSnapshot(id)
+	if err != nil {
+		return err
+	}
+	s.appendJournal(label, 0, id, encodeState(before), encodeState(after))
+	return nil
+}
+
+// restoreSchedule writes a journal scheduleState back. Schedules deleted
+// in the meantime, and trashed todos purged since, are left alone.
+func restoreSchedule(tx *sql.Tx, id int, snap sql.NullString) error {
+	if !snap.Valid {
+		return nil
+	}
+	var st scheduleState
+	if err := json.Unmarshal([]byte(snap.String), &st); err != nil {
+		return err
+	}
+	res, err := tx.Exec("UPDATE schedules SET paused = ? WHERE id = ?", st.Paused, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM schedule_exceptions WHERE schedule_id = ?", id); err != nil {
+		return err
+	}
+	for _, d := range st.Exceptions {
+		if _, err := tx.Exec("INSERT INTO schedule_exceptions (schedule_id, date) VALUES (?, ?)", id, d); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM schedule_trash WHERE schedule_id = ?", id); err != nil {
+		return err
+	}
+	for _, todoID := range st.Trashed {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO schedule_trash (todo_id, schedule_id) SELECT id, ? FROM todos WHERE id = ?", id, todoID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeState returns the JSON form of a schedule snapshot, or nil for a
+// schedule that does not exist.
+func encodeState(st *scheduleState) any {
+	if st == nil {
+		return nil
+	}
+	data, err := json.Marshal(st)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
+
+// encodeSnapshot returns the JSON form of a todo snapshot, or nil for a
+// todo that does not exist.
+func encodeSnapshot(t *Todo) any {
+	if t == nil {
+		return nil
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,48 @@
+package store
+
+import "time"
+
+// overdueCondition selects incomplete todos whose whole date lies before
+// the date bound to its parameter (see Todo.IsOverdue).
+const overdueCondition = `done = 0 AND date IS NOT NULL AND CASE date_precision
+		WHEN 'month' THEN date(date, 'start of month', '+1 month', '-1 day')
+		WHEN 'year' THEN date(date, 'start of year', '+1 year', '-1 day')
+		ELSE date END < ?`
+
+// OverdueTodos returns the incomplete todos outside the trash and the
+// archive whose date lies before today ("YYYY-MM-DD"), oldest first.
+func (s *SQLiteStore) OverdueTodos(today string) []Todo {
+	rows, err := s.db.Query(
+		"SELECT "+todoColumns+" FROM todos WHERE "+overdueCondition+` AND deleted_at IS NULL AND archived_at IS NULL
+		ORDER BY date, start_time, sort_order, id`,
+		today,
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	todos, _ := scanTodos(rows)
+	return todos
+}
+
+// RollOver moves the todo with the given ID to today ("YYYY-MM-DD"),
+// keeping its date precision: a day todo moves to today, a month todo to
+// today's month and a year todo to today's year. Its start time and
+// duration are kept. Completed and floating todos are left alone.
+func (s *SQLiteStore) RollOver(id int, today string) {
+	d, err := time.Parse(dateFormat, today)
+	if err != nil {
+		return
+	}
+	before := s.snapshot(id)
+	s.db.Exec(`UPDATE todos SET date = CASE date_precision
+			WHEN 'month' THEN ?
+			WHEN 'year' THEN ?
+			ELSE ? END
+		WHERE id = ? AND done = 0 AND date IS NOT NULL`,
+		time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC).Format(dateFormat),
+		time.Date(d.Year(), 1, 1, 0, 0, 0, 0, time.UTC).Format(dateFormat),
+		today, id,
+	)
+	s.record("rollover", id, before)
+}
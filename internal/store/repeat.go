@@ -0,0 +1,75 @@
+package store
+
+// SetRepeat sets the repeat rule of the todo with the given ID (see
+// recurring.ParseRepeat); "" stops it repeating. A todo that starts
+// repeating begins a series of its own unless it already belongs to one.
+func (s *SQLiteStore) SetRepeat(id int, repeat string) {
+	before := s.snapshot(id)
+	s.db.Exec(`UPDATE todos SET repeat = ?,
+		series_id = CASE WHEN ? <> '' THEN COALESCE(series_id, id) ELSE series_id END
+		WHERE id = ?`, repeat, repeat, id)
+	s.record("repeat", id, before)
+}
+
+// AddOccurrence adds the next occurrence of the repeating todo with the
+// given ID on date ("YYYY-MM-DD"). The occurrence is a day todo with the
+// todo's title, body, priority, time, tags, reminders and project, and it
+// takes over the repeat rule, so the todo itself stops repeating and
+// stays behind in the series' history. It returns the new todo, or the
+// zero Todo if the todo does not repeat.
+func (s *SQLiteStore) AddOccurrence(id int, date string) Todo {
+	t := s.Find(id)
+	if t == nil || t.Repeat == "" {
+		return Todo{}
+	}
+	seriesID := t.SeriesID
+	if seriesID == 0 {
+		seriesID = t.ID
+	}
+
+	var next Todo
+	s.Batch("repeat", func() {
+		next = s.Add(t.Text, date, "day", t.Priority)
+		if next.ID == 0 {
+			return
+		}
+		if t.Body != "" {
+			s.UpdateBody(next.ID, t.Body)
+		}
+		if t.StartTime != "" {
+			s.SetTime(next.ID, t.StartTime, t.Duration)
+		}
+		if len(t.Tags) > 0 {
+			s.SetTags(next.ID, t.Tags)
+		}
+		if len(t.Reminders) > 0 {
+			s.SetReminders(next.ID, t.Reminders)
+		}
+		if t.ProjectID != 0 {
+			s.SetProject(next.ID, t.ProjectID)
+		}
+		before := s.snapshot(next.ID)
+		s.db.Exec("UPDATE todos SET repeat = ?, series_id = ? WHERE id = ?", t.Repeat, seriesID, next.ID)
+		s.record("repeat", next.ID, before)
+		s.SetRepeat(id, "")
+	})
+	if fresh := s.Find(next.ID); fresh != nil {
+		next = *fresh
+	}
+	return next
+}
+
+// SeriesTodos returns the occurrences of a repeating todo outside the
+// trash, archived ones included, ordered by date.
+func (s *SQLiteStore) SeriesTodos(seriesID int) []Todo {
+	rows, err := s.db.Query(
+		"SELECT "+todoColumns+" FROM todos WHERE series_id = ? AND deleted_at IS NULL ORDER BY CASE WHEN date IS NULL THEN 1 ELSE 0 END, date, id",
+		seriesID,
+	)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	todos, _ := scanTodos(rows)
+	return todos
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/query"
+)
+
+// SaveSearch stores q under name, replacing the query of an existing
+// saved search with that name. The query has to parse.
+func (s *SQLiteStore) SaveSearch(name, q string) (SavedSearch, error) {
+	name = strings.TrimSpace(name)
+	q = strings.TrimSpace(q)
+	if name == "" {
+		return SavedSearch{}, fmt.Errorf("save search: empty name")
+	}
+	if q == "" {
+		return SavedSearch{}, fmt.Errorf("save search: empty query")
+	}
+	if _, err := query.Parse(q, time.Now()); err != nil {
+		return SavedSearch{}, fmt.Errorf("save search: %w", err)
+	}
+
+	ss := SavedSearch{Name: name, Query: q, CreatedAt: time.Now().Format(dateFormat)}
+	err := s.db.QueryRow(
+		`INSERT INTO saved_searches (name, query, created_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET query = excluded.query
+		RETURNING id, created_at`,
+		ss.Name, ss.Query, ss.CreatedAt,
+	).Scan(&ss.ID, &ss.CreatedAt)
+	if err != nil {
+		return SavedSearch{}, fmt.Errorf("save search: %w", err)
+	}
+	return ss, nil
+}
+
+// ListSavedSearches returns the saved searches in the order they were
+// first saved.
+func (s *SQLiteStore) ListSavedSearches() []SavedSearch {
+	rows, err := s.db.Query("SELECT id, name, query, created_at FROM saved_searches ORDER BY id")
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var searches []SavedSearch
+	for rows.Next() {
+		var ss SavedSearch
+		if err := rows.Scan(&ss.ID, &ss.Name, &ss.Query, &ss.CreatedAt); err != nil {
+			return nil
+		}
+		searches = append(searches, ss)
+	}
+	return searches
+}
+
+// SavedSearchCounts counts the pending and completed todos matching each
+// saved search, in the order of ListSavedSearches. Searches whose query
+// no longer parses (see query.Parse) are left out.
+func (s *SQLiteStore) SavedSearchCounts() []SavedSearchCount {
+	var counts []SavedSearchCount
+	now := time.Now()
+	for _, ss := range s.ListSavedSearches() {
+		q, err := query.Parse(ss.Query, now)
+		if err != nil {
+			continue
+		}
+		c := SavedSearchCount{SearchID: ss.ID, Name: ss.Name}
+		for _, r := range s.QueryTodos(q) {
+			if r.Todo.Done {
+				c.Completed++
+			} else {
+				c.Pending++
+			}
+		}
+		counts = append(counts, c)
+	}
+	return counts
+}
+
+// DeleteSavedSearch removes the saved search with the given ID.
+func (s *SQLiteStore) DeleteSavedSearch(id int) {
+	s.db.Exec("DELETE FROM saved_searches WHERE id = ?", id)
+}
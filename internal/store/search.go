@@ -0,0 +1,171 @@
+package store
+
+import (
+	"database/sql"
+	"strings"
+	"unicode"
+
+	"github.com/antti/todo-calendar/internal/query"
+)
+
+// MatchStart and MatchEnd surround the matched words in the Title and
+// Snippet of a SearchResult.
+const (
+	MatchStart = "\x02"
+	MatchEnd   = "\x03"
+)
+
+// SearchResult is a todo found by SearchTodos. Title is the todo's text
+// with the matched words marked; Snippet is the part of the body around
+// the best match, or "" when the body does not match.
+type SearchResult struct {
+	Todo    Todo
+	Title   string
+	Snippet string
+}
+
+// SearchTodos runs a full-text search over the titles and bodies of the
+// todos outside the trash and the archive. Every word of the query has to
+// match the start of a word in the todo, ignoring case and diacritics.
+// Results are ranked best first, with title matches weighted above body
+// matches.
+func (s *SQLiteStore) SearchTodos(text string) []SearchResult {
+	return s.QueryTodos(query.Query{Text: text})
+}
+
+// QueryTodos returns the todos outside the trash and the archive that
+// match all filters of q and, if q has text, the full-text search for it
+// (see SearchTodos). Text matches are ranked best first; without text,
+// dated todos come first by date, then floating todos by ID.
+func (s *SQLiteStore) QueryTodos(q query.Query) []SearchResult {
+	if q.Empty() {
+		return nil
+	}
+
+	where := []string{"deleted_at IS NULL", "archived_at IS NULL"}
+	var args []any
+	for _, f := range q.Filters {
+		cond, condArgs := filterCondition(f)
+		if f.Negate {
+			// A condition on a NULL column is NULL, which NOT leaves NULL;
+			// "-date:2026" should still find undated todos.
+			cond = "NOT coalesce(" + cond + ", 0)"
+		}
+		where = append(where, cond)
+		args = append(args, condArgs...)
+	}
+
+	var rows *sql.Rows
+	var err error
+	if match := ftsQuery(q.Text); match != "" {
+		rows, err = s.db.Query(`SELECT `+todoColumns+`, m.title, m.snippet FROM todos JOIN (
+				SELECT rowid AS fts_id,
+					highlight(todos_fts, 0, ?, ?) AS title,
+					snippet(todos_fts, 1, ?, ?, '…', 12) AS snippet,
+					bm25(todos_fts, 10.0, 1.0) AS rank
+				FROM todos_fts WHERE todos_fts MATCH ?
+			) m ON m.fts_id = todos.id
+			WHERE `+strings.Join(where, " AND ")+`
+			ORDER BY m.rank, todos.id`,
+			append([]any{MatchStart, MatchEnd, MatchStart, MatchEnd, match}, args...)...,
+		)
+	} else {
+		rows, err = s.db.Query(`SELECT `+todoColumns+`, text, '' FROM todos
+			WHERE `+strings.Join(where, " AND ")+`
+			ORDER BY CASE WHEN date IS NULL THEN 1 ELSE 0 END, date, id`,
+			args...,
+		)
+	}
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var title, snippet sql.NullString
+		t, err := scanTodo(extraColumns{rows, []any{&title, &snippet}})
+		if err != nil {
+			return nil
+		}
+		r := SearchResult{Todo: t, Title: title.String}
+		if strings.Contains(snippet.String, MatchStart) {
+			r.Snippet = strings.Join(strings.Fields(snippet.String), " ")
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// filterCondition translates a query filter, ignoring its negation, into
+// an SQL condition on the todos table and its arguments.
+func filterCondition(f query.Filter) (string, []any) {
+	switch f.Field {
+	case query.FieldPriority:
+		return "priority BETWEEN ? AND ?", []any{f.Min, f.Max}
+	case query.FieldDone:
+		return "done = ?", []any{f.Value == "yes"}
+	case query.FieldDate:
+		switch {
+		case f.From == "" && f.To == "":
+			return "date IS NULL", nil
+		case f.From == "":
+			return "date <= ?", []any{f.To}
+		case f.To == "":
+			return "date >= ?", []any{f.From}
+		default:
+			return "date BETWEEN ? AND ?", []any{f.From, f.To}
+		}
+	case query.FieldPrecision:
+		if f.Value == "none" {
+			return "date IS NULL", nil
+		}
+		return "date_precision = ?", []any{f.Value}
+	case query.FieldHas:
+		switch f.Value {
+		case "body":
+			return "trim(body) <> ''", nil
+		case "time":
+			return "start_time <> ''", nil
+		case "tags":
+			return "EXISTS (SELECT 1 FROM todo_tags WHERE todo_tags.todo_id = todos.id)", nil
+		case "project":
+			return "project_id IS NOT NULL", nil
+		case "reminders":
+			return "EXISTS (SELECT 1 FROM reminders WHERE reminders.todo_id = todos.id)", nil
+		}
+	case query.FieldRecurring:
+		if f.Value == "yes" {
+			return "(schedule_id IS NOT NULL OR series_id IS NOT NULL)", nil
+		}
+		return "schedule_id IS NULL AND series_id IS NULL", nil
+	case query.FieldTag:
+		return "EXISTS (SELECT 1 FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id WHERE todo_tags.todo_id = todos.id AND tags.name = ?)", []any{f.Value}
+	}
+	return "1", nil
+}
+
+// extraColumns scans a todo row followed by further columns into extra.
+type extraColumns struct {
+	rows  *sql.Rows
+	extra []any
+}
+
+func (e extraColumns) Scan(dest ...any) error {
+	return e.rows.Scan(append(dest, e.extra...)...)
+}
+
+// ftsQuery turns free text into an FTS5 query matching todos that contain
+// a word starting with each of its words. Words are quoted so FTS5
+// operators in the text are searched for literally. It returns "" when
+// the text has no words.
+func ftsQuery(text string) string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		if strings.IndexFunc(word, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,7 +18,12 @@ var _ TodoStore = (*SQLiteStore)(nil)
 
 // SQLiteStore implements TodoStore backed by a SQLite database.
 type SQLiteStore struct {
-	db *sql.DB
+	db   *sql.DB
+	path string // database file, next to which migration backups are kept
+
+	// batchLabel and batchOp track the operation recorded by Batch.
+	batchLabel string
+	batchOp    int
 }
 
 // NewSQLiteStore opens (or creates) a SQLite database at dbPath and returns
@@ -26,6 +33,21 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 		return nil, fmt.Errorf("create db directory: %w", err)
 	}
 
+	db, err := openDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SQLiteStore{db: db, path: dbPath}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate database: %w", err)
+	}
+	return s, nil
+}
+
+// openDB opens the SQLite database at dbPath.
+func openDB(dbPath string) (*sql.DB, error) {
 	dsn := fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(ON)", dbPath)
 	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
@@ -41,23 +63,46 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 		db.Close()
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
+	return db, nil
+}
 
-	s := &SQLiteStore{db: db}
-	if err := s.migrate(); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("migrate database: %w", err)
+// schemaVersion is the PRAGMA user_version set by the last migration.
+const schemaVersion = 23
+
+// migrate brings the schema up to schemaVersion. An existing database is
+// backed up first (see backupBeforeMigrate).
+func (s *SQLiteStore) migrate() error {
+	version, err := s.schemaVersion()
+	if err != nil {
+		return err
 	}
-	return s, nil
+	if version > 0 && version < schemaVersion {
+		if err := s.backupBeforeMigrate(version); err != nil {
+			return fmt.Errorf("backup before migration: %w", err)
+		}
+	}
+	return s.migrateTo(schemaVersion)
 }
 
-// migrate applies schema migrations using PRAGMA user_version for tracking.
-func (s *SQLiteStore) migrate() error {
+// schemaVersion reads the schema version from PRAGMA user_version.
+func (s *SQLiteStore) schemaVersion() (int, error) {
 	var version int
 	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
-		return fmt.Errorf("read user_version: %w", err)
+		return 0, fmt.Errorf("read user_version: %w", err)
 	}
+	return version, nil
+}
 
-	if version < 1 {
+// migrateTo applies the schema migrations up to and including target,
+// using PRAGMA user_version for tracking.
+func (s *SQLiteStore) migrateTo(target int) error {
+	version, err := s.schemaVersion()
+	if err != nil {
+		return err
+	}
+	step := func(n int) bool { return version < n && n <= target }
+
+	if step(1) {
 		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS todos (
 			id         INTEGER PRIMARY KEY AUTOINCREMENT,
 			text       TEXT    NOT NULL,
@@ -80,7 +125,7 @@ func (s *SQLiteStore) migrate() error {
 		}
 	}
 
-	if version < 2 {
+	if step(2) {
 		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS templates (
 			id         INTEGER PRIMARY KEY AUTOINCREMENT,
 			name       TEXT    NOT NULL UNIQUE,
@@ -94,7 +139,7 @@ func (s *SQLiteStore) migrate() error {
 		}
 	}
 
-	if version < 3 {
+	if step(3) {
 		for _, t := range defaultTemplates() {
 			s.db.Exec(
 				"INSERT OR IGNORE INTO templates (name, content, created_at) VALUES (?, ?, ?)",
@@ -106,7 +151,7 @@ func (s *SQLiteStore) migrate() error {
 		}
 	}
 
-	if version < 4 {
+	if step(4) {
 		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schedules (
 			id                   INTEGER PRIMARY KEY AUTOINCREMENT,
 			template_id          INTEGER NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
@@ -125,7 +170,7 @@ func (s *SQLiteStore) migrate() error {
 		}
 	}
 
-	if version < 5 {
+	if step(5) {
 		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN schedule_id INTEGER REFERENCES schedules(id) ON DELETE SET NULL`); err != nil {
 			return fmt.Errorf("add schedule_id column: %w", err)
 		}
@@ -140,7 +185,7 @@ func (s *SQLiteStore) migrate() error {
 		}
 	}
 
-	if version < 6 {
+	if step(6) {
 		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN date_precision TEXT NOT NULL DEFAULT 'day'`); err != nil {
 			return fmt.Errorf("add date_precision column: %w", err)
 		}
@@ -153,7 +198,7 @@ func (s *SQLiteStore) migrate() error {
 		}
 	}
 
-	if version < 7 {
+	if step(7) {
 		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN priority INTEGER NOT NULL DEFAULT 0`); err != nil {
 			return fmt.Errorf("add priority column: %w", err)
 		}
@@ -162,6 +207,311 @@ func (s *SQLiteStore) migrate() error {
 		}
 	}
 
+	if step(8) {
+		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS tags (
+			id   INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT    NOT NULL UNIQUE
+		)`); err != nil {
+			return fmt.Errorf("create tags table: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS todo_tags (
+			todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+			tag_id  INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			PRIMARY KEY (todo_id, tag_id)
+		)`); err != nil {
+			return fmt.Errorf("create todo_tags table: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_todo_tags_tag ON todo_tags(tag_id)`); err != nil {
+			return fmt.Errorf("create todo_tags tag index: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 8`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(9) {
+		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS projects (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			name       TEXT    NOT NULL UNIQUE,
+			created_at TEXT    NOT NULL
+		)`); err != nil {
+			return fmt.Errorf("create projects table: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN project_id INTEGER REFERENCES projects(id) ON DELETE SET NULL`); err != nil {
+			return fmt.Errorf("add project_id column: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_todos_project ON todos(project_id)`); err != nil {
+			return fmt.Errorf("create project index: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 9`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(10) {
+		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN start_time TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add start_time column: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN duration INTEGER NOT NULL DEFAULT 0`); err != nil {
+			return fmt.Errorf("add duration column: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 10`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(11) {
+		// fired_for holds the due time ("YYYY-MM-DD HH:MM") the reminder last
+		// fired for, so rescheduling a todo re-arms its reminders.
+		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS reminders (
+			id             INTEGER PRIMARY KEY AUTOINCREMENT,
+			todo_id        INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+			offset_minutes INTEGER NOT NULL,
+			fired_for      TEXT    NOT NULL DEFAULT '',
+			UNIQUE(todo_id, offset_minutes)
+		)`); err != nil {
+			return fmt.Errorf("create reminders table: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 11`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(12) {
+		// before and after hold JSON todo snapshots (see journal.go).
+		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS journal (
+			id      INTEGER PRIMARY KEY AUTOINCREMENT,
+			op_id   INTEGER NOT NULL,
+			label   TEXT    NOT NULL,
+			todo_id INTEGER NOT NULL,
+			before  TEXT,
+			after   TEXT,
+			undone  INTEGER NOT NULL DEFAULT 0
+		)`); err != nil {
+			return fmt.Errorf("create journal table: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_journal_op ON journal(op_id)`); err != nil {
+			return fmt.Errorf("create journal index: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 12`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(13) {
+		// deleted_at is NULL for live todos and the time a todo was moved to
+		// the trash otherwise.
+		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN deleted_at TEXT`); err != nil {
+			return fmt.Errorf("add deleted_at column: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_todos_deleted ON todos(deleted_at)`); err != nil {
+			return fmt.Errorf("create deleted_at index: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 13`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(14) {
+		// completed_at is set when a todo is completed. archived_at is set
+		// when a completed todo is moved out of the main views.
+		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN completed_at TEXT`); err != nil {
+			return fmt.Errorf("add completed_at column: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN archived_at TEXT`); err != nil {
+			return fmt.Errorf("add archived_at column: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_todos_archived ON todos(archived_at)`); err != nil {
+			return fmt.Errorf("create archived_at index: %w", err)
+		}
+		// Todos completed before completion times were recorded count as
+		// completed on the day they were created. Trashed ones are left out.
+		if _, err := s.db.Exec(`UPDATE todos SET completed_at = created_at || ' 00:00:00'
+			WHERE done = 1 AND completed_at IS NULL AND deleted_at IS NULL`); err != nil {
+			return fmt.Errorf("backfill completed_at: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 14`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(15) {
+		// uid holds the iCalendar UID of imported todos so re-importing
+		// the same file updates them instead of adding duplicates.
+		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN uid TEXT`); err != nil {
+			return fmt.Errorf("add uid column: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_todos_uid ON todos(uid) WHERE uid IS NOT NULL`); err != nil {
+			return fmt.Errorf("create uid index: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 15`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(16) {
+		// todos_fts is a full-text index over todo titles and bodies. It
+		// stores no copy of the text (content='todos'); the triggers keep
+		// it in sync with the todos table.
+		if _, err := s.db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS todos_fts USING fts5(
+			text, body,
+			content='todos', content_rowid='id',
+			tokenize='unicode61 remove_diacritics 2'
+		)`); err != nil {
+			return fmt.Errorf("create todos_fts table: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE TRIGGER IF NOT EXISTS todos_fts_insert AFTER INSERT ON todos BEGIN
+			INSERT INTO todos_fts (rowid, text, body) VALUES (new.id, new.text, new.body);
+		END`); err != nil {
+			return fmt.Errorf("create todos_fts insert trigger: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE TRIGGER IF NOT EXISTS todos_fts_delete AFTER DELETE ON todos BEGIN
+			INSERT INTO todos_fts (todos_fts, rowid, text, body) VALUES ('delete', old.id, old.text, old.body);
+		END`); err != nil {
+			return fmt.Errorf("create todos_fts delete trigger: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE TRIGGER IF NOT EXISTS todos_fts_update AFTER UPDATE OF text, body ON todos BEGIN
+			INSERT INTO todos_fts (todos_fts, rowid, text, body) VALUES ('delete', old.id, old.text, old.body);
+			INSERT INTO todos_fts (rowid, text, body) VALUES (new.id, new.text, new.body);
+		END`); err != nil {
+			return fmt.Errorf("create todos_fts update trigger: %w", err)
+		}
+		if _, err := s.db.Exec(`INSERT INTO todos_fts (todos_fts) VALUES ('rebuild')`); err != nil {
+			return fmt.Errorf("build todos_fts index: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 16`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(17) {
+		// query holds the search query language text (see package query).
+		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS saved_searches (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			name       TEXT    NOT NULL UNIQUE COLLATE NOCASE,
+			query      TEXT    NOT NULL,
+			created_at TEXT    NOT NULL
+		)`); err != nil {
+			return fmt.Errorf("create saved_searches table: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 17`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(18) {
+		// repeat is the rule of a repeating todo (see recurring.ParseRepeat);
+		// series_id links the occurrences of a repeating todo to the first.
+		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN repeat TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add repeat column: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE todos ADD COLUMN series_id INTEGER`); err != nil {
+			return fmt.Errorf("add series_id column: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_todos_series ON todos(series_id)`); err != nil {
+			return fmt.Errorf("create series index: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 18`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(19) {
+		// holiday_policy decides what a schedule does with dates on
+		// weekends and holidays (see recurring.ShiftDate); '' creates the
+		// todo anyway.
+		if _, err := s.db.Exec(`ALTER TABLE schedules ADD COLUMN holiday_policy TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add holiday_policy column: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 19`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(20) {
+		// lookahead_days is how many days ahead a schedule creates todos;
+		// catch_up decides what happens to occurrences that passed while
+		// the app was not running (see recurring.AutoCreateForDate), and
+		// generated_through is the last day todos were created for.
+		if _, err := s.db.Exec(`ALTER TABLE schedules ADD COLUMN lookahead_days INTEGER NOT NULL DEFAULT 7`); err != nil {
+			return fmt.Errorf("add lookahead_days column: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE schedules ADD COLUMN catch_up TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add catch_up column: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE schedules ADD COLUMN generated_through TEXT`); err != nil {
+			return fmt.Errorf("add generated_through column: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 20`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(21) {
+		// start_date, end_date and max_occurrences bound the occurrences of
+		// a schedule, paused ones create no todos, and schedule_exceptions
+		// lists single occurrences that are skipped.
+		if _, err := s.db.Exec(`ALTER TABLE schedules ADD COLUMN start_date TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add start_date column: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE schedules ADD COLUMN end_date TEXT NOT NULL DEFAULT ''`); err != nil {
+			return fmt.Errorf("add end_date column: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE schedules ADD COLUMN max_occurrences INTEGER NOT NULL DEFAULT 0`); err != nil {
+			return fmt.Errorf("add max_occurrences column: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE schedules ADD COLUMN paused INTEGER NOT NULL DEFAULT 0`); err != nil {
+			return fmt.Errorf("add paused column: %w", err)
+		}
+		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schedule_exceptions (
+			schedule_id INTEGER NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+			date        TEXT    NOT NULL,
+			PRIMARY KEY (schedule_id, date)
+		)`); err != nil {
+			return fmt.Errorf("create schedule_exceptions table: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 21`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(22) {
+		// meta holds settings of the database itself: database_id is a
+		// random identity that tells its exported iCalendar UIDs apart
+		// from those of other databases (see DatabaseID).
+		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS meta (
+			key   TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)`); err != nil {
+			return fmt.Errorf("create meta table: %w", err)
+		}
+		if _, err := s.db.Exec(`INSERT OR IGNORE INTO meta (key, value) VALUES ('database_id', lower(hex(randomblob(8))))`); err != nil {
+			return fmt.Errorf("set database id: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 22`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
+	if step(23) {
+		// schedule_trash lists the todos a schedule's pause or skipped
+		// occurrences moved to the trash, so resuming or restoring the
+		// occurrence brings back just those. Journal entries with a
+		// schedule_id record schedule changes (see journal.go).
+		if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schedule_trash (
+			todo_id     INTEGER PRIMARY KEY REFERENCES todos(id) ON DELETE CASCADE,
+			schedule_id INTEGER NOT NULL REFERENCES schedules(id) ON DELETE CASCADE
+		)`); err != nil {
+			return fmt.Errorf("create schedule_trash table: %w", err)
+		}
+		if _, err := s.db.Exec(`ALTER TABLE journal ADD COLUMN schedule_id INTEGER`); err != nil {
+			return fmt.Errorf("add journal schedule_id column: %w", err)
+		}
+		if _, err := s.db.Exec(`PRAGMA user_version = 23`); err != nil {
+			return fmt.Errorf("set user_version: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -171,7 +521,11 @@ func (s *SQLiteStore) Close() error {
 }
 
 // todoColumns is the column list used in SELECT statements.
-const todoColumns = "id, text, body, date, done, created_at, sort_order, schedule_id, schedule_date, date_precision, priority"
+// The last two columns aggregate the todo's tag names and reminder offsets
+// into comma-separated lists.
+const todoColumns = "id, text, body, date, done, created_at, sort_order, schedule_id, schedule_date, date_precision, priority, project_id, start_time, duration, deleted_at, completed_at, archived_at, uid, repeat, series_id, " +
+	"(SELECT group_concat(tags.name, ',') FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id WHERE todo_tags.todo_id = todos.id), " +
+	"(SELECT group_concat(offset_minutes, ',') FROM reminders WHERE reminders.todo_id = todos.id)"
 
 // scanTodo scans a single todo row from the given scanner.
 func scanTodo(scanner interface{ Scan(...any) error }) (Todo, error) {
@@ -180,7 +534,12 @@ func scanTodo(scanner interface{ Scan(...any) error }) (Todo, error) {
 	var done int
 	var scheduleID sql.NullInt64
 	var scheduleDate sql.NullString
-	err := scanner.Scan(&t.ID, &t.Text, &t.Body, &date, &done, &t.CreatedAt, &t.SortOrder, &scheduleID, &scheduleDate, &t.DatePrecision, &t.Priority)
+	var projectID sql.NullInt64
+	var tags sql.NullString
+	var reminders sql.NullString
+	var deletedAt, completedAt, archivedAt, uid sql.NullString
+	var seriesID sql.NullInt64
+	err := scanner.Scan(&t.ID, &t.Text, &t.Body, &date, &done, &t.CreatedAt, &t.SortOrder, &scheduleID, &scheduleDate, &t.DatePrecision, &t.Priority, &projectID, &t.StartTime, &t.Duration, &deletedAt, &completedAt, &archivedAt, &uid, &t.Repeat, &seriesID, &tags, &reminders)
 	if err != nil {
 		return Todo{}, err
 	}
@@ -194,6 +553,36 @@ func scanTodo(scanner interface{ Scan(...any) error }) (Todo, error) {
 	if scheduleDate.Valid {
 		t.ScheduleDate = scheduleDate.String
 	}
+	if projectID.Valid {
+		t.ProjectID = int(projectID.Int64)
+	}
+	if deletedAt.Valid {
+		t.DeletedAt = deletedAt.String
+	}
+	if completedAt.Valid {
+		t.CompletedAt = completedAt.String
+	}
+	if archivedAt.Valid {
+		t.ArchivedAt = archivedAt.String
+	}
+	if uid.Valid {
+		t.UID = uid.String
+	}
+	if seriesID.Valid {
+		t.SeriesID = int(seriesID.Int64)
+	}
+	if tags.Valid && tags.String != "" {
+		t.Tags = strings.Split(tags.String, ",")
+		sort.Strings(t.Tags)
+	}
+	if reminders.Valid && reminders.String != "" {
+		for _, f := range strings.Split(reminders.String, ",") {
+			if n, err := strconv.Atoi(f); err == nil {
+				t.Reminders = append(t.Reminders, n)
+			}
+		}
+		sort.Ints(t.Reminders)
+	}
 	return t, nil
 }
 
@@ -240,6 +629,7 @@ func (s *SQLiteStore) Add(text string, date string, datePrecision string, priori
 	}
 
 	id, _ := result.LastInsertId()
+	s.record("add", int(id), nil)
 	return Todo{
 		ID:            int(id),
 		Text:          text,
@@ -252,19 +642,31 @@ func (s *SQLiteStore) Add(text string, date string, datePrecision string, priori
 	}
 }
 
-// Toggle flips the Done status of the todo with the given ID.
+// Toggle flips the Done status of the todo with the given ID. Completing a
+// todo records when it was completed; reopening it clears that and takes it
+// out of the archive.
 func (s *SQLiteStore) Toggle(id int) {
-	s.db.Exec("UPDATE todos SET done = NOT done WHERE id = ?", id)
+	before := s.snapshot(id)
+	s.db.Exec(`UPDATE todos SET done = NOT done,
+		completed_at = CASE WHEN done = 0 THEN ? ELSE NULL END,
+		archived_at = NULL
+		WHERE id = ?`, time.Now().Format(timestampFormat), id)
+	s.record("toggle", id, before)
 }
 
-// Delete removes the todo with the given ID.
+// Delete moves the todo with the given ID to the trash. Trashed todos are
+// hidden from every query except TrashedTodos until they are restored or
+// purged.
 func (s *SQLiteStore) Delete(id int) {
-	s.db.Exec("DELETE FROM todos WHERE id = ?", id)
+	before := s.snapshot(id)
+	s.db.Exec("UPDATE todos SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", time.Now().Format(timestampFormat), id)
+	s.record("delete", id, before)
 }
 
-// Find returns a pointer to the todo with the given ID, or nil if not found.
+// Find returns a pointer to the todo with the given ID, or nil if not found
+// or in the trash.
 func (s *SQLiteStore) Find(id int) *Todo {
-	row := s.db.QueryRow("SELECT "+todoColumns+" FROM todos WHERE id = ?", id)
+	row := s.db.QueryRow("SELECT "+todoColumns+" FROM todos WHERE id = ? AND deleted_at IS NULL", id)
 	t, err := scanTodo(row)
 	if err != nil {
 		return nil
@@ -284,17 +686,39 @@ func (s *SQLiteStore) Update(id int, text string, date string, datePrecision str
 	if date == "" {
 		datePrecision = ""
 	}
-	s.db.Exec("UPDATE todos SET text = ?, date = ?, date_precision = ?, priority = ? WHERE id = ?", text, dateVal, datePrecision, priority, id)
+	// A time of day only makes sense on a specific day, so moving a todo to
+	// a fuzzy date or making it floating drops its start time and duration.
+	before := s.snapshot(id)
+	s.db.Exec(`UPDATE todos SET text = ?, date = ?, date_precision = ?, priority = ?,
+		start_time = CASE WHEN ? = 'day' THEN start_time ELSE '' END,
+		duration = CASE WHEN ? = 'day' THEN duration ELSE 0 END
+		WHERE id = ?`, text, dateVal, datePrecision, priority, datePrecision, datePrecision, id)
+	s.record("edit", id, before)
+}
+
+// SetTime sets the start time ("HH:MM", or "" for none) and duration in
+// minutes of the todo with the given ID. Only day-precision todos can be
+// timed; the call is ignored for others.
+func (s *SQLiteStore) SetTime(id int, startTime string, duration int) {
+	if startTime == "" {
+		duration = 0
+	}
+	before := s.snapshot(id)
+	s.db.Exec("UPDATE todos SET start_time = ?, duration = ? WHERE id = ? AND date_precision = 'day'", startTime, duration, id)
+	s.record("set time", id, before)
 }
 
 // UpdateBody sets the markdown body of the todo with the given ID.
 func (s *SQLiteStore) UpdateBody(id int, body string) {
+	before := s.snapshot(id)
 	s.db.Exec("UPDATE todos SET body = ? WHERE id = ?", body, id)
+	s.record("edit body", id, before)
 }
 
-// Todos returns all todos ordered by sort_order, then id.
+// Todos returns all todos outside the trash and the archive, ordered by
+// sort_order, then id.
 func (s *SQLiteStore) Todos() []Todo {
-	rows, err := s.db.Query("SELECT " + todoColumns + " FROM todos ORDER BY sort_order, id")
+	rows, err := s.db.Query("SELECT " + todoColumns + " FROM todos WHERE deleted_at IS NULL AND archived_at IS NULL ORDER BY sort_order, id")
 	if err != nil {
 		return nil
 	}
@@ -304,14 +728,15 @@ func (s *SQLiteStore) Todos() []Todo {
 }
 
 // TodosForMonth returns day-precision todos whose date falls in the given year and month,
-// sorted by sort_order, date, then id. Excludes fuzzy-date (month/year precision) todos.
+// sorted by date, start time (untimed first), sort_order, then id. Excludes fuzzy-date
+// (month/year precision) todos.
 func (s *SQLiteStore) TodosForMonth(year int, month time.Month) []Todo {
 	start := fmt.Sprintf("%04d-%02d-01", year, month)
 	// Last day: go to first of next month, subtract one day.
 	end := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Format(dateFormat)
 
 	rows, err := s.db.Query(
-		"SELECT "+todoColumns+" FROM todos WHERE date >= ? AND date <= ? AND date_precision = 'day' ORDER BY sort_order, date, id",
+		"SELECT "+todoColumns+" FROM todos WHERE date >= ? AND date <= ? AND date_precision = 'day' AND deleted_at IS NULL AND archived_at IS NULL ORDER BY date, start_time, sort_order, id",
 		start, end,
 	)
 	if err != nil {
@@ -323,11 +748,11 @@ func (s *SQLiteStore) TodosForMonth(year int, month time.Month) []Todo {
 }
 
 // TodosForDateRange returns day-precision todos whose date falls within [startDate, endDate] inclusive,
-// sorted by sort_order, date, then id. Parameters are ISO date strings ("YYYY-MM-DD").
+// sorted by date, start time (untimed first), sort_order, then id. Parameters are ISO date strings ("YYYY-MM-DD").
 // Excludes fuzzy-date (month/year precision) todos.
 func (s *SQLiteStore) TodosForDateRange(startDate, endDate string) []Todo {
 	rows, err := s.db.Query(
-		"SELECT "+todoColumns+" FROM todos WHERE date >= ? AND date <= ? AND date_precision = 'day' ORDER BY sort_order, date, id",
+		"SELECT "+todoColumns+" FROM todos WHERE date >= ? AND date <= ? AND date_precision = 'day' AND deleted_at IS NULL AND archived_at IS NULL ORDER BY date, start_time, sort_order, id",
 		startDate, endDate,
 	)
 	if err != nil {
@@ -343,7 +768,7 @@ func (s *SQLiteStore) TodosForDateRange(startDate, endDate string) []Todo {
 func (s *SQLiteStore) MonthTodos(year int, month time.Month) []Todo {
 	ym := fmt.Sprintf("%04d-%02d", year, month)
 	rows, err := s.db.Query(
-		"SELECT "+todoColumns+" FROM todos WHERE date_precision = 'month' AND substr(date, 1, 7) = ? ORDER BY sort_order, id",
+		"SELECT "+todoColumns+" FROM todos WHERE date_precision = 'month' AND substr(date, 1, 7) = ? AND deleted_at IS NULL AND archived_at IS NULL ORDER BY sort_order, id",
 		ym,
 	)
 	if err != nil {
@@ -359,7 +784,7 @@ func (s *SQLiteStore) MonthTodos(year int, month time.Month) []Todo {
 func (s *SQLiteStore) YearTodos(year int) []Todo {
 	y := fmt.Sprintf("%04d", year)
 	rows, err := s.db.Query(
-		"SELECT "+todoColumns+" FROM todos WHERE date_precision = 'year' AND substr(date, 1, 4) = ? ORDER BY sort_order, id",
+		"SELECT "+todoColumns+" FROM todos WHERE date_precision = 'year' AND substr(date, 1, 4) = ? AND deleted_at IS NULL AND archived_at IS NULL ORDER BY sort_order, id",
 		y,
 	)
 	if err != nil {
@@ -372,7 +797,7 @@ func (s *SQLiteStore) YearTodos(year int) []Todo {
 
 // FloatingTodos returns todos with no date, sorted by sort_order then id.
 func (s *SQLiteStore) FloatingTodos() []Todo {
-	rows, err := s.db.Query("SELECT " + todoColumns + " FROM todos WHERE date IS NULL ORDER BY sort_order, id")
+	rows, err := s.db.Query("SELECT " + todoColumns + " FROM todos WHERE date IS NULL AND deleted_at IS NULL AND archived_at IS NULL ORDER BY sort_order, id")
 	if err != nil {
 		return nil
 	}
@@ -388,7 +813,7 @@ func (s *SQLiteStore) IncompleteTodosPerDay(year int, month time.Month) map[int]
 	end := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Format(dateFormat)
 
 	rows, err := s.db.Query(
-		"SELECT CAST(substr(date, 9, 2) AS INTEGER) AS day, COUNT(*) FROM todos WHERE done = 0 AND date >= ? AND date <= ? AND date_precision = 'day' GROUP BY day",
+		"SELECT CAST(substr(date, 9, 2) AS INTEGER) AS day, COUNT(*) FROM todos WHERE done = 0 AND date >= ? AND date <= ? AND date_precision = 'day' AND deleted_at IS NULL AND archived_at IS NULL GROUP BY day",
 		start, end,
 	)
 	if err != nil {
@@ -414,7 +839,7 @@ func (s *SQLiteStore) TotalTodosPerDay(year int, month time.Month) map[int]int {
 	end := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Format(dateFormat)
 
 	rows, err := s.db.Query(
-		"SELECT CAST(substr(date, 9, 2) AS INTEGER) AS day, COUNT(*) FROM todos WHERE date >= ? AND date <= ? AND date_precision = 'day' GROUP BY day",
+		"SELECT CAST(substr(date, 9, 2) AS INTEGER) AS day, COUNT(*) FROM todos WHERE date >= ? AND date <= ? AND date_precision = 'day' AND deleted_at IS NULL AND archived_at IS NULL GROUP BY day",
 		start, end,
 	)
 	if err != nil {
@@ -440,7 +865,7 @@ func (s *SQLiteStore) TodoCountsByMonth() []MonthCount {
 		       SUM(CASE WHEN done = 0 THEN 1 ELSE 0 END) AS pending,
 		       SUM(CASE WHEN done = 1 THEN 1 ELSE 0 END) AS completed
 		FROM todos
-		WHERE date IS NOT NULL AND date_precision = 'day'
+		WHERE date IS NOT NULL AND date_precision = 'day' AND deleted_at IS NULL AND archived_at IS NULL
 		GROUP BY ym
 		ORDER BY ym
 	`)
@@ -473,7 +898,7 @@ func (s *SQLiteStore) FloatingTodoCounts() FloatingCount {
 	s.db.QueryRow(`
 		SELECT COALESCE(SUM(CASE WHEN done = 0 THEN 1 ELSE 0 END), 0),
 		       COALESCE(SUM(CASE WHEN done = 1 THEN 1 ELSE 0 END), 0)
-		FROM todos WHERE date IS NULL
+		FROM todos WHERE date IS NULL AND deleted_at IS NULL AND archived_at IS NULL
 	`).Scan(&fc.Pending, &fc.Completed)
 	return fc
 }
@@ -491,6 +916,7 @@ func (s *SQLiteStore) HighestPriorityPerDay(year int, month time.Month) map[int]
 		 WHERE done = 0
 		   AND date >= ? AND date <= ?
 		   AND date_precision = 'day'
+		   AND deleted_at IS NULL AND archived_at IS NULL
 		   AND priority BETWEEN 1 AND 4
 		 GROUP BY day`,
 		start, end,
@@ -512,6 +938,12 @@ func (s *SQLiteStore) HighestPriorityPerDay(year int, month time.Month) map[int]
 
 // SwapOrder swaps the sort_order values of two todos in a transaction.
 func (s *SQLiteStore) SwapOrder(id1, id2 int) {
+	s.Batch("reorder", func() { s.swapOrder(id1, id2) })
+}
+
+// swapOrder does the work of SwapOrder inside its journal batch.
+func (s *SQLiteStore) swapOrder(id1, id2 int) {
+	before1, before2 := s.snapshot(id1), s.snapshot(id2)
 	tx, err := s.db.Begin()
 	if err != nil {
 		return
@@ -528,20 +960,92 @@ func (s *SQLiteStore) SwapOrder(id1, id2 int) {
 
 	tx.Exec("UPDATE todos SET sort_order = ? WHERE id = ?", order2, id1)
 	tx.Exec("UPDATE todos SET sort_order = ? WHERE id = ?", order1, id2)
-	tx.Commit()
+	if tx.Commit() == nil {
+		s.record("reorder", id1, before1)
+		s.record("reorder", id2, before2)
+	}
 }
 
-// SearchTodos returns todos whose text contains the query (case-insensitive),
-// sorted: dated first by date ascending, then floating by id.
-func (s *SQLiteStore) SearchTodos(query string) []Todo {
-	if query == "" {
-		return nil
+// SetTags replaces the tags of the todo with the given ID. Names are
+// normalized with NormalizeTag; empty and duplicate names are dropped.
+// Tags no longer used by any todo are removed.
+func (s *SQLiteStore) SetTags(id int, tags []string) {
+	before := s.snapshot(id)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM todo_tags WHERE todo_id = ?", id); err != nil {
+		return
 	}
-	pattern := "%" + strings.ReplaceAll(query, "%", "\\%") + "%"
+	for _, name := range NormalizeTags(tags) {
+		if err := insertTodoTag(tx, id, name); err != nil {
+			return
+		}
+	}
+	if _, err := tx.Exec("DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM todo_tags)"); err != nil {
+		return
+	}
+	if tx.Commit() == nil {
+		s.record("edit tags", id, before)
+	}
+}
 
+// AddTag attaches a single tag to the todo with the given ID.
+// Adding a tag the todo already has is a no-op.
+func (s *SQLiteStore) AddTag(id int, tag string) {
+	name := NormalizeTag(tag)
+	if name == "" {
+		return
+	}
+	before := s.snapshot(id)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+	if err := insertTodoTag(tx, id, name); err != nil {
+		return
+	}
+	if tx.Commit() == nil {
+		s.record("edit tags", id, before)
+	}
+}
+
+// RemoveTag detaches a single tag from the todo with the given ID and
+// removes the tag itself once no todo uses it.
+func (s *SQLiteStore) RemoveTag(id int, tag string) {
+	name := NormalizeTag(tag)
+	before := s.snapshot(id)
+	s.db.Exec("DELETE FROM todo_tags WHERE todo_id = ? AND tag_id = (SELECT id FROM tags WHERE name = ?)", id, name)
+	s.db.Exec("DELETE FROM tags WHERE name = ? AND id NOT IN (SELECT tag_id FROM todo_tags)", name)
+	s.record("edit tags", id, before)
+}
+
+// insertTodoTag creates the tag if needed and links it to the todo.
+func insertTodoTag(tx *sql.Tx, todoID int, name string) error {
+	if _, err := tx.Exec("INSERT OR IGNORE INTO tags (name) VALUES (?)", name); err != nil {
+		return err
+	}
+	_, err := tx.Exec(
+		"INSERT OR IGNORE INTO todo_tags (todo_id, tag_id) SELECT ?, id FROM tags WHERE name = ?",
+		todoID, name,
+	)
+	return err
+}
+
+// TodosWithTag returns todos carrying the given tag, sorted: dated first by
+// date ascending, then floating by id.
+func (s *SQLiteStore) TodosWithTag(tag string) []Todo {
+	name := NormalizeTag(tag)
+	if name == "" {
+		return nil
+	}
 	rows, err := s.db.Query(
-		"SELECT "+todoColumns+" FROM todos WHERE text LIKE ? ESCAPE '\\' ORDER BY CASE WHEN date IS NULL THEN 1 ELSE 0 END, date, id",
-		pattern,
+		"SELECT "+todoColumns+" FROM todos WHERE id IN (SELECT todo_tags.todo_id FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id WHERE tags.name = ?) AND deleted_at IS NULL AND archived_at IS NULL ORDER BY CASE WHEN date IS NULL THEN 1 ELSE 0 END, date, id",
+		name,
 	)
 	if err != nil {
 		return nil
@@ -551,6 +1055,201 @@ func (s *SQLiteStore) SearchTodos(query string) []Todo {
 	return todos
 }
 
+// ListTags returns the names of all tags used by todos outside the trash,
+// sorted alphabetically.
+func (s *SQLiteStore) ListTags() []string {
+	rows, err := s.db.Query("SELECT name FROM tags WHERE id IN (SELECT tag_id FROM todo_tags JOIN todos ON todos.id = todo_tags.todo_id WHERE todos.deleted_at IS NULL AND todos.archived_at IS NULL) ORDER BY name")
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err == nil {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// AddProject creates a new project with the given name.
+// Returns an error if the name is empty or not unique.
+func (s *SQLiteStore) AddProject(name string) (Project, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Project{}, fmt.Errorf("add project: empty name")
+	}
+	createdAt := time.Now().Format(dateFormat)
+	result, err := s.db.Exec("INSERT INTO projects (name, created_at) VALUES (?, ?)", name, createdAt)
+	if err != nil {
+		return Project{}, fmt.Errorf("add project: %w", err)
+	}
+	id, _ := result.LastInsertId()
+	return Project{ID: int(id), Name: name, CreatedAt: createdAt}, nil
+}
+
+// ListProjects returns all projects ordered by name.
+func (s *SQLiteStore) ListProjects() []Project {
+	rows, err := s.db.Query("SELECT id, name, created_at FROM projects ORDER BY name COLLATE NOCASE, id")
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var projects []Project
+	for rows.Next() {
+		var p Project
+		if err := rows.Scan(&p.ID, &p.Name, &p.CreatedAt); err != nil {
+			continue
+		}
+		projects = append(projects, p)
+	}
+	return projects
+}
+
+// FindProject returns the project with the given ID, or nil if not found.
+func (s *SQLiteStore) FindProject(id int) *Project {
+	var p Project
+	err := s.db.QueryRow("SELECT id, name, created_at FROM projects WHERE id = ?", id).Scan(&p.ID, &p.Name, &p.CreatedAt)
+	if err != nil {
+		return nil
+	}
+	return &p
+}
+
+// FindProjectByName returns the project with the given name
+// (case-insensitive), or nil if not found.
+func (s *SQLiteStore) FindProjectByName(name string) *Project {
+	var p Project
+	err := s.db.QueryRow(
+		"SELECT id, name, created_at FROM projects WHERE name = ? COLLATE NOCASE", strings.TrimSpace(name),
+	).Scan(&p.ID, &p.Name, &p.CreatedAt)
+	if err != nil {
+		return nil
+	}
+	return &p
+}
+
+// RenameProject changes the name of a project.
+// Returns an error if the name is empty or violates the UNIQUE constraint.
+func (s *SQLiteStore) RenameProject(id int, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("rename project: empty name")
+	}
+	if _, err := s.db.Exec("UPDATE projects SET name = ? WHERE id = ?", name, id); err != nil {
+		return fmt.Errorf("rename project: %w", err)
+	}
+	return nil
+}
+
+// DeleteProject removes a project. Its todos are kept and lose their project.
+func (s *SQLiteStore) DeleteProject(id int) {
+	s.db.Exec("DELETE FROM projects WHERE id = ?", id)
+}
+
+// SetProject assigns the todo with the given ID to a project.
+// projectID 0 removes the todo from its project.
+func (s *SQLiteStore) SetProject(id int, projectID int) {
+	var projectVal any
+	if projectID > 0 {
+		projectVal = projectID
+	}
+	before := s.snapshot(id)
+	s.db.Exec("UPDATE todos SET project_id = ? WHERE id = ?", projectVal, id)
+	s.record("move to project", id, before)
+}
+
+// ProjectCounts returns pending and completed todo counts for every project,
+// ordered by project name. Projects without todos are included with zero counts.
+func (s *SQLiteStore) ProjectCounts() []ProjectCount {
+	rows, err := s.db.Query(`
+		SELECT p.id, p.name,
+		       COALESCE(SUM(CASE WHEN t.done = 0 THEN 1 ELSE 0 END), 0) AS pending,
+		       COALESCE(SUM(CASE WHEN t.done = 1 THEN 1 ELSE 0 END), 0) AS completed
+		FROM projects p
+		LEFT JOIN todos t ON t.project_id = p.id AND t.deleted_at IS NULL AND t.archived_at IS NULL
+		GROUP BY p.id
+		ORDER BY p.name COLLATE NOCASE, p.id
+	`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var result []ProjectCount
+	for rows.Next() {
+		var pc ProjectCount
+		if err := rows.Scan(&pc.ProjectID, &pc.Name, &pc.Pending, &pc.Completed); err != nil {
+			continue
+		}
+		result = append(result, pc)
+	}
+	return result
+}
+
+// SetReminders replaces the reminder offsets (minutes before the todo is
+// due) of the todo with the given ID. Offsets that are kept retain their
+// fired state.
+func (s *SQLiteStore) SetReminders(id int, offsets []int) {
+	before := s.snapshot(id)
+	tx, err := s.db.Begin()
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	keep := make([]string, 0, len(offsets))
+	for _, off := range offsets {
+		if off < 0 {
+			continue
+		}
+		keep = append(keep, strconv.Itoa(off))
+		tx.Exec("INSERT OR IGNORE INTO reminders (todo_id, offset_minutes) VALUES (?, ?)", id, off)
+	}
+	query := "DELETE FROM reminders WHERE todo_id = ?"
+	if len(keep) > 0 {
+		query += " AND offset_minutes NOT IN (" + strings.Join(keep, ",") + ")"
+	}
+	tx.Exec(query, id)
+	if tx.Commit() == nil {
+		s.record("edit reminders", id, before)
+	}
+}
+
+// ListReminders returns the reminders of incomplete day-precision todos,
+// ordered by todo and offset.
+func (s *SQLiteStore) ListReminders() []Reminder {
+	rows, err := s.db.Query(`
+		SELECT r.id, r.todo_id, r.offset_minutes, r.fired_for
+		FROM reminders r
+		JOIN todos t ON t.id = r.todo_id
+		WHERE t.done = 0 AND t.date_precision = 'day' AND t.deleted_at IS NULL
+		ORDER BY r.todo_id, r.offset_minutes
+	`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var result []Reminder
+	for rows.Next() {
+		var r Reminder
+		if err := rows.Scan(&r.ID, &r.TodoID, &r.Offset, &r.FiredFor); err != nil {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// MarkReminderFired records that the reminder fired for the given due time.
+func (s *SQLiteStore) MarkReminderFired(id int, firedFor string) {
+	s.db.Exec("UPDATE reminders SET fired_for = ? WHERE id = ?", firedFor, id)
+}
+
 // EnsureSortOrder assigns sort_order = id * 10 to any todos with sort_order = 0.
 func (s *SQLiteStore) EnsureSortOrder() {
 	s.db.Exec("UPDATE todos SET sort_order = id * 10 WHERE sort_order = 0")
@@ -630,13 +1329,29 @@ func (s *SQLiteStore) Save() error {
 	return nil
 }
 
+// scheduleColumns is the column list used in schedule SELECT statements.
+// The last column aggregates the schedule's exception dates into a
+// comma-separated list.
+const scheduleColumns = "id, template_id, cadence_type, cadence_value, placeholder_defaults, created_at, holiday_policy, lookahead_days, catch_up, generated_through, " +
+	"start_date, end_date, max_occurrences, paused, " +
+	"(SELECT group_concat(date, ',') FROM schedule_exceptions WHERE schedule_exceptions.schedule_id = schedules.id)"
+
 // scanSchedule scans a single schedule row from the given scanner.
 func scanSchedule(scanner interface{ Scan(...any) error }) (Schedule, error) {
 	var sc Schedule
-	err := scanner.Scan(&sc.ID, &sc.TemplateID, &sc.CadenceType, &sc.CadenceValue, &sc.PlaceholderDefaults, &sc.CreatedAt)
+	var generated, exceptions sql.NullString
+	var paused int
+	err := scanner.Scan(&sc.ID, &sc.TemplateID, &sc.CadenceType, &sc.CadenceValue, &sc.PlaceholderDefaults, &sc.CreatedAt, &sc.HolidayPolicy,
+		&sc.LookaheadDays, &sc.CatchUp, &generated, &sc.StartDate, &sc.EndDate, &sc.MaxOccurrences, &paused, &exceptions)
 	if err != nil {
 		return Schedule{}, err
 	}
+	sc.GeneratedThrough = generated.String
+	sc.Paused = paused != 0
+	if exceptions.Valid && exceptions.String != "" {
+		sc.Exceptions = strings.Split(exceptions.String, ",")
+		sort.Strings(sc.Exceptions)
+	}
 	return sc, nil
 }
 
@@ -671,12 +1386,13 @@ func (s *SQLiteStore) AddSchedule(templateID int, cadenceType, cadenceValue, pla
 		CadenceValue:       cadenceValue,
 		PlaceholderDefaults: placeholderDefaults,
 		CreatedAt:          createdAt,
+		LookaheadDays:      7,
 	}, nil
 }
 
 // ListSchedules returns all schedules ordered by ID.
 func (s *SQLiteStore) ListSchedules() []Schedule {
-	rows, err := s.db.Query("SELECT id, template_id, cadence_type, cadence_value, placeholder_defaults, created_at FROM schedules ORDER BY id")
+	rows, err := s.db.Query("SELECT "+scheduleColumns+" FROM schedules ORDER BY id")
 	if err != nil {
 		return nil
 	}
@@ -688,7 +1404,7 @@ func (s *SQLiteStore) ListSchedules() []Schedule {
 // ListSchedulesForTemplate returns schedules for a given template ordered by ID.
 func (s *SQLiteStore) ListSchedulesForTemplate(templateID int) []Schedule {
 	rows, err := s.db.Query(
-		"SELECT id, template_id, cadence_type, cadence_value, placeholder_defaults, created_at FROM schedules WHERE template_id = ? ORDER BY id",
+		"SELECT "+scheduleColumns+" FROM schedules WHERE template_id = ? ORDER BY id",
 		templateID,
 	)
 	if err != nil {
@@ -716,7 +1432,172 @@ func (s *SQLiteStore) UpdateSchedule(id int, cadenceType, cadenceValue, placehol
 	return nil
 }
 
+// SetHolidayPolicy sets what the schedule does with dates on weekends and
+// holidays: "" (create anyway), "skip", "previous" or "next" workday.
+func (s *SQLiteStore) SetHolidayPolicy(id int, policy string) error {
+	_, err := s.db.Exec("UPDATE schedules SET holiday_policy = ? WHERE id = ?", policy, id)
+	if err != nil {
+		return fmt.Errorf("set holiday policy: %w", err)
+	}
+	return nil
+}
+
+// SetScheduleWindow sets how many days ahead the schedule creates todos
+// and what happens to occurrences missed while the app was not running:
+// "" (nothing), "all" (a todo for each) or "summary" (one todo listing
+// them).
+func (s *SQLiteStore) SetScheduleWindow(id, lookaheadDays int, catchUp string) error {
+	_, err := s.db.Exec("UPDATE schedules SET lookahead_days = ?, catch_up = ? WHERE id = ?", lookaheadDays, catchUp, id)
+	if err != nil {
+		return fmt.Errorf("set schedule window: %w", err)
+	}
+	return nil
+}
+
+// SetGeneratedThrough records the last day ("YYYY-MM-DD") the schedule
+// has created todos for.
+func (s *SQLiteStore) SetGeneratedThrough(id int, date string) error {
+	_, err := s.db.Exec("UPDATE schedules SET generated_through = ? WHERE id = ?", date, id)
+	if err != nil {
+		return fmt.Errorf("set generated through: %w", err)
+	}
+	return nil
+}
+
+// SetScheduleRange bounds the occurrences of a schedule: none before
+// startDate or after endDate ("YYYY-MM-DD", "" for no bound), and at most
+// maxOccurrences of them counted from startDate (0 for no limit).
+func (s *SQLiteStore) SetScheduleRange(id int, startDate, endDate string, maxOccurrences int) error {
+	_, err := s.db.Exec(
+		"UPDATE schedules SET start_date = ?, end_date = ?, max_occurrences = ? WHERE id = ?",
+		startDate, endDate, maxOccurrences, id,
+	)
+	if err != nil {
+		return fmt.Errorf("set schedule range: %w", err)
+	}
+	return nil
+}
+
+// SetSchedulePaused pauses or resumes a schedule, as one undoable
+// operation. Paused schedules create no todos: pausing moves the open todos
+// the schedule created for today and later, look-ahead ones included, to
+// the trash, and resuming restores the todos the schedule trashed, except
+// those of skipped occurrences.
+func (s *SQLiteStore) SetSchedulePaused(id int, paused bool) error {
+	label := "resume"
+	if paused {
+		label = "pause"
+	}
+	return s.scheduleOp(label, id, func() error {
+		if _, err := s.db.Exec("UPDATE schedules SET paused = ? WHERE id = ?", paused, id); err != nil {
+			return fmt.Errorf("set schedule paused: %w", err)
+		}
+		if paused {
+			return s.trashScheduled(id, "date >= ?", time.Now().Format(dateFormat))
+		}
+		return s.restoreScheduled(id)
+	})
+}
+
+// SetScheduleException skips the occurrence of a schedule on date
+// ("YYYY-MM-DD"), moving its open todo to the trash, or restores the
+// occurrence when skip is false, along with the todo the skip trashed
+// unless the schedule is paused. Either is one undoable operation.
+func (s *SQLiteStore) SetScheduleException(id int, date string, skip bool) error {
+	label := "unskip"
+	if skip {
+		label = "skip"
+	}
+	return s.scheduleOp(label, id, func() error {
+		if !skip {
+			if _, err := s.db.Exec("DELETE FROM schedule_exceptions WHERE schedule_id = ? AND date = ?", id, date); err != nil {
+				return fmt.Errorf("set schedule exception: %w", err)
+			}
+			return s.restoreScheduled(id)
+		}
+		if _, err := s.db.Exec("INSERT OR IGNORE INTO schedule_exceptions (schedule_id, date) VALUES (?, ?)", id, date); err != nil {
+			return fmt.Errorf("set schedule exception: %w", err)
+		}
+		return s.trashScheduled(id, "schedule_date = ?", date)
+	})
+}
+
+// scheduleOp runs fn, which changes the schedule with the given ID and
+// its todos, as one undoable operation.
+func (s *SQLiteStore) scheduleOp(label string, id int, fn func() error) error {
+	var err error
+	s.Batch(label, func() {
+		var before *scheduleState
+		if before, err = s.scheduleSnapshot(id); err != nil {
+			return
+		}
+		err = fn()
+		if rerr := s.recordSchedule(label, id, before); err == nil {
+			err = rerr
+		}
+	})
+	return err
+}
+
+// trashScheduled moves the open todos of the schedule with the given ID
+// that match cond to the trash, and lists them in schedule_trash for
+// restoreScheduled.
+func (s *SQLiteStore) trashScheduled(id int, cond string, args ...any) error {
+	rows, err := s.db.Query("SELECT id FROM todos WHERE schedule_id = ? AND done = 0 AND deleted_at IS NULL AND "+cond, append([]any{id}, args...)...)
+	if err != nil {
+		return fmt.Errorf("find scheduled todos: %w", err)
+	}
+	ids, err := scanIDs(rows)
+	if err != nil {
+		return fmt.Errorf("find scheduled todos: %w", err)
+	}
+	for _, todoID := range ids {
+		s.Delete(todoID)
+		if _, err := s.db.Exec("INSERT OR IGNORE INTO schedule_trash (todo_id, schedule_id) VALUES (?, ?)", todoID, id); err != nil {
+			return fmt.Errorf("record trashed todo: %w", err)
+		}
+	}
+	return nil
+}
+
+// restoreScheduled restores the todos the schedule with the given ID
+// moved to the trash, unless it is paused. Todos of occurrences that are
+// still skipped stay in the trash.
+func (s *SQLiteStore) restoreScheduled(id int) error {
+	rows, err := s.db.Query(`SELECT t.id FROM schedule_trash st
+		JOIN todos t ON t.id = st.todo_id
+		JOIN schedules sc ON sc.id = st.schedule_id
+		WHERE st.schedule_id = ? AND sc.paused = 0 AND t.deleted_at IS NOT NULL
+		AND t.schedule_date NOT IN (SELECT date FROM schedule_exceptions WHERE schedule_id = ?)`, id, id)
+	if err != nil {
+		return fmt.Errorf("find trashed scheduled todos: %w", err)
+	}
+	ids, err := scanIDs(rows)
+	if err != nil {
+		return fmt.Errorf("find trashed scheduled todos: %w", err)
+	}
+	for _, todoID := range ids {
+		s.Restore(todoID)
+	}
+	return nil
+}
+
+// scanIDs reads a single integer column and closes rows.
+func scanIDs(rows *sql.Rows) ([]int, error) {
+	defer rows.Close()
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // TodoExistsForSchedule checks if a todo already exists for a schedule and date.
+// Trashed todos count, so deleting a generated todo does not bring it back.
 func (s *SQLiteStore) TodoExistsForSchedule(scheduleID int, date string) bool {
 	var exists int
 	err := s.db.QueryRow(
@@ -726,9 +1607,30 @@ func (s *SQLiteStore) TodoExistsForSchedule(scheduleID int, date string) bool {
 	return err == nil
 }
 
+// FindScheduled returns the todo a schedule created for the occurrence on
+// date, or moved to date by its holiday policy, including trashed and
+// archived todos, or nil if there is none.
+func (s *SQLiteStore) FindScheduled(scheduleID int, date string) *Todo {
+	row := s.db.QueryRow(
+		"SELECT "+todoColumns+" FROM todos WHERE schedule_id = ? AND (schedule_date = ? OR date = ?) ORDER BY schedule_date = ? DESC, id LIMIT 1",
+		scheduleID, date, date, date,
+	)
+	t, err := scanTodo(row)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
 // AddScheduledTodo creates a todo linked to a schedule with schedule_date set.
 // Scheduled todos are always day-precision.
 func (s *SQLiteStore) AddScheduledTodo(text, date, body string, scheduleID int) Todo {
+	return s.AddShiftedTodo(text, date, date, body, scheduleID)
+}
+
+// AddShiftedTodo creates a todo for the occurrence of a schedule on
+// scheduleDate that its holiday policy moved to date.
+func (s *SQLiteStore) AddShiftedTodo(text, date, scheduleDate, body string, scheduleID int) Todo {
 	createdAt := time.Now().Format(dateFormat)
 
 	// Compute next sort_order as MAX(sort_order) + 10.
@@ -743,7 +1645,7 @@ func (s *SQLiteStore) AddScheduledTodo(text, date, body string, scheduleID int)
 
 	result, err := s.db.Exec(
 		"INSERT INTO todos (text, body, date, done, created_at, sort_order, schedule_id, schedule_date, date_precision, priority) VALUES (?, ?, ?, 0, ?, ?, ?, ?, 'day', 0)",
-		text, body, dateVal, createdAt, sortOrder, scheduleID, date,
+		text, body, dateVal, createdAt, sortOrder, scheduleID, scheduleDate,
 	)
 	if err != nil {
 		return Todo{}
@@ -759,7 +1661,7 @@ func (s *SQLiteStore) AddScheduledTodo(text, date, body string, scheduleID int)
 		CreatedAt:     createdAt,
 		SortOrder:     sortOrder,
 		ScheduleID:    scheduleID,
-		ScheduleDate:  date,
+		ScheduleDate:  scheduleDate,
 		DatePrecision: "day",
 		Priority:      0,
 	}
@@ -1,10 +1,14 @@
 package store
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/antti/todo-calendar/internal/query"
 	"github.com/antti/todo-calendar/internal/tmpl"
 )
 
@@ -681,3 +685,1193 @@ func TestPriorityHelpers(t *testing.T) {
 		}
 	}
 }
+
+func TestTagsRoundtrip(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	todo := s.Add("Deploy", "2026-03-15", "day", 0)
+	other := s.Add("Groceries", "", "", 0)
+
+	s.SetTags(todo.ID, []string{"Work", "#oncall", "work", " "})
+	found := s.Find(todo.ID)
+	if found == nil {
+		t.Fatal("Find returned nil")
+	}
+	if got := strings.Join(found.Tags, ","); got != "oncall,work" {
+		t.Errorf("Tags after SetTags = %q, want %q", got, "oncall,work")
+	}
+
+	// Untagged todos have no tags.
+	if found := s.Find(other.ID); found == nil || len(found.Tags) != 0 {
+		t.Errorf("untagged todo: got %+v", found)
+	}
+
+	s.AddTag(other.ID, "home")
+	s.AddTag(other.ID, "home")
+	s.AddTag(todo.ID, "home")
+	if got := strings.Join(s.ListTags(), ","); got != "home,oncall,work" {
+		t.Errorf("ListTags = %q", got)
+	}
+
+	withHome := s.TodosWithTag("#HOME")
+	if len(withHome) != 2 || withHome[0].ID != todo.ID || withHome[1].ID != other.ID {
+		t.Errorf("TodosWithTag(home): want dated then floating, got %+v", withHome)
+	}
+
+	// Removing the last use of a tag drops it from ListTags.
+	s.RemoveTag(todo.ID, "oncall")
+	if got := strings.Join(s.ListTags(), ","); got != "home,work" {
+		t.Errorf("ListTags after RemoveTag = %q", got)
+	}
+
+	// SetTags replaces the whole set.
+	s.SetTags(todo.ID, nil)
+	if found := s.Find(todo.ID); len(found.Tags) != 0 {
+		t.Errorf("Tags after clearing = %v", found.Tags)
+	}
+	if got := strings.Join(s.ListTags(), ","); got != "home" {
+		t.Errorf("ListTags after clearing = %q", got)
+	}
+
+	// Deleting a todo removes its tag links.
+	s.Delete(other.ID)
+	if got := s.ListTags(); len(got) != 0 {
+		t.Errorf("ListTags after delete = %v", got)
+	}
+}
+
+func TestTagHelpers(t *testing.T) {
+	if got := NormalizeTag("  #On Call, "); got != "on-call" {
+		t.Errorf("NormalizeTag = %q, want %q", got, "on-call")
+	}
+	if got := strings.Join(ParseTags("work, #Home  oncall,work"), ","); got != "home,oncall,work" {
+		t.Errorf("ParseTags = %q", got)
+	}
+
+	text, tags := SplitTagQuery("fix #Work report #q3 #")
+	if text != "fix report" {
+		t.Errorf("SplitTagQuery text = %q", text)
+	}
+	if strings.Join(tags, ",") != "q3,work" {
+		t.Errorf("SplitTagQuery tags = %v", tags)
+	}
+
+	todo := Todo{Tags: []string{"q3", "work"}}
+	if !todo.HasAllTags([]string{"WORK", "q3"}) || todo.HasAllTags([]string{"work", "home"}) || !todo.HasAllTags(nil) {
+		t.Error("HasAllTags returned unexpected result")
+	}
+}
+
+func TestProjects(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	work, err := s.AddProject("Work")
+	if err != nil {
+		t.Fatalf("AddProject: %v", err)
+	}
+	home, err := s.AddProject("home")
+	if err != nil {
+		t.Fatalf("AddProject: %v", err)
+	}
+	if _, err := s.AddProject("Work"); err == nil {
+		t.Error("AddProject with duplicate name: want error")
+	}
+	if _, err := s.AddProject("  "); err == nil {
+		t.Error("AddProject with empty name: want error")
+	}
+
+	// ListProjects sorts case-insensitively.
+	projects := s.ListProjects()
+	if len(projects) != 2 || projects[0].ID != home.ID || projects[1].ID != work.ID {
+		t.Errorf("ListProjects = %+v", projects)
+	}
+	if p := s.FindProjectByName("WORK"); p == nil || p.ID != work.ID {
+		t.Errorf("FindProjectByName(WORK) = %+v", p)
+	}
+
+	a := s.Add("Report", "2026-03-15", "day", 0)
+	b := s.Add("Review", "", "", 0)
+	c := s.Add("Laundry", "", "", 0)
+	s.SetProject(a.ID, work.ID)
+	s.SetProject(b.ID, work.ID)
+	s.SetProject(c.ID, home.ID)
+	s.Toggle(b.ID)
+
+	if got := s.Find(a.ID).ProjectID; got != work.ID {
+		t.Errorf("ProjectID after SetProject = %d, want %d", got, work.ID)
+	}
+
+	counts := s.ProjectCounts()
+	if len(counts) != 2 {
+		t.Fatalf("ProjectCounts: got %d entries, want 2", len(counts))
+	}
+	if counts[1] != (ProjectCount{ProjectID: work.ID, Name: "Work", Pending: 1, Completed: 1}) {
+		t.Errorf("work counts = %+v", counts[1])
+	}
+
+	if err := s.RenameProject(home.ID, "Household"); err != nil {
+		t.Fatalf("RenameProject: %v", err)
+	}
+	if p := s.FindProject(home.ID); p == nil || p.Name != "Household" {
+		t.Errorf("FindProject after rename = %+v", p)
+	}
+	if err := s.RenameProject(home.ID, "Work"); err == nil {
+		t.Error("RenameProject to an existing name: want error")
+	}
+
+	// Deleting a project keeps its todos.
+	s.DeleteProject(work.ID)
+	found := s.Find(a.ID)
+	if found == nil || found.ProjectID != 0 {
+		t.Errorf("todo after project delete = %+v", found)
+	}
+
+	s.SetProject(c.ID, 0)
+	if got := s.Find(c.ID).ProjectID; got != 0 {
+		t.Errorf("ProjectID after clearing = %d", got)
+	}
+}
+
+func TestTimedTodos(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	untimed := s.Add("Untimed", "2026-10-20", "day", 0)
+	late := s.Add("Late", "2026-10-20", "day", 0)
+	early := s.Add("Early", "2026-10-20", "day", 0)
+	s.SetTime(late.ID, "14:00", 30)
+	s.SetTime(early.ID, "09:15", 0)
+
+	var got []string
+	for _, td := range s.TodosForDateRange("2026-10-20", "2026-10-20") {
+		got = append(got, td.Text)
+	}
+	if strings.Join(got, ",") != "Untimed,Early,Late" {
+		t.Errorf("day order = %v, want untimed first, then chronological", got)
+	}
+
+	f := s.Find(late.ID)
+	if f.StartTime != "14:00" || f.Duration != 30 || f.TimeLabel() != "14:00-14:30" {
+		t.Errorf("late todo time = %q/%d (%q)", f.StartTime, f.Duration, f.TimeLabel())
+	}
+	if s.Find(untimed.ID).HasTime() {
+		t.Error("untimed todo reports a time")
+	}
+
+	// Moving a timed todo to a fuzzy date drops its time.
+	s.Update(late.ID, "Late", "2026-10-01", "month", 0)
+	if f := s.Find(late.ID); f.HasTime() || f.Duration != 0 {
+		t.Errorf("month todo kept its time: %q/%d", f.StartTime, f.Duration)
+	}
+	s.SetTime(late.ID, "10:00", 15)
+	if s.Find(late.ID).HasTime() {
+		t.Error("SetTime applied to a month-precision todo")
+	}
+}
+
+func TestTimeHelpers(t *testing.T) {
+	clocks := map[string]string{"9": "09:00", "9:30": "09:30", "0930": "09:30", "23:59": "23:59", "": ""}
+	for in, want := range clocks {
+		if got, err := ParseClock(in); err != nil || got != want {
+			t.Errorf("ParseClock(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"24:00", "9:60", "ab", "123:4"} {
+		if _, err := ParseClock(bad); err == nil {
+			t.Errorf("ParseClock(%q) accepted", bad)
+		}
+	}
+
+	durations := map[string]int{"": 0, "90": 90, "1h30m": 90, "45m": 45, "2h": 120}
+	for in, want := range durations {
+		if got, err := ParseDuration(in); err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := ParseDuration("30s"); err == nil {
+		t.Error("ParseDuration accepted a sub-minute duration")
+	}
+	for min, want := range map[int]string{0: "", 45: "45m", 120: "2h", 90: "1h30m"} {
+		if got := FormatDuration(min); got != want {
+			t.Errorf("FormatDuration(%d) = %q, want %q", min, got, want)
+		}
+	}
+
+	wrap := Todo{StartTime: "23:30", Duration: 60}
+	if got := wrap.EndTime(); got != "00:30" {
+		t.Errorf("EndTime past midnight = %q, want 00:30", got)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+
+	if _, ok := s.Undo(); ok {
+		t.Fatal("Undo on an empty journal succeeded")
+	}
+
+	var a Todo
+	s.Batch("add", func() {
+		a = s.Add("Dentist", "2026-10-20", "day", 1)
+		s.SetTime(a.ID, "10:00", 30)
+		s.SetTags(a.ID, []string{"health"})
+		s.SetReminders(a.ID, []int{15})
+	})
+	b := s.Add("Groceries", "", "", 0)
+	s.Toggle(b.ID)
+	s.Delete(a.ID)
+
+	if label, ok := s.Undo(); !ok || label != "delete" {
+		t.Fatalf("Undo = %q, %v; want delete", label, ok)
+	}
+	got := s.Find(a.ID)
+	if got == nil || got.StartTime != "10:00" || got.Duration != 30 || !got.HasTag("health") || len(got.Reminders) != 1 {
+		t.Fatalf("deleted todo not fully restored: %+v", got)
+	}
+	if s.Undo(); s.Find(b.ID).Done {
+		t.Error("toggle not undone")
+	}
+
+	// Undo and redo survive reopening the database.
+	s.Close()
+	s, err = NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	defer s.Close()
+
+	if label, _ := s.Redo(); label != "toggle" || !s.Find(b.ID).Done {
+		t.Errorf("Redo = %q, done = %v; want toggle re-applied", label, s.Find(b.ID).Done)
+	}
+
+	s.Undo() // toggle
+	s.Undo() // add Groceries
+	if s.Find(b.ID) != nil {
+		t.Error("add not undone")
+	}
+	if label, _ := s.Undo(); label != "add" || s.Find(a.ID) != nil {
+		t.Errorf("batched add not undone in one step: %q", label)
+	}
+	if len(s.ListTags()) != 0 {
+		t.Errorf("tags left behind: %v", s.ListTags())
+	}
+
+	// A new operation discards the redo history.
+	s.Redo()
+	s.UpdateBody(a.ID, "bring insurance card")
+	if _, ok := s.Redo(); ok {
+		t.Error("Redo after a new operation succeeded")
+	}
+
+	// No-op mutations are not journaled.
+	s.UpdateBody(a.ID, "bring insurance card")
+	if label, _ := s.Undo(); label != "edit body" || s.Find(a.ID).Body != "" {
+		t.Errorf("Undo = %q, body %q; want edit body reverted", label, s.Find(a.ID).Body)
+	}
+}
+
+func TestUndoReorderAndLimit(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	a := s.Add("A", "", "", 0)
+	b := s.Add("B", "", "", 0)
+	s.SwapOrder(a.ID, b.ID)
+	if label, _ := s.Undo(); label != "reorder" {
+		t.Fatalf("Undo = %q, want reorder", label)
+	}
+	if s.Find(a.ID).SortOrder >= s.Find(b.ID).SortOrder {
+		t.Error("reorder not undone")
+	}
+
+	for i := 0; i < journalLimit+10; i++ {
+		s.Toggle(a.ID)
+	}
+	undone := 0
+	for {
+		if _, ok := s.Undo(); !ok {
+			break
+		}
+		undone++
+	}
+	if undone != journalLimit {
+		t.Errorf("undid %d operations, want %d", undone, journalLimit)
+	}
+}
+
+func TestTrash(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	a := s.Add("Dentist", "2026-10-20", "day", 1)
+	s.SetTags(a.ID, []string{"health"})
+	b := s.Add("Groceries", "", "", 0)
+
+	s.Delete(a.ID)
+	if s.Find(a.ID) != nil {
+		t.Error("Find returned a trashed todo")
+	}
+	if n := len(s.TodosForMonth(2026, time.October)); n != 0 {
+		t.Errorf("TodosForMonth returned %d trashed todos", n)
+	}
+	if n := len(s.SearchTodos("dent")); n != 0 {
+		t.Errorf("SearchTodos returned %d trashed todos", n)
+	}
+	if counts := s.IncompleteTodosPerDay(2026, time.October); counts[20] != 0 {
+		t.Errorf("IncompleteTodosPerDay counts trashed todos: %v", counts)
+	}
+	if tags := s.ListTags(); len(tags) != 0 {
+		t.Errorf("ListTags includes tags of trashed todos: %v", tags)
+	}
+
+	trashed := s.TrashedTodos()
+	if len(trashed) != 1 || trashed[0].ID != a.ID || trashed[0].DeletedAt == "" {
+		t.Fatalf("TrashedTodos = %+v", trashed)
+	}
+
+	// Undoing a delete takes the todo out of the trash again.
+	s.Undo()
+	if got := s.Find(a.ID); got == nil || !got.HasTag("health") {
+		t.Fatalf("undone delete not restored: %+v", got)
+	}
+	s.Redo()
+	s.Restore(a.ID)
+	if s.Find(a.ID) == nil || len(s.TrashedTodos()) != 0 {
+		t.Error("Restore did not take the todo out of the trash")
+	}
+
+	// Purge only removes trashed todos.
+	s.Purge(b.ID)
+	if s.Find(b.ID) == nil {
+		t.Error("Purge removed a live todo")
+	}
+	s.Delete(b.ID)
+	if n := s.PurgeTrash(time.Now().Add(-time.Hour)); n != 0 {
+		t.Errorf("PurgeTrash removed %d recently trashed todos", n)
+	}
+	if n := s.PurgeTrash(time.Now().Add(time.Second)); n != 1 {
+		t.Errorf("PurgeTrash removed %d todos, want 1", n)
+	}
+	if len(s.TrashedTodos()) != 0 {
+		t.Error("trash not empty after PurgeTrash")
+	}
+	// The purged todo's history is gone, so it cannot be undone back.
+	for {
+		if _, ok := s.Undo(); !ok {
+			break
+		}
+	}
+	if s.snapshot(b.ID) != nil {
+		t.Error("undo brought back a purged todo")
+	}
+}
+
+func TestArchive(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	a := s.Add("Report", "2026-10-20", "day", 0)
+	b := s.Add("Email", "2026-10-21", "day", 0)
+	open := s.Add("Review", "2026-10-22", "day", 0)
+
+	s.Toggle(a.ID)
+	done := s.Find(a.ID)
+	if done.CompletedAt == "" {
+		t.Fatal("Toggle did not record completed_at")
+	}
+	s.Toggle(a.ID)
+	if got := s.Find(a.ID); got.CompletedAt != "" {
+		t.Errorf("reopening kept completed_at %q", got.CompletedAt)
+	}
+	s.Toggle(a.ID)
+	s.Toggle(b.ID)
+
+	// Open todos cannot be archived.
+	s.Archive(open.ID)
+	if got := s.Find(open.ID); got.ArchivedAt != "" {
+		t.Error("Archive archived an open todo")
+	}
+
+	s.Archive(a.ID)
+	if n := len(s.TodosForMonth(2026, time.October)); n != 2 {
+		t.Errorf("TodosForMonth returned %d todos, want 2", n)
+	}
+	if counts := s.TotalTodosPerDay(2026, time.October); counts[20] != 0 {
+		t.Errorf("TotalTodosPerDay counts archived todos: %v", counts)
+	}
+	if got := s.Find(a.ID); got == nil || got.ArchivedAt == "" {
+		t.Fatalf("Find(archived) = %+v", got)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	archived := s.ArchivedTodos(today, today)
+	if len(archived) != 1 || archived[0].ID != a.ID {
+		t.Fatalf("ArchivedTodos = %+v", archived)
+	}
+	if n := len(s.ArchivedTodos("2000-01-01", "2000-12-31")); n != 0 {
+		t.Errorf("ArchivedTodos outside the range returned %d todos", n)
+	}
+
+	// Archiving is undoable; reopening takes a todo out of the archive.
+	s.Undo()
+	if got := s.Find(a.ID); got.ArchivedAt != "" {
+		t.Error("undo did not unarchive")
+	}
+	s.Redo()
+	s.Toggle(a.ID)
+	if got := s.Find(a.ID); got.ArchivedAt != "" || got.Done {
+		t.Errorf("reopened todo still archived: %+v", got)
+	}
+	s.Toggle(a.ID)
+	s.Archive(a.ID)
+	s.Unarchive(a.ID)
+	if n := len(s.ArchivedTodos(today, today)); n != 0 {
+		t.Errorf("Unarchive left %d archived todos", n)
+	}
+
+	// ArchiveCompleted only archives todos completed before the cutoff.
+	if n := s.ArchiveCompleted(time.Now().AddDate(0, 0, -1)); n != 0 {
+		t.Errorf("ArchiveCompleted(yesterday) = %d, want 0", n)
+	}
+	if n := s.ArchiveCompleted(time.Now().Add(time.Second)); n != 2 {
+		t.Errorf("ArchiveCompleted(now) = %d, want 2", n)
+	}
+	if n := len(s.Todos()); n != 1 {
+		t.Errorf("Todos returned %d todos, want the open one", n)
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	p, _ := s.AddProject("Home")
+	a := s.Add("Dentist", "2026-10-20", "day", 1)
+	s.SetTags(a.ID, []string{"health"})
+	s.SetProject(a.ID, p.ID)
+	s.SetReminders(a.ID, []int{30})
+	s.UpdateBody(a.ID, "Bring the referral.")
+	tpl, _ := s.AddTemplate("Weekly", "Review {{.Week}}")
+	s.AddSchedule(tpl.ID, "weekly", "mon", `{"Week":"1"}`)
+
+	var buf strings.Builder
+	b, err := s.Backup()
+	if err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if err := WriteBackup(&buf, b); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes made after the backup are replaced by the restore.
+	s.Delete(a.ID)
+	s.Add("Later", "", "", 0)
+	s.DeleteTemplate(tpl.ID)
+
+	read, err := ReadBackup(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("ReadBackup: %v", err)
+	}
+	if err := s.RestoreBackup(read); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+
+	todos := s.Todos()
+	if len(todos) != 1 {
+		t.Fatalf("restored %d todos, want 1", len(todos))
+	}
+	got := todos[0]
+	if got.ID != a.ID || got.Text != "Dentist" || got.Priority != 1 || got.Body != "Bring the referral." || got.ProjectID != p.ID {
+		t.Errorf("restored todo = %+v", got)
+	}
+	if len(got.Tags) != 1 || got.Tags[0] != "health" || len(got.Reminders) != 1 || got.Reminders[0] != 30 {
+		t.Errorf("restored tags %v, reminders %v", got.Tags, got.Reminders)
+	}
+	scheds := s.ListSchedulesForTemplate(tpl.ID)
+	if len(scheds) != 1 || scheds[0].PlaceholderDefaults != `{"Week":"1"}` {
+		t.Errorf("restored schedules = %+v", scheds)
+	}
+	if next := s.Add("Next", "", "", 0); next.ID <= a.ID {
+		t.Errorf("new todo reused ID %d", next.ID)
+	}
+
+	for _, doc := range []string{
+		`{"format":"something-else","version":1,"schema":15}`,
+		`{"format":"todo-calendar-backup","version":2,"schema":15}`,
+		`{"format":"todo-calendar-backup","version":1,"schema":99}`,
+	} {
+		if _, err := ReadBackup(strings.NewReader(doc)); err == nil {
+			t.Errorf("ReadBackup(%s) succeeded", doc)
+		}
+	}
+	bad := &Backup{Format: BackupFormat, Version: BackupVersion, Schema: 1, Tables: map[string][]map[string]any{"tags": {}}}
+	if err := s.RestoreBackup(bad); err == nil {
+		t.Error("RestoreBackup accepted a table missing from its schema")
+	}
+	if n := len(s.Todos()); n != 2 {
+		t.Errorf("failed restore changed the store: %d todos", n)
+	}
+}
+
+func TestMigrationBackup(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "test.db")
+
+	// A database left at schema version 1 by an old release.
+	old, err := openDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&SQLiteStore{db: old}).migrateTo(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Exec("INSERT INTO todos (text, date, created_at) VALUES ('Floating', NULL, '2020-01-01')"); err != nil {
+		t.Fatal(err)
+	}
+	old.Close()
+
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "backups", "*.json"))
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], "-schema1.json") {
+		t.Fatalf("migration backups = %v", backups)
+	}
+	f, err := os.Open(backups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b, err := ReadBackup(f)
+	if err != nil {
+		t.Fatalf("ReadBackup: %v", err)
+	}
+	if b.Schema != 1 || len(b.Tables["todos"]) != 1 {
+		t.Fatalf("backup schema %d with %d todos", b.Schema, len(b.Tables["todos"]))
+	}
+
+	// Restoring the old backup migrates it like an upgrade would.
+	s.Add("Newer", "2026-10-20", "day", 0)
+	if err := s.RestoreBackup(b); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	todos := s.Todos()
+	if len(todos) != 1 || todos[0].Text != "Floating" || todos[0].DatePrecision != "" {
+		t.Errorf("restored todos = %+v", todos)
+	}
+	if n := len(s.ListTemplates()); n != 7 {
+		t.Errorf("restored %d templates, want the 7 seeded by migration 3", n)
+	}
+
+	// Reopening an up-to-date database takes no backup.
+	s.Close()
+	s, err = NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if again, _ := filepath.Glob(filepath.Join(dir, "backups", "*.json")); len(again) != 1 {
+		t.Errorf("reopening took another backup: %v", again)
+	}
+}
+
+func TestSearchTodos(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	dentist := s.Add("Dentist", "2026-10-20", "day", 0)
+	s.UpdateBody(dentist.ID, "Bring the referral letter.\nAsk about the bill.")
+	referral := s.Add("Referral for physio", "", "", 0)
+	cafe := s.Add("Café with Anna", "", "", 0)
+
+	ids := func(results []SearchResult) []int {
+		var ids []int
+		for _, r := range results {
+			ids = append(ids, r.Todo.ID)
+		}
+		return ids
+	}
+
+	// Title matches rank above body matches; words match by prefix.
+	results := s.SearchTodos("refer")
+	if got := ids(results); len(got) != 2 || got[0] != referral.ID || got[1] != dentist.ID {
+		t.Fatalf("SearchTodos(refer) = %v", got)
+	}
+	if want := MatchStart + "Referral" + MatchEnd + " for physio"; results[0].Title != want || results[0].Snippet != "" {
+		t.Errorf("title match: Title %q, Snippet %q", results[0].Title, results[0].Snippet)
+	}
+	if !strings.Contains(results[1].Snippet, MatchStart+"referral"+MatchEnd) || strings.Contains(results[1].Snippet, "\n") {
+		t.Errorf("body match snippet = %q", results[1].Snippet)
+	}
+
+	// Every word has to match; case and diacritics are ignored.
+	if got := ids(s.SearchTodos("dentist bill")); len(got) != 1 || got[0] != dentist.ID {
+		t.Errorf("SearchTodos(dentist bill) = %v", got)
+	}
+	if got := ids(s.SearchTodos("dentist physio")); len(got) != 0 {
+		t.Errorf("SearchTodos(dentist physio) = %v", got)
+	}
+	if got := ids(s.SearchTodos("CAFE")); len(got) != 1 || got[0] != cafe.ID {
+		t.Errorf("SearchTodos(CAFE) = %v", got)
+	}
+
+	// FTS5 syntax in the query is searched for literally.
+	for _, q := range []string{`"`, "-", "NOT", "Dentist OR", `bill"*`, "a:b"} {
+		s.SearchTodos(q) // must not fail
+	}
+	if got := ids(s.SearchTodos(`bill" OR "physio`)); len(got) != 0 {
+		t.Errorf("quoted operator matched %v", got)
+	}
+
+	// The index follows edits, undo and deletes.
+	s.Update(cafe.ID, "Lunch with Anna", "", "", 0)
+	if got := ids(s.SearchTodos("cafe")); len(got) != 0 {
+		t.Errorf("old title still found: %v", got)
+	}
+	s.Undo()
+	if got := ids(s.SearchTodos("cafe")); len(got) != 1 {
+		t.Errorf("undone title not found: %v", got)
+	}
+	s.Delete(referral.ID)
+	s.Purge(referral.ID)
+	if got := ids(s.SearchTodos("physio")); len(got) != 0 {
+		t.Errorf("purged todo found: %v", got)
+	}
+
+	// Backups leave the index out; restoring rebuilds it.
+	b, err := s.Backup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for table := range b.Tables {
+		if strings.HasPrefix(table, "todos_fts") {
+			t.Errorf("backup includes %s", table)
+		}
+	}
+	if err := s.RestoreBackup(b); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if got := ids(s.SearchTodos("referral")); len(got) != 1 || got[0] != dentist.ID {
+		t.Errorf("SearchTodos after restore = %v", got)
+	}
+}
+
+func TestQueryTodos(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	report := s.Add("Quarterly report", "2026-11-05", "day", 1)
+	s.UpdateBody(report.ID, "Numbers from finance.")
+	s.SetTags(report.ID, []string{"work"})
+	taxes := s.Add("Taxes", "2026-12-01", "month", 2)
+	s.Toggle(taxes.ID)
+	plan := s.Add("Yearly plan", "2027-01-01", "year", 0)
+	idea := s.Add("Report idea", "", "", 0)
+	tpl, _ := s.AddTemplate("Standup", "Standup")
+	sched, _ := s.AddSchedule(tpl.ID, "daily", "", "{}")
+	standup := s.AddScheduledTodo("Standup", "2026-10-20", "", sched.ID)
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"p:1", []int{report.ID}},
+		{"p:0..1", []int{standup.ID, report.ID, plan.ID, idea.ID}},
+		{"done:yes", []int{taxes.ID}},
+		{"date:2026-11..2026-12", []int{report.ID, taxes.ID}},
+		{"date:none", []int{idea.ID}},
+		{"-date:2026", []int{plan.ID, idea.ID}},
+		{"prec:month", []int{taxes.ID}},
+		{"prec:none", []int{idea.ID}},
+		{"has:body", []int{report.ID}},
+		{"-has:body done:no", []int{standup.ID, plan.ID, idea.ID}},
+		{"has:tags", []int{report.ID}},
+		{"recurring:yes", []int{standup.ID}},
+		{"#work", []int{report.ID}},
+		{"-#work prec:day", []int{standup.ID}},
+		{"report", []int{idea.ID, report.ID}}, // the shorter title ranks first
+		{"report date:none", []int{idea.ID}},
+		{"finance p:1", []int{report.ID}},
+	}
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		q, err := query.Parse(tt.query, now)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		var got []int
+		for _, r := range s.QueryTodos(q) {
+			got = append(got, r.Todo.ID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("QueryTodos(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSavedSearches(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	s.Add("Dentist", "2026-10-20", "day", 1)
+	taxes := s.Add("Taxes", "", "", 1)
+	s.Toggle(taxes.ID)
+	s.Add("Laundry", "", "", 0)
+
+	urgent, err := s.SaveSearch("Urgent", "p:1")
+	if err != nil {
+		t.Fatalf("SaveSearch: %v", err)
+	}
+	if _, err := s.SaveSearch("Floating", "date:none done:no"); err != nil {
+		t.Fatalf("SaveSearch: %v", err)
+	}
+	if _, err := s.SaveSearch("Broken", "p:9"); err == nil {
+		t.Error("SaveSearch with an invalid query succeeded")
+	}
+	if _, err := s.SaveSearch(" ", "p:1"); err == nil {
+		t.Error("SaveSearch with an empty name succeeded")
+	}
+
+	// Saving under an existing name, in any case, replaces its query.
+	again, err := s.SaveSearch("urgent", "p:1..2")
+	if err != nil {
+		t.Fatalf("SaveSearch: %v", err)
+	}
+	if again.ID != urgent.ID {
+		t.Errorf("re-saved search got ID %d, want %d", again.ID, urgent.ID)
+	}
+
+	searches := s.ListSavedSearches()
+	if len(searches) != 2 || searches[0].Name != "Urgent" || searches[0].Query != "p:1..2" || searches[1].Name != "Floating" {
+		t.Errorf("ListSavedSearches = %+v", searches)
+	}
+
+	counts := s.SavedSearchCounts()
+	if len(counts) != 2 {
+		t.Fatalf("SavedSearchCounts = %+v", counts)
+	}
+	if c := counts[0]; c.SearchID != urgent.ID || c.Pending != 1 || c.Completed != 1 {
+		t.Errorf("Urgent counts = %+v, want 1 pending, 1 completed", c)
+	}
+	if c := counts[1]; c.Pending != 1 || c.Completed != 0 {
+		t.Errorf("Floating counts = %+v, want 1 pending", c)
+	}
+
+	s.DeleteSavedSearch(urgent.ID)
+	if searches := s.ListSavedSearches(); len(searches) != 1 || searches[0].Name != "Floating" {
+		t.Errorf("after delete: %+v", searches)
+	}
+}
+
+func TestOverdueTodos(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	const today = "2026-10-17"
+	old := s.Add("Dentist", "2026-09-20", "day", 1)
+	s.SetTime(old.ID, "09:30", 30)
+	yesterday := s.Add("Rent", "2026-10-16", "day", 0)
+	september := s.Add("Review", "2026-09-01", "month", 0)
+	lastYear := s.Add("Taxes", "2025-01-01", "year", 0)
+	s.Add("Today", today, "day", 0)
+	s.Add("This month", "2026-10-01", "month", 0)
+	s.Add("Floating", "", "", 0)
+	done := s.Add("Done", "2026-10-01", "day", 0)
+	s.Toggle(done.ID)
+
+	var got []int
+	for _, todo := range s.OverdueTodos(today) {
+		if !todo.IsOverdue(today) {
+			t.Errorf("OverdueTodos returned %q, which is not IsOverdue", todo.Text)
+		}
+		got = append(got, todo.ID)
+	}
+	want := []int{lastYear.ID, september.ID, old.ID, yesterday.ID}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("OverdueTodos = %v, want %v", got, want)
+	}
+
+	s.Batch("rollover", func() {
+		for _, id := range want {
+			s.RollOver(id, today)
+		}
+	})
+	if n := len(s.OverdueTodos(today)); n != 0 {
+		t.Errorf("%d todos still overdue after RollOver", n)
+	}
+	if got := s.Find(old.ID); got.Date != today || got.StartTime != "09:30" {
+		t.Errorf("rolled over day todo = %s %s, want %s 09:30", got.Date, got.StartTime, today)
+	}
+	if got := s.Find(september.ID); got.Date != "2026-10-01" || got.DatePrecision != "month" {
+		t.Errorf("rolled over month todo = %s (%s)", got.Date, got.DatePrecision)
+	}
+	if got := s.Find(lastYear.ID); got.Date != "2026-01-01" || got.DatePrecision != "year" {
+		t.Errorf("rolled over year todo = %s (%s)", got.Date, got.DatePrecision)
+	}
+
+	// The rollover is undone as one operation.
+	s.Undo()
+	if n := len(s.OverdueTodos(today)); n != len(want) {
+		t.Errorf("%d todos overdue after undo, want %d", n, len(want))
+	}
+}
+
+func TestRepeatSeries(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	first := s.Add("Standup notes", "2026-10-13", "day", 0)
+	s.SetTime(first.ID, "09:00", 15)
+	s.UpdateBody(first.ID, "- [ ] notes")
+	s.SetReminders(first.ID, []int{10})
+	s.SetRepeat(first.ID, "weekly:tue")
+	if got := s.Find(first.ID); got.SeriesID != first.ID || !got.IsRecurring() {
+		t.Fatalf("repeating todo = %+v, want its own series", got)
+	}
+
+	// AddOccurrence ignores todos that do not repeat.
+	other := s.Add("Once", "2026-10-13", "day", 0)
+	if got := s.AddOccurrence(other.ID, "2026-10-20"); got.ID != 0 {
+		t.Errorf("AddOccurrence of a plain todo = %+v", got)
+	}
+
+	s.Toggle(first.ID)
+	next := s.AddOccurrence(first.ID, "2026-10-20")
+	if next.Date != "2026-10-20" || next.StartTime != "09:00" || next.Duration != 15 ||
+		next.Body != "- [ ] notes" || len(next.Reminders) != 1 || next.Repeat != "weekly:tue" {
+		t.Errorf("occurrence = %+v", next)
+	}
+	if got := s.Find(first.ID); got.Repeat != "" || got.SeriesID != first.ID {
+		t.Errorf("completed occurrence = %+v, want it to keep the series without the rule", got)
+	}
+
+	var dates []string
+	for _, todo := range s.SeriesTodos(first.ID) {
+		dates = append(dates, todo.Date)
+	}
+	if fmt.Sprint(dates) != "[2026-10-13 2026-10-20]" {
+		t.Errorf("SeriesTodos dates = %v", dates)
+	}
+
+	results := s.QueryTodos(query.Query{Filters: []query.Filter{{Field: query.FieldRecurring, Value: "yes"}}})
+	if len(results) != 2 {
+		t.Errorf("recurring:yes found %d todos, want 2", len(results))
+	}
+
+	// Stopping the repeat keeps the todo in its series.
+	s.SetRepeat(next.ID, "")
+	if got := s.Find(next.ID); got.Repeat != "" || got.SeriesID != first.ID {
+		t.Errorf("after SetRepeat(\"\") = %+v", got)
+	}
+}
+
+func TestHolidayPolicyAndShiftedTodo(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	tpl, _ := s.AddTemplate("Rent", "")
+	sc, err := s.AddSchedule(tpl.ID, "monthly", "1", "{}")
+	if err != nil {
+		t.Fatalf("add schedule: %v", err)
+	}
+	if err := s.SetHolidayPolicy(sc.ID, "next"); err != nil {
+		t.Fatalf("set holiday policy: %v", err)
+	}
+	if got := s.ListSchedules()[0].HolidayPolicy; got != "next" {
+		t.Errorf("holiday policy = %q, want next", got)
+	}
+
+	todo := s.AddShiftedTodo("Rent", "2026-01-02", "2026-01-01", "", sc.ID)
+	got := s.Find(todo.ID)
+	if got == nil || got.Date != "2026-01-02" || got.ScheduleDate != "2026-01-01" {
+		t.Fatalf("shifted todo = %+v", got)
+	}
+	if !s.TodoExistsForSchedule(sc.ID, "2026-01-01") || s.TodoExistsForSchedule(sc.ID, "2026-01-02") {
+		t.Error("shifted todo should count for its occurrence, not its date")
+	}
+}
+
+func TestScheduleWindow(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	tpl, _ := s.AddTemplate("Standup", "")
+	sc, _ := s.AddSchedule(tpl.ID, "weekdays", "", "{}")
+	got := s.ListSchedules()[0]
+	if got.LookaheadDays != 7 || got.CatchUp != "" || got.GeneratedThrough != "" {
+		t.Errorf("new schedule = %+v, want 7 days, no catch-up, nothing generated", got)
+	}
+
+	if err := s.SetScheduleWindow(sc.ID, 30, "summary"); err != nil {
+		t.Fatalf("set schedule window: %v", err)
+	}
+	if err := s.SetGeneratedThrough(sc.ID, "2026-03-10"); err != nil {
+		t.Fatalf("set generated through: %v", err)
+	}
+	got = s.ListSchedules()[0]
+	if got.LookaheadDays != 30 || got.CatchUp != "summary" || got.GeneratedThrough != "2026-03-10" {
+		t.Errorf("schedule = %+v", got)
+	}
+}
+
+func TestScheduleLifecycle(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	tpl, _ := s.AddTemplate("Standup", "")
+	sc, _ := s.AddSchedule(tpl.ID, "weekdays", "", "{}")
+	if err := s.SetScheduleRange(sc.ID, "2026-03-01", "2026-06-30", 40); err != nil {
+		t.Fatalf("set schedule range: %v", err)
+	}
+	if err := s.SetSchedulePaused(sc.ID, true); err != nil {
+		t.Fatalf("set schedule paused: %v", err)
+	}
+	for _, d := range []string{"2026-04-03", "2026-03-02", "2026-05-01"} {
+		if err := s.SetScheduleException(sc.ID, d, true); err != nil {
+			t.Fatalf("set schedule exception: %v", err)
+		}
+	}
+	s.SetScheduleException(sc.ID, "2026-05-01", false)
+
+	got := s.ListSchedulesForTemplate(tpl.ID)[0]
+	if got.StartDate != "2026-03-01" || got.EndDate != "2026-06-30" || got.MaxOccurrences != 40 || !got.Paused {
+		t.Errorf("schedule = %+v", got)
+	}
+	if len(got.Exceptions) != 2 || got.Exceptions[0] != "2026-03-02" || got.Exceptions[1] != "2026-04-03" {
+		t.Errorf("exceptions = %v, want [2026-03-02 2026-04-03]", got.Exceptions)
+	}
+
+	s.SetSchedulePaused(sc.ID, false)
+	if s.ListSchedules()[0].Paused {
+		t.Error("schedule still paused after resume")
+	}
+}
+
+func TestScheduleLifecycleTrashesTodos(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	tpl, _ := s.AddTemplate("Standup", "")
+	sc, _ := s.AddSchedule(tpl.ID, "daily", "", "{}")
+	day := func(n int) string { return time.Now().AddDate(0, 0, n).Format(dateFormat) }
+	past := s.AddScheduledTodo("Standup", day(-1), "", sc.ID)
+	next := s.AddScheduledTodo("Standup", day(1), "", sc.ID)
+	done := s.AddScheduledTodo("Standup", day(2), "", sc.ID)
+	s.Toggle(done.ID)
+	later := s.AddScheduledTodo("Standup", day(3), "", sc.ID)
+
+	// Skipping an occurrence trashes its open todo only.
+	if err := s.SetScheduleException(sc.ID, day(3), true); err != nil {
+		t.Fatalf("set schedule exception: %v", err)
+	}
+	s.SetScheduleException(sc.ID, day(2), true)
+	if s.Find(later.ID) != nil || s.Find(done.ID) == nil || s.Find(next.ID) == nil {
+		t.Error("skip did not trash just the open todo of the occurrence")
+	}
+	s.SetScheduleException(sc.ID, day(3), false)
+	if s.Find(later.ID) == nil {
+		t.Error("restoring the occurrence did not restore its todo")
+	}
+
+	// Pausing trashes the open todos from today on.
+	if err := s.SetSchedulePaused(sc.ID, true); err != nil {
+		t.Fatalf("set schedule paused: %v", err)
+	}
+	if s.Find(next.ID) != nil || s.Find(later.ID) != nil {
+		t.Error("pause did not trash upcoming todos")
+	}
+	if s.Find(past.ID) == nil || s.Find(done.ID) == nil {
+		t.Error("pause trashed past or completed todos")
+	}
+	if label, _ := s.Undo(); label != "pause" || s.Find(next.ID) == nil || s.Find(later.ID) == nil || s.ListSchedules()[0].Paused {
+		t.Errorf("undo = %q, want the pause and its trashing reverted at once", label)
+	}
+	s.Redo()
+	s.SetSchedulePaused(sc.ID, false)
+	if s.Find(next.ID) == nil || s.Find(later.ID) == nil {
+		t.Error("resume did not restore the trashed todos")
+	}
+}
+
+func TestScheduleResumeRestoresOnlyItsTrash(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	tpl, _ := s.AddTemplate("Standup", "")
+	sc, _ := s.AddSchedule(tpl.ID, "daily", "", "{}")
+	day := func(n int) string { return time.Now().AddDate(0, 0, n).Format(dateFormat) }
+	deleted := s.AddScheduledTodo("Standup", day(1), "", sc.ID)
+	skipped := s.AddScheduledTodo("Standup", day(2), "", sc.ID)
+	paused := s.AddScheduledTodo("Standup", day(3), "", sc.ID)
+	unskipped := s.AddScheduledTodo("Standup", day(4), "", sc.ID)
+
+	s.Delete(deleted.ID)
+	s.SetScheduleException(sc.ID, day(2), true)
+	s.SetSchedulePaused(sc.ID, true)
+	// Skips while paused keep their todo in the trash on resume, and
+	// restoring one while paused waits for the resume.
+	s.SetScheduleException(sc.ID, day(3), true)
+	s.SetScheduleException(sc.ID, day(3), false)
+	s.SetScheduleException(sc.ID, day(4), true)
+	if s.Find(paused.ID) != nil {
+		t.Error("restoring an occurrence of a paused schedule restored its todo")
+	}
+	if err := s.SetSchedulePaused(sc.ID, false); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+
+	if s.Find(deleted.ID) != nil {
+		t.Error("resume restored a todo deleted by hand")
+	}
+	if s.Find(skipped.ID) != nil || s.Find(unskipped.ID) != nil {
+		t.Error("resume restored the todo of a skipped occurrence")
+	}
+	if s.Find(paused.ID) == nil {
+		t.Error("resume did not restore the todo the pause trashed")
+	}
+
+	s.SetScheduleException(sc.ID, day(4), false)
+	if s.Find(unskipped.ID) == nil {
+		t.Error("restoring the occurrence did not restore its todo")
+	}
+	// A todo deleted by hand after its occurrence was restored stays
+	// deleted.
+	s.SetScheduleException(sc.ID, day(2), false)
+	s.Delete(skipped.ID)
+	s.SetSchedulePaused(sc.ID, true)
+	s.SetSchedulePaused(sc.ID, false)
+	if s.Find(skipped.ID) != nil {
+		t.Error("resume restored a todo deleted by hand after its occurrence was restored")
+	}
+
+	s.SetScheduleException(sc.ID, day(4), true)
+	if label, _ := s.Undo(); label != "skip" || len(s.ListSchedules()[0].Exceptions) != 0 || s.Find(unskipped.ID) == nil {
+		t.Errorf("undo = %q, want the skip and its trashing reverted at once", label)
+	}
+}
+
+func TestUndoRestoresTakenUID(t *testing.T) {
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	a := s.Add("Imported", "2026-10-20", "day", 0)
+	b := s.Add("Other", "", "", 0)
+	s.SetUID(a.ID, "event-1@example.com")
+	s.SetUID(a.ID, "")
+	// Another todo takes the UID outside the journal, e.g. from another
+	// process.
+	s.db.Exec("UPDATE todos SET uid = ? WHERE id = ?", "event-1@example.com", b.ID)
+
+	if _, ok := s.Undo(); !ok {
+		t.Fatal("undo failed on a UID held by another todo")
+	}
+	if got := s.Find(a.ID); got == nil || got.UID != "event-1@example.com" {
+		t.Errorf("restored todo = %+v, want its UID back", got)
+	}
+	if got := s.Find(b.ID); got == nil || got.UID != "" {
+		t.Errorf("other todo = %+v, want its UID cleared", got)
+	}
+}
+
+func TestMigrationBackfillsCompletedAt(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Completed todos from before completion times were recorded.
+	old, err := openDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&SQLiteStore{db: old}).migrateTo(13); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Exec(`INSERT INTO todos (text, done, created_at, deleted_at) VALUES
+		('Done', 1, '2024-03-05', NULL), ('Trashed', 1, '2024-03-06', '2024-04-01 10:00:00'), ('Open', 0, '2024-03-07', NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	old.Close()
+
+	s, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer s.Close()
+
+	want := map[string]string{"Done": "2024-03-05 00:00:00", "Trashed": "", "Open": ""}
+	var rows []Todo
+	rows = append(rows, s.Todos()...)
+	rows = append(rows, s.TrashedTodos()...)
+	for _, todo := range rows {
+		if todo.CompletedAt != want[todo.Text] {
+			t.Errorf("%s: completed_at = %q, want %q", todo.Text, todo.CompletedAt, want[todo.Text])
+		}
+	}
+
+	// Archiving does not move completion times to today.
+	s.ArchiveCompleted(time.Now())
+	if trashed := s.TrashedTodos(); len(trashed) != 1 || trashed[0].CompletedAt != "" {
+		t.Errorf("trashed todos = %+v", trashed)
+	}
+}
@@ -2,27 +2,48 @@ package store
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+
+	"github.com/antti/todo-calendar/internal/query"
 )
 
 // dateFormat is the canonical date layout for todo dates (YYYY-MM-DD).
 const dateFormat = "2006-01-02"
 
+// timestampFormat is the layout of the deleted_at, completed_at and
+// archived_at columns. It sorts and compares correctly as a string.
+const timestampFormat = "2006-01-02 15:04:05"
+
 // Todo represents a single todo item.
 // Date is stored as a plain string ("YYYY-MM-DD") to avoid timezone
 // corruption during JSON round-trips.
 type Todo struct {
-	ID            int    `json:"id"`
-	Text          string `json:"text"`
-	Body          string `json:"body,omitempty"`
-	Date          string `json:"date,omitempty"`
-	Done          bool   `json:"done"`
-	CreatedAt     string `json:"created_at"`
-	SortOrder     int    `json:"sort_order,omitempty"`
-	ScheduleID    int    `json:"schedule_id,omitempty"`
-	ScheduleDate  string `json:"schedule_date,omitempty"`
-	DatePrecision string `json:"date_precision"`
-	Priority      int    `json:"priority"`
+	ID            int      `json:"id"`
+	Text          string   `json:"text"`
+	Body          string   `json:"body,omitempty"`
+	Date          string   `json:"date,omitempty"`
+	Done          bool     `json:"done"`
+	CreatedAt     string   `json:"created_at"`
+	SortOrder     int      `json:"sort_order,omitempty"`
+	ScheduleID    int      `json:"schedule_id,omitempty"`
+	ScheduleDate  string   `json:"schedule_date,omitempty"`
+	DatePrecision string   `json:"date_precision"`
+	Priority      int      `json:"priority"`
+	ProjectID     int      `json:"project_id,omitempty"`
+	StartTime     string   `json:"start_time,omitempty"` // "HH:MM", day-precision todos only
+	Duration      int      `json:"duration,omitempty"`   // minutes; 0 = no duration
+	Tags          []string `json:"tags,omitempty"`
+	Reminders     []int    `json:"reminders,omitempty"`    // minutes before due, ascending
+	DeletedAt     string   `json:"deleted_at,omitempty"`   // when moved to the trash; "" = live
+	CompletedAt   string   `json:"completed_at,omitempty"` // when completed; "" for open todos
+	ArchivedAt    string   `json:"archived_at,omitempty"`  // when archived; "" = shown in the main views
+	UID           string   `json:"uid,omitempty"`          // iCalendar UID of imported todos
+	Repeat        string   `json:"repeat,omitempty"`       // repeat rule of the open occurrence; "" = none
+	SeriesID      int      `json:"series_id,omitempty"`    // first occurrence of a repeating todo
 }
 
 // HasPriority reports whether the todo has a valid priority level (1-3).
@@ -43,22 +64,117 @@ func (t Todo) HasBody() bool {
 	return t.Body != ""
 }
 
+// HasTag reports whether the todo carries the given tag (compared after
+// normalization).
+func (t Todo) HasTag(tag string) bool {
+	name := NormalizeTag(tag)
+	for _, have := range t.Tags {
+		if have == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAllTags reports whether the todo carries every one of the given tags.
+// An empty list matches every todo.
+func (t Todo) HasAllTags(tags []string) bool {
+	for _, tag := range tags {
+		if !t.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeTag converts user input into a canonical tag name, as
+// query.NormalizeTag does.
+func NormalizeTag(tag string) string {
+	return query.NormalizeTag(tag)
+}
+
+// NormalizeTags normalizes each tag and returns the distinct, non-empty
+// names in sorted order.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, tag := range tags {
+		name := NormalizeTag(tag)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ParseTags splits a tag field such as "work, #home oncall" on commas and
+// whitespace and returns the normalized tag names.
+func ParseTags(input string) []string {
+	return NormalizeTags(strings.FieldsFunc(input, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	}))
+}
+
+// SplitTagQuery separates "#tag" tokens from the rest of a filter query.
+// It returns the remaining free text and the normalized tag names, so
+// "report #work #q3" yields ("report", ["q3", "work"]).
+func SplitTagQuery(query string) (string, []string) {
+	var words, tags []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "#") {
+			if name := NormalizeTag(field); name != "" {
+				tags = append(tags, name)
+			}
+			continue
+		}
+		words = append(words, field)
+	}
+	return strings.Join(words, " "), NormalizeTags(tags)
+}
+
 // Template represents a reusable markdown template with placeholders.
 type Template struct {
-	ID        int
-	Name      string
-	Content   string
-	CreatedAt string
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Project is a named list that todos can belong to.
+type Project struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SavedSearch is a named search query, shown as a view in the todo pane.
+type SavedSearch struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	CreatedAt string `json:"created_at"`
 }
 
 // Schedule represents a recurring schedule linked to a template.
 type Schedule struct {
-	ID                 int
-	TemplateID         int
-	CadenceType        string
-	CadenceValue       string
-	PlaceholderDefaults string // JSON object of default placeholder values
-	CreatedAt          string
+	ID                  int      `json:"id"`
+	TemplateID          int      `json:"template_id"`
+	CadenceType         string   `json:"cadence_type"`
+	CadenceValue        string   `json:"cadence_value"`
+	PlaceholderDefaults string   `json:"placeholder_defaults"` // JSON object of default placeholder values
+	CreatedAt           string   `json:"created_at"`
+	HolidayPolicy       string   `json:"holiday_policy,omitempty"`    // "", "skip", "previous" or "next"; see recurring.ShiftDate
+	LookaheadDays       int      `json:"lookahead_days"`              // days ahead todos are created for
+	CatchUp             string   `json:"catch_up,omitempty"`          // "", "all" or "summary"; what happens to missed occurrences
+	GeneratedThrough    string   `json:"generated_through,omitempty"` // last day todos were created for; "" = never
+	StartDate           string   `json:"start_date,omitempty"`        // first day with occurrences; "" = no bound
+	EndDate             string   `json:"end_date,omitempty"`          // last day with occurrences; "" = no bound
+	MaxOccurrences      int      `json:"max_occurrences,omitempty"`   // occurrences from StartDate on; 0 = no limit
+	Paused              bool     `json:"paused,omitempty"`            // paused schedules create no todos
+	Exceptions          []string `json:"exceptions,omitempty"`        // skipped occurrence dates, sorted
 }
 
 // IsMonthPrecision reports whether this todo has month-level date precision.
@@ -81,6 +197,91 @@ func (t Todo) HasDate() bool {
 	return t.Date != ""
 }
 
+// HasTime reports whether the todo has a start time of day.
+func (t Todo) HasTime() bool {
+	return t.StartTime != ""
+}
+
+// EndTime returns the "HH:MM" end time derived from StartTime and Duration,
+// or "" when the todo has no start time or no duration. End times past
+// midnight wrap around.
+func (t Todo) EndTime() string {
+	if t.StartTime == "" || t.Duration <= 0 {
+		return ""
+	}
+	start, err := time.Parse("15:04", t.StartTime)
+	if err != nil {
+		return ""
+	}
+	return start.Add(time.Duration(t.Duration) * time.Minute).Format("15:04")
+}
+
+// TimeLabel returns the todo's time for display: "09:30", "09:30-10:15"
+// when it has a duration, or "" when it is untimed.
+func (t Todo) TimeLabel() string {
+	if end := t.EndTime(); end != "" {
+		return t.StartTime + "-" + end
+	}
+	return t.StartTime
+}
+
+// ParseClock parses a time of day such as "9", "9:30", "09:30" or "0930"
+// into the canonical "HH:MM" form. An empty string yields "".
+func ParseClock(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	hour, minute := s, "0"
+	if h, m, ok := strings.Cut(s, ":"); ok {
+		hour, minute = h, m
+	} else if len(s) > 2 {
+		hour, minute = s[:len(s)-2], s[len(s)-2:]
+	}
+	h, err1 := strconv.Atoi(hour)
+	m, err2 := strconv.Atoi(minute)
+	if err1 != nil || err2 != nil || h < 0 || h > 23 || m < 0 || m > 59 || len(hour) > 2 || len(minute) > 2 {
+		return "", fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return fmt.Sprintf("%02d:%02d", h, m), nil
+}
+
+// ParseDuration parses a duration given either as plain minutes ("90") or
+// in Go duration syntax ("1h30m", "45m") and returns it in whole minutes.
+// An empty string yields 0.
+func ParseDuration(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return n, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 || d%time.Minute != 0 {
+		return 0, fmt.Errorf("invalid duration %q (want minutes or e.g. 1h30m)", s)
+	}
+	return int(d / time.Minute), nil
+}
+
+// FormatDuration formats minutes compactly, e.g. "45m", "2h" or "1h30m".
+// Zero yields "".
+func FormatDuration(minutes int) string {
+	switch {
+	case minutes <= 0:
+		return ""
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+	}
+}
+
 // InMonth reports whether the todo's date falls in the given year and month.
 // Month-precision todos match if year and month match.
 // Year-precision todos match if the year matches.
@@ -104,6 +305,32 @@ func (t Todo) InMonth(year int, month time.Month) bool {
 	}
 }
 
+// IsRecurring reports whether the todo was created by a schedule or is
+// an occurrence of a repeating todo.
+func (t Todo) IsRecurring() bool {
+	return t.ScheduleID != 0 || t.SeriesID != 0
+}
+
+// IsOverdue reports whether the todo is incomplete and its whole date
+// lies before today ("YYYY-MM-DD"): the day of a day-precision todo, the
+// month or year of a fuzzy one. Floating todos are never overdue.
+func (t Todo) IsOverdue(today string) bool {
+	if t.Done || t.Date == "" {
+		return false
+	}
+	d, err := time.Parse(dateFormat, t.Date)
+	if err != nil {
+		return false
+	}
+	switch t.DatePrecision {
+	case "year":
+		d = time.Date(d.Year()+1, 1, 0, 0, 0, 0, 0, time.UTC)
+	case "month":
+		d = time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+	return d.Format(dateFormat) < today
+}
+
 // InDateRange reports whether the todo's date falls within [startDate, endDate] inclusive.
 // Fuzzy-date todos (month/year precision) are excluded from date range matching.
 // Returns false if the todo has no date or any date cannot be parsed.
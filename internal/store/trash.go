@@ -0,0 +1,62 @@
+package store
+
+import "time"
+
+// TrashedTodos returns the todos in the trash, most recently deleted first.
+func (s *SQLiteStore) TrashedTodos() []Todo {
+	rows, err := s.db.Query("SELECT " + todoColumns + " FROM todos WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC")
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	todos, _ := scanTodos(rows)
+	return todos
+}
+
+// Restore moves the todo with the given ID out of the trash. A schedule
+// that trashed it no longer brings it back (see SetSchedulePaused).
+func (s *SQLiteStore) Restore(id int) {
+	before := s.snapshot(id)
+	s.db.Exec("UPDATE todos SET deleted_at = NULL WHERE id = ?", id)
+	s.db.Exec("DELETE FROM schedule_trash WHERE todo_id = ?", id)
+	s.record("restore", id, before)
+}
+
+// Purge permanently removes the trashed todo with the given ID, including
+// its undo history. Todos outside the trash are left alone.
+func (s *SQLiteStore) Purge(id int) {
+	s.purge("id = ?", id)
+}
+
+// PurgeTrash permanently removes every todo moved to the trash before the
+// given time and returns how many were removed.
+func (s *SQLiteStore) PurgeTrash(before time.Time) int {
+	return s.purge("deleted_at < ?", before.Format(timestampFormat))
+}
+
+// purge deletes the trashed todos matching the condition, their journal
+// entries and tags no longer used by any todo.
+func (s *SQLiteStore) purge(cond string, args ...any) int {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0
+	}
+	defer tx.Rollback()
+
+	where := "deleted_at IS NOT NULL AND " + cond
+	if _, err := tx.Exec("DELETE FROM journal WHERE todo_id IN (SELECT id FROM todos WHERE "+where+")", args...); err != nil {
+		return 0
+	}
+	result, err := tx.Exec("DELETE FROM todos WHERE "+where, args...)
+	if err != nil {
+		return 0
+	}
+	if _, err := tx.Exec("DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM todo_tags)"); err != nil {
+		return 0
+	}
+	if err := tx.Commit(); err != nil {
+		return 0
+	}
+	n, _ := result.RowsAffected()
+	return int(n)
+}
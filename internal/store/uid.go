@@ -0,0 +1,32 @@
+package store
+
+// FindByUID returns the todo with the given iCalendar UID, including
+// trashed and archived todos, or nil if there is none.
+func (s *SQLiteStore) FindByUID(uid string) *Todo {
+	row := s.db.QueryRow("SELECT "+todoColumns+" FROM todos WHERE uid = ?", uid)
+	t, err := scanTodo(row)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// SetUID sets the iCalendar UID of the todo with the given ID. An empty
+// uid clears it. The call is ignored if another todo has the UID.
+func (s *SQLiteStore) SetUID(id int, uid string) {
+	var value any
+	if uid != "" {
+		value = uid
+	}
+	before := s.snapshot(id)
+	s.db.Exec("UPDATE todos SET uid = ? WHERE id = ?", value, id)
+	s.record("edit", id, before)
+}
+
+// DatabaseID returns the random identity of the database, which export
+// formats use to tell its todos apart from those of other databases.
+func (s *SQLiteStore) DatabaseID() string {
+	var id string
+	s.db.QueryRow("SELECT value FROM meta WHERE key = 'database_id'").Scan(&id)
+	return id
+}
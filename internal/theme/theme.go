@@ -20,6 +20,7 @@ type Theme struct {
 	TodayBg     lipgloss.Color // today's date background
 	HolidayFg   lipgloss.Color // holiday names and dates
 	IndicatorFg lipgloss.Color // todo-count indicators
+	OverdueFg   lipgloss.Color // overdue days and todo dates
 
 	// Todo list
 	AccentFg    lipgloss.Color // selected item, headings
@@ -56,6 +57,7 @@ func Dark() Theme {
 		TodayBg:         lipgloss.Color(""),         // terminal default
 		HolidayFg:       lipgloss.Color("#D75FAF"),  // magenta (distinct from P1 red)
 		IndicatorFg:     lipgloss.Color(""),         // terminal default
+		OverdueFg:       lipgloss.Color("#FF5F00"),  // orange-red (ANSI 202)
 		AccentFg:        lipgloss.Color("#5F5FD7"),  // ANSI 62
 		MutedFg:         lipgloss.Color("#585858"),  // ANSI 240
 		CompletedFg:     lipgloss.Color("#585858"),  // ANSI 240
@@ -83,6 +85,7 @@ func Light() Theme {
 		TodayBg:         lipgloss.Color("#5F5FD7"), // indigo
 		HolidayFg:       lipgloss.Color("#AF005F"), // dark magenta (distinct from P1 red)
 		IndicatorFg:     lipgloss.Color("#005FAF"), // blue
+		OverdueFg:       lipgloss.Color("#D75F00"), // burnt orange (ANSI 166)
 		AccentFg:        lipgloss.Color("#5F5FD7"), // indigo
 		MutedFg:         lipgloss.Color("#8A8A8A"), // medium grey
 		CompletedFg:     lipgloss.Color("#BCBCBC"), // light grey
@@ -111,6 +114,7 @@ func Nord() Theme {
 		TodayBg:         lipgloss.Color("#88C0D0"), // nord8 frost
 		HolidayFg:       lipgloss.Color("#B48EAD"), // nord15 aurora purple (distinct from P1 red)
 		IndicatorFg:     lipgloss.Color("#EBCB8B"), // nord13 aurora yellow
+		OverdueFg:       lipgloss.Color("#D08770"), // nord12 aurora orange
 		AccentFg:        lipgloss.Color("#88C0D0"), // nord8 frost
 		MutedFg:         lipgloss.Color("#81A1C1"), // nord9 muted frost (better contrast)
 		CompletedFg:     lipgloss.Color("#4C566A"), // nord3 polar night
@@ -139,6 +143,7 @@ func Solarized() Theme {
 		TodayBg:         lipgloss.Color("#268BD2"), // blue
 		HolidayFg:       lipgloss.Color("#D33682"), // solarized magenta (distinct from P1 red)
 		IndicatorFg:     lipgloss.Color("#B58900"), // yellow
+		OverdueFg:       lipgloss.Color("#CB4B16"), // solarized orange
 		AccentFg:        lipgloss.Color("#268BD2"), // blue
 		MutedFg:         lipgloss.Color("#657B83"), // base00 (better contrast)
 		CompletedFg:     lipgloss.Color("#586E75"), // base01
@@ -16,11 +16,15 @@ type KeyMap struct {
 	Left     key.Binding
 	Right    key.Binding
 	Toggle   key.Binding
+	NextRow  key.Binding
+	Pause    key.Binding
+	Upcoming key.Binding
+	Skip     key.Binding
 }
 
 // ShortHelp returns key bindings for the short help view.
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.Create, k.Delete, k.Rename, k.Edit, k.Schedule, k.Cancel}
+	return []key.Binding{k.Up, k.Down, k.Create, k.Delete, k.Rename, k.Edit, k.Schedule, k.Pause, k.Upcoming, k.Cancel}
 }
 
 // FullHelp returns key bindings for the full help view.
@@ -69,15 +73,31 @@ func DefaultKeyMap() KeyMap {
 		),
 		Left: key.NewBinding(
 			key.WithKeys("left", "h"),
-			key.WithHelp("left/h", "prev type"),
+			key.WithHelp("left/h", "prev option"),
 		),
 		Right: key.NewBinding(
 			key.WithKeys("right", "l"),
-			key.WithHelp("right/l", "next type"),
+			key.WithHelp("right/l", "next option"),
 		),
 		Toggle: key.NewBinding(
 			key.WithKeys(" "),
 			key.WithHelp("space", "toggle"),
 		),
+		NextRow: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next setting"),
+		),
+		Pause: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pause/resume"),
+		),
+		Upcoming: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "upcoming"),
+		),
+		Skip: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "skip/restore"),
+		),
 	}
 }
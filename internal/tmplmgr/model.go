@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/antti/todo-calendar/internal/holidays"
 	"github.com/antti/todo-calendar/internal/recurring"
 	"github.com/antti/todo-calendar/internal/store"
 	"github.com/antti/todo-calendar/internal/theme"
@@ -25,8 +27,27 @@ const (
 	renameMode
 	scheduleMode
 	placeholderDefaultsMode
+	upcomingMode
 )
 
+// Rows of the schedule picker; Tab moves between them and left/right
+// change the option of the focused one.
+const (
+	rowCadence = iota
+	rowHolidays
+	rowLookahead
+	rowCatchUp
+	rowStart
+	rowEnd
+	pickerRows
+)
+
+// lookaheadOptions are the look-ahead windows, in days, the schedule
+// picker offers; defaultLookaheadIndex is the 7-day default.
+var lookaheadOptions = []int{1, 3, 7, 14, 30, 60, 90}
+
+const defaultLookaheadIndex = 2
+
 // CloseMsg is emitted when the user presses Esc to close the overlay.
 type CloseMsg struct{}
 
@@ -57,7 +78,20 @@ type Model struct {
 	weeklyDays      [7]bool
 	weekdayCursor   int
 	monthlyInput    textinput.Model
+	advancedInput   textinput.Model
+	pickerRow       int // focused row of the picker, one of the row constants
+	holidayIndex    int // index into recurring.HolidayPolicies
+	lookaheadIndex  int // index into lookaheadOptions
+	catchUpIndex    int // index into recurring.CatchUpModes
+	startInput      textinput.Model
+	endInput        textinput.Model // an end date or a number of occurrences
 	editingSchedule *store.Schedule
+	holidays        *holidays.Provider
+
+	// Upcoming occurrences state
+	upcoming       []recurring.Occurrence
+	upcomingCursor int
+	upcomingSched  store.Schedule
 
 	// Placeholder defaults state
 	pendingCadenceType  string
@@ -79,18 +113,36 @@ func New(s store.TodoStore, t theme.Theme) Model {
 	mi.CharLimit = 2
 	mi.Placeholder = "1-31"
 
+	ai := textinput.New()
+	ai.Prompt = "> "
+	ai.CharLimit = 200
+	ai.Placeholder = "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"
+
 	di := textinput.New()
 	di.Prompt = "> "
 	di.CharLimit = 200
 
+	si := textinput.New()
+	si.Prompt = "> "
+	si.CharLimit = 10
+	si.Placeholder = "YYYY-MM-DD"
+
+	ei := textinput.New()
+	ei.Prompt = "> "
+	ei.CharLimit = 10
+	ei.Placeholder = "YYYY-MM-DD or number of times"
+
 	m := Model{
 		store:         s,
 		keys:          DefaultKeyMap(),
 		styles:        NewStyles(t),
 		input:         ti,
-		cadenceTypes:  []string{"none", "daily", "weekdays", "weekly", "monthly"},
+		cadenceTypes:  []string{"none", "daily", "weekdays", "weekly", "monthly", "advanced"},
 		monthlyInput:  mi,
+		advancedInput: ai,
 		defaultsInput: di,
+		startInput:    si,
+		endInput:      ei,
 	}
 	m.RefreshTemplates()
 	return m
@@ -102,6 +154,19 @@ func (m *Model) SetSize(w, h int) {
 	m.height = h
 }
 
+// SetHolidayProvider sets the holidays schedule holiday policies use.
+func (m *Model) SetHolidayProvider(p *holidays.Provider) {
+	m.holidays = p
+}
+
+// isHoliday returns the holiday lookup of the provider, or nil without one.
+func (m Model) isHoliday() func(time.Time) bool {
+	if m.holidays == nil {
+		return nil
+	}
+	return m.holidays.IsHoliday
+}
+
 // SetTheme replaces the styles with ones built from the given theme.
 func (m *Model) SetTheme(t theme.Theme) {
 	m.styles = NewStyles(t)
@@ -115,15 +180,17 @@ func (m Model) HelpBindings() []key.Binding {
 	case renameMode:
 		return []key.Binding{m.keys.Confirm, m.keys.Cancel}
 	case scheduleMode:
-		bindings := []key.Binding{m.keys.Left, m.keys.Right, m.keys.Confirm, m.keys.Cancel}
+		bindings := []key.Binding{m.keys.Left, m.keys.Right, m.keys.NextRow, m.keys.Confirm, m.keys.Cancel}
 		if m.cadenceTypes[m.cadenceIndex] == "weekly" {
-			bindings = []key.Binding{m.keys.Left, m.keys.Right, m.keys.Up, m.keys.Down, m.keys.Toggle, m.keys.Confirm, m.keys.Cancel}
+			bindings = []key.Binding{m.keys.Left, m.keys.Right, m.keys.Up, m.keys.Down, m.keys.Toggle, m.keys.NextRow, m.keys.Confirm, m.keys.Cancel}
 		}
 		return bindings
 	case placeholderDefaultsMode:
 		return []key.Binding{m.keys.Confirm, m.keys.Cancel}
+	case upcomingMode:
+		return []key.Binding{m.keys.Up, m.keys.Down, m.keys.Skip, m.keys.Cancel}
 	default:
-		return []key.Binding{m.keys.Up, m.keys.Down, m.keys.Create, m.keys.Delete, m.keys.Rename, m.keys.Edit, m.keys.Schedule, m.keys.Cancel}
+		return []key.Binding{m.keys.Up, m.keys.Down, m.keys.Create, m.keys.Delete, m.keys.Rename, m.keys.Edit, m.keys.Schedule, m.keys.Pause, m.keys.Upcoming, m.keys.Cancel}
 	}
 }
 
@@ -167,6 +234,8 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m.updateScheduleMode(msg)
 		case placeholderDefaultsMode:
 			return m.updatePlaceholderDefaultsMode(msg)
+		case upcomingMode:
+			return m.updateUpcomingMode(msg)
 		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -227,6 +296,30 @@ func (m Model) updateListMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.Pause):
+		sched, ok := m.selectedSchedule()
+		if !ok {
+			m.err = "No schedule to pause"
+			return m, nil
+		}
+		if err := m.store.SetSchedulePaused(sched.ID, !sched.Paused); err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+		return m, func() tea.Msg { return TemplateUpdatedMsg{} }
+
+	case key.Matches(msg, m.keys.Upcoming):
+		sched, ok := m.selectedSchedule()
+		if !ok {
+			m.err = "No schedule"
+			return m, nil
+		}
+		m.mode = upcomingMode
+		m.upcomingCursor = 0
+		m.err = ""
+		m.loadUpcoming(sched)
+		return m, nil
+
 	case key.Matches(msg, m.keys.Schedule):
 		if sel := m.selected(); sel != nil {
 			m.mode = scheduleMode
@@ -235,6 +328,16 @@ func (m Model) updateListMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.weekdayCursor = 0
 			m.monthlyInput.SetValue("")
 			m.monthlyInput.Blur()
+			m.advancedInput.SetValue("")
+			m.advancedInput.Blur()
+			m.pickerRow = rowCadence
+			m.holidayIndex = 0
+			m.lookaheadIndex = defaultLookaheadIndex
+			m.catchUpIndex = 0
+			m.startInput.SetValue("")
+			m.startInput.Blur()
+			m.endInput.SetValue("")
+			m.endInput.Blur()
 			m.editingSchedule = nil
 			m.err = ""
 
@@ -243,6 +346,28 @@ func (m Model) updateListMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			if len(scheds) > 0 {
 				sched := scheds[0]
 				m.editingSchedule = &sched
+				for i, p := range recurring.HolidayPolicies {
+					if p == sched.HolidayPolicy {
+						m.holidayIndex = i
+					}
+				}
+				for i, n := range lookaheadOptions {
+					if n <= recurring.Lookahead(sched) {
+						m.lookaheadIndex = i
+					}
+				}
+				for i, c := range recurring.CatchUpModes {
+					if c == sched.CatchUp {
+						m.catchUpIndex = i
+					}
+				}
+				m.startInput.SetValue(sched.StartDate)
+				switch {
+				case sched.EndDate != "":
+					m.endInput.SetValue(sched.EndDate)
+				case sched.MaxOccurrences > 0:
+					m.endInput.SetValue(strconv.Itoa(sched.MaxOccurrences))
+				}
 
 				ruleStr := sched.CadenceType
 				if sched.CadenceValue != "" {
@@ -270,6 +395,11 @@ func (m Model) updateListMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 						m.cadenceIndex = 4
 						m.monthlyInput.SetValue(strconv.Itoa(rule.DayOfMonth))
 						m.monthlyInput.Focus()
+					case "rrule":
+						m.cadenceIndex = 5
+						m.advancedInput.SetValue(rule.Recur.String())
+						m.advancedInput.Focus()
+						m.advancedInput.CursorEnd()
 					}
 				}
 			}
@@ -362,33 +492,41 @@ var dayNames = [7]string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
 
 // updateScheduleMode handles key messages in schedule picker mode.
 func (m Model) updateScheduleMode(msg tea.KeyMsg) (Model, tea.Cmd) {
+	// Keys other than Tab, Enter and Esc belong to a focused date input.
+	if m.pickerRow == rowStart || m.pickerRow == rowEnd {
+		if !key.Matches(msg, m.keys.NextRow, m.keys.Confirm, m.keys.Cancel) {
+			var cmd tea.Cmd
+			if m.pickerRow == rowStart {
+				m.startInput, cmd = m.startInput.Update(msg)
+			} else {
+				m.endInput, cmd = m.endInput.Update(msg)
+			}
+			return m, cmd
+		}
+	}
+
+	// Letters typed into an advanced rule belong to the rule, not to the
+	// h/l and j/k bindings.
+	if m.pickerRow == rowCadence && m.cadenceTypes[m.cadenceIndex] == "advanced" && msg.Type == tea.KeyRunes {
+		var cmd tea.Cmd
+		m.advancedInput, cmd = m.advancedInput.Update(msg)
+		return m, cmd
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Cancel):
 		m.mode = listMode
 		m.err = ""
 		m.monthlyInput.Blur()
+		m.advancedInput.Blur()
 		return m, nil
 
 	case key.Matches(msg, m.keys.Left):
-		wasMonthly := m.cadenceTypes[m.cadenceIndex] == "monthly"
-		m.cadenceIndex = (m.cadenceIndex - 1 + len(m.cadenceTypes)) % len(m.cadenceTypes)
-		if wasMonthly {
-			m.monthlyInput.Blur()
-		}
-		if m.cadenceTypes[m.cadenceIndex] == "monthly" {
-			m.monthlyInput.Focus()
-		}
+		m.stepPickerRow(-1)
 		return m, nil
 
 	case key.Matches(msg, m.keys.Right):
-		wasMonthly := m.cadenceTypes[m.cadenceIndex] == "monthly"
-		m.cadenceIndex = (m.cadenceIndex + 1) % len(m.cadenceTypes)
-		if wasMonthly {
-			m.monthlyInput.Blur()
-		}
-		if m.cadenceTypes[m.cadenceIndex] == "monthly" {
-			m.monthlyInput.Focus()
-		}
+		m.stepPickerRow(1)
 		return m, nil
 
 	case key.Matches(msg, m.keys.Up):
@@ -409,11 +547,20 @@ func (m Model) updateScheduleMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.NextRow):
+		// Without a cadence there is nothing else to set.
+		if m.cadenceTypes[m.cadenceIndex] != "none" {
+			m.pickerRow = (m.pickerRow + 1) % pickerRows
+			m.focusPickerRow()
+		}
+		return m, nil
+
 	case key.Matches(msg, m.keys.Confirm):
 		sel := m.selected()
 		if sel == nil {
 			m.mode = listMode
 			m.monthlyInput.Blur()
+			m.advancedInput.Blur()
 			return m, nil
 		}
 
@@ -427,6 +574,7 @@ func (m Model) updateScheduleMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.mode = listMode
 			m.err = ""
 			m.monthlyInput.Blur()
+			m.advancedInput.Blur()
 			m.RefreshTemplates()
 			return m, func() tea.Msg { return TemplateUpdatedMsg{} }
 
@@ -461,6 +609,26 @@ func (m Model) updateScheduleMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			}
 			cadenceType = "monthly"
 			cadenceValue = dayStr
+
+		case "advanced":
+			ruleStr := strings.TrimSpace(m.advancedInput.Value())
+			if ruleStr == "" {
+				m.err = "Enter a rule, e.g. FREQ=MONTHLY;BYDAY=-1FR"
+				return m, nil
+			}
+			// Rules are anchored to the day they are entered unless they
+			// name their own start.
+			rule, err := recurring.ParseRule(recurring.AnchorRule("rrule:"+ruleStr, time.Now()))
+			if err != nil {
+				m.err = "Invalid rule: " + err.Error()
+				return m, nil
+			}
+			cadenceType = "rrule"
+			cadenceValue = rule.Recur.String()
+		}
+		if _, _, _, msg := m.scheduleRange(); msg != "" {
+			m.err = msg
+			return m, nil
 		}
 
 		// Check if template has placeholders that need defaults.
@@ -478,36 +646,206 @@ func (m Model) updateScheduleMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.defaultsInput.Focus()
 			m.defaultsInput.CursorEnd()
 			m.monthlyInput.Blur()
+			m.advancedInput.Blur()
 			m.mode = placeholderDefaultsMode
 			return m, nil
 		}
 
 		defaults := "{}"
-		if m.editingSchedule != nil {
-			if m.editingSchedule.PlaceholderDefaults != "" {
-				defaults = m.editingSchedule.PlaceholderDefaults
-			}
-			m.store.UpdateSchedule(m.editingSchedule.ID, cadenceType, cadenceValue, defaults)
-		} else {
-			m.store.AddSchedule(sel.ID, cadenceType, cadenceValue, defaults)
+		if m.editingSchedule != nil && m.editingSchedule.PlaceholderDefaults != "" {
+			defaults = m.editingSchedule.PlaceholderDefaults
 		}
+		m.saveSchedule(sel.ID, cadenceType, cadenceValue, defaults)
 		m.mode = listMode
 		m.err = ""
 		m.monthlyInput.Blur()
+		m.advancedInput.Blur()
 		m.RefreshTemplates()
 		return m, func() tea.Msg { return TemplateUpdatedMsg{} }
 	}
 
-	// Forward to monthlyInput when in monthly mode.
-	if m.cadenceTypes[m.cadenceIndex] == "monthly" {
-		var cmd tea.Cmd
+	// Forward to the text input of the monthly and advanced types.
+	var cmd tea.Cmd
+	switch m.cadenceTypes[m.cadenceIndex] {
+	case "monthly":
 		m.monthlyInput, cmd = m.monthlyInput.Update(msg)
-		return m, cmd
+	case "advanced":
+		m.advancedInput, cmd = m.advancedInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// saveSchedule updates the schedule being edited, or adds one to the
+// template with the given ID, along with the chosen holiday policy.
+func (m Model) saveSchedule(templateID int, cadenceType, cadenceValue, defaults string) {
+	id := 0
+	if m.editingSchedule != nil {
+		if m.store.UpdateSchedule(m.editingSchedule.ID, cadenceType, cadenceValue, defaults) == nil {
+			id = m.editingSchedule.ID
+		}
+	} else if sched, err := m.store.AddSchedule(templateID, cadenceType, cadenceValue, defaults); err == nil {
+		id = sched.ID
+	}
+	if id != 0 {
+		m.store.SetHolidayPolicy(id, recurring.HolidayPolicies[m.holidayIndex])
+		m.store.SetScheduleWindow(id, lookaheadOptions[m.lookaheadIndex], recurring.CatchUpModes[m.catchUpIndex])
+		start, end, maxOccurrences, _ := m.scheduleRange()
+		m.store.SetScheduleRange(id, start, end, maxOccurrences)
+	}
+}
+
+// scheduleRange parses the start and end inputs of the picker into a
+// start date, an end date and a maximum number of occurrences. msg
+// describes the first invalid input, if any.
+func (m Model) scheduleRange() (start, end string, maxOccurrences int, msg string) {
+	start = strings.TrimSpace(m.startInput.Value())
+	if start != "" {
+		if _, err := time.Parse("2006-01-02", start); err != nil {
+			return "", "", 0, "Enter the start as YYYY-MM-DD"
+		}
+	}
+	end = strings.TrimSpace(m.endInput.Value())
+	if n, err := strconv.Atoi(end); err == nil {
+		if n < 1 {
+			return "", "", 0, "Enter at least 1 occurrence"
+		}
+		return start, "", n, ""
+	}
+	if end != "" {
+		if _, err := time.Parse("2006-01-02", end); err != nil {
+			return "", "", 0, "Enter the end as YYYY-MM-DD or a number of times"
+		}
+		if end < start {
+			return "", "", 0, "The end is before the start"
+		}
+	}
+	return start, end, 0, ""
+}
+
+// focusPickerRow focuses the text input of the focused picker row, if it
+// has one, and blurs the others.
+func (m *Model) focusPickerRow() {
+	m.startInput.Blur()
+	m.endInput.Blur()
+	switch m.pickerRow {
+	case rowCadence:
+		m.focusCadenceInput()
+	case rowStart:
+		m.monthlyInput.Blur()
+		m.advancedInput.Blur()
+		m.startInput.Focus()
+	case rowEnd:
+		m.monthlyInput.Blur()
+		m.advancedInput.Blur()
+		m.endInput.Focus()
+	default:
+		m.monthlyInput.Blur()
+		m.advancedInput.Blur()
+	}
+}
+
+// selectedSchedule returns the schedule of the selected template.
+func (m Model) selectedSchedule() (store.Schedule, bool) {
+	sel := m.selected()
+	if sel == nil {
+		return store.Schedule{}, false
+	}
+	scheds := m.store.ListSchedulesForTemplate(sel.ID)
+	if len(scheds) == 0 {
+		return store.Schedule{}, false
+	}
+	return scheds[0], true
+}
+
+// upcomingCount is the number of occurrences the upcoming view lists.
+const upcomingCount = 10
+
+// loadUpcoming lists the upcoming occurrences of sched.
+func (m *Model) loadUpcoming(sched store.Schedule) {
+	m.upcomingSched = sched
+	m.upcoming = nil
+	ruleStr := sched.CadenceType
+	if sched.CadenceValue != "" {
+		ruleStr += ":" + sched.CadenceValue
+	}
+	if rule, err := recurring.ParseRule(ruleStr); err == nil {
+		m.upcoming = recurring.Upcoming(sched, rule, time.Now(), upcomingCount, m.isHoliday())
+	}
+	if m.upcomingCursor >= len(m.upcoming) && m.upcomingCursor > 0 {
+		m.upcomingCursor = len(m.upcoming) - 1
 	}
+}
 
+// updateUpcomingMode handles key messages in the upcoming occurrences view.
+func (m Model) updateUpcomingMode(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = listMode
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.upcomingCursor < len(m.upcoming)-1 {
+			m.upcomingCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		if m.upcomingCursor > 0 {
+			m.upcomingCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Skip):
+		if len(m.upcoming) == 0 {
+			return m, nil
+		}
+		occ := m.upcoming[m.upcomingCursor]
+		id := m.upcomingSched.ID
+		if err := m.store.SetScheduleException(id, occ.Date.Format("2006-01-02"), !occ.Exception); err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+		m.err = ""
+		for _, sched := range m.store.ListSchedules() {
+			if sched.ID == id {
+				m.loadUpcoming(sched)
+			}
+		}
+		return m, func() tea.Msg { return TemplateUpdatedMsg{} }
+	}
 	return m, nil
 }
 
+// stepPickerRow moves the option of the focused picker row by delta,
+// wrapping around at either end.
+func (m *Model) stepPickerRow(delta int) {
+	step := func(i, n int) int { return (i + delta + n) % n }
+	switch m.pickerRow {
+	case rowHolidays:
+		m.holidayIndex = step(m.holidayIndex, len(recurring.HolidayPolicies))
+	case rowLookahead:
+		m.lookaheadIndex = step(m.lookaheadIndex, len(lookaheadOptions))
+	case rowCatchUp:
+		m.catchUpIndex = step(m.catchUpIndex, len(recurring.CatchUpModes))
+	default:
+		m.cadenceIndex = step(m.cadenceIndex, len(m.cadenceTypes))
+		m.focusCadenceInput()
+	}
+}
+
+// focusCadenceInput focuses the text input of the selected cadence type,
+// if it has one, and blurs the others.
+func (m *Model) focusCadenceInput() {
+	m.monthlyInput.Blur()
+	m.advancedInput.Blur()
+	switch m.cadenceTypes[m.cadenceIndex] {
+	case "monthly":
+		m.monthlyInput.Focus()
+	case "advanced":
+		m.advancedInput.Focus()
+	}
+}
+
 // updatePlaceholderDefaultsMode handles key messages in placeholder defaults mode.
 func (m Model) updatePlaceholderDefaultsMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch {
@@ -531,11 +869,8 @@ func (m Model) updatePlaceholderDefaultsMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		defaultsJSON, _ := json.Marshal(m.placeholderValues)
 		defaults := string(defaultsJSON)
 
-		sel := m.selected()
-		if m.editingSchedule != nil {
-			m.store.UpdateSchedule(m.editingSchedule.ID, m.pendingCadenceType, m.pendingCadenceValue, defaults)
-		} else if sel != nil {
-			m.store.AddSchedule(sel.ID, m.pendingCadenceType, m.pendingCadenceValue, defaults)
+		if sel := m.selected(); sel != nil {
+			m.saveSchedule(sel.ID, m.pendingCadenceType, m.pendingCadenceValue, defaults)
 		}
 		m.mode = listMode
 		m.err = ""
@@ -629,6 +964,8 @@ func (m Model) View() string {
 	if m.mode == scheduleMode {
 		// Schedule picker.
 		b.WriteString(m.renderSchedulePicker())
+	} else if m.mode == upcomingMode {
+		b.WriteString(m.renderUpcoming())
 	} else if m.mode == placeholderDefaultsMode {
 		// Placeholder defaults prompting.
 		prompt := fmt.Sprintf("Set default for %q (%d/%d):",
@@ -661,24 +998,28 @@ func (m Model) View() string {
 func (m Model) renderSchedulePicker() string {
 	var b strings.Builder
 
-	// Cadence type bar: < None  Daily  Weekdays  Weekly  Monthly >
-	displayNames := [5]string{"None", "Daily", "Weekdays", "Weekly", "Monthly"}
-	b.WriteString("Schedule: < ")
-	for i, name := range displayNames {
-		if i == m.cadenceIndex {
-			b.WriteString(m.styles.ScheduleActive.Render(name))
-		} else {
-			b.WriteString(m.styles.ScheduleInactive.Render(name))
-		}
-		if i < len(displayNames)-1 {
-			b.WriteString("  ")
-		}
-	}
-	b.WriteString(" >")
-	b.WriteString("\n")
+	// Cadence type bar: < None  Daily  Weekdays  Weekly  Monthly  Advanced >
+	displayNames := [6]string{"None", "Daily", "Weekdays", "Weekly", "Monthly", "Advanced"}
+	b.WriteString(m.optionBar(rowCadence, "Schedule:  ", displayNames[:], m.cadenceIndex))
 
 	cadence := m.cadenceTypes[m.cadenceIndex]
 
+	// Holiday policy, look-ahead and catch-up bars, e.g.
+	// Holidays: < Create anyway  Skip  Previous workday  Next workday >
+	if cadence != "none" {
+		policyNames := []string{"Create anyway", "Skip", "Previous workday", "Next workday"}
+		b.WriteString(m.optionBar(rowHolidays, "Holidays:  ", policyNames, m.holidayIndex))
+		lookaheadNames := make([]string, len(lookaheadOptions))
+		for i, n := range lookaheadOptions {
+			lookaheadNames[i] = strconv.Itoa(n) + "d"
+		}
+		b.WriteString(m.optionBar(rowLookahead, "Look ahead:", lookaheadNames, m.lookaheadIndex))
+		catchUpNames := []string{"Skip", "Create each", "Summary todo"}
+		b.WriteString(m.optionBar(rowCatchUp, "Missed:    ", catchUpNames, m.catchUpIndex))
+		b.WriteString(m.inputRow(rowStart, "Starts:    ", m.startInput))
+		b.WriteString(m.inputRow(rowEnd, "Ends:      ", m.endInput))
+	}
+
 	switch cadence {
 	case "weekly":
 		b.WriteString("\n")
@@ -706,6 +1047,14 @@ func (m Model) renderSchedulePicker() string {
 		b.WriteString("  Day of month: ")
 		b.WriteString(m.monthlyInput.View())
 		b.WriteString("\n")
+
+	case "advanced":
+		b.WriteString("\n")
+		b.WriteString("  RRULE: ")
+		b.WriteString(m.advancedInput.View())
+		b.WriteString("\n")
+		b.WriteString(m.styles.ScheduleInactive.Render("  e.g. FREQ=MONTHLY;BYDAY=-1FR (last Friday of the month)"))
+		b.WriteString("\n")
 	}
 
 	// Error message.
@@ -717,9 +1066,92 @@ func (m Model) renderSchedulePicker() string {
 	return b.String()
 }
 
+// optionBar renders one row of the schedule picker, e.g.
+// "> Missed: < Skip  Create each  Summary todo >", with the chosen option
+// highlighted and a cursor when the row has focus.
+func (m Model) optionBar(row int, label string, names []string, selected int) string {
+	var b strings.Builder
+	if row == m.pickerRow {
+		b.WriteString("> ")
+	} else {
+		b.WriteString("  ")
+	}
+	b.WriteString(label + " < ")
+	for i, name := range names {
+		if i == selected {
+			b.WriteString(m.styles.ScheduleActive.Render(name))
+		} else {
+			b.WriteString(m.styles.ScheduleInactive.Render(name))
+		}
+		if i < len(names)-1 {
+			b.WriteString("  ")
+		}
+	}
+	b.WriteString(" >\n")
+	return b.String()
+}
+
+// inputRow renders a text input row of the schedule picker, with a cursor
+// when the row has focus.
+func (m Model) inputRow(row int, label string, input textinput.Model) string {
+	cursor := "  "
+	if row == m.pickerRow {
+		cursor = "> "
+	}
+	return cursor + label + " " + input.View() + "\n"
+}
+
+// renderUpcoming renders the upcoming occurrences of the selected
+// schedule below the separator, e.g.
+//
+//	> Fri Jan 1   -> Mon Jan 4
+//	  Mon Feb 1   skipped
+func (m Model) renderUpcoming() string {
+	var b strings.Builder
+	title := "Upcoming"
+	if m.upcomingSched.Paused {
+		title += " (paused)"
+	}
+	b.WriteString(m.styles.SchedulePrompt.Render(title))
+	b.WriteString("\n")
+	if len(m.upcoming) == 0 {
+		b.WriteString(m.styles.Empty.Render("  (no upcoming occurrences)"))
+		return b.String()
+	}
+	for i, occ := range m.upcoming {
+		cursor := "  "
+		if i == m.upcomingCursor {
+			cursor = "> "
+		}
+		var note string
+		switch {
+		case occ.Exception:
+			note = "skipped"
+		case occ.Skipped:
+			note = "no todo (" + recurring.HolidayPolicyLabel(m.upcomingSched.HolidayPolicy) + ")"
+		case !occ.Due.Equal(occ.Date):
+			note = "-> " + occ.Due.Format("Mon Jan 2")
+		}
+		if m.store.TodoExistsForSchedule(m.upcomingSched.ID, occ.Date.Format("2006-01-02")) {
+			note = strings.TrimSpace(note + " created")
+		}
+		day := fmt.Sprintf("%s%-11s", cursor, occ.Date.Format("Mon Jan 2"))
+		style := m.styles.ScheduleDay
+		if occ.Exception {
+			style = m.styles.ScheduleInactive
+		}
+		b.WriteString(style.Render(day) + " " + m.styles.ScheduleInactive.Render(note) + "\n")
+	}
+	if m.err != "" {
+		b.WriteString(m.styles.Error.Render("  " + m.err))
+	}
+	return b.String()
+}
+
 // scheduleLabel returns a display suffix for the schedule attached to the
-// given template, e.g. "(daily)", "(Mon/Wed/Fri)", "(15th of month)".
-// Returns "" if no schedule is attached.
+// given template, e.g. "(daily)", "(Mon/Wed/Fri)", "(15th of month)", or
+// "(1st of month, next workday: Jan 1 -> Fri Jan 2)" when a holiday policy
+// moves its next occurrence. Returns "" if no schedule is attached.
 func (m Model) scheduleLabel(templateID int) string {
 	schedules := m.store.ListSchedulesForTemplate(templateID)
 	if len(schedules) == 0 {
@@ -739,23 +1171,86 @@ func (m Model) scheduleLabel(templateID int) string {
 		return "(" + sched.CadenceType + ")"
 	}
 
+	var desc string
 	switch rule.Type {
 	case "daily":
-		return "(daily)"
+		desc = "daily"
 	case "weekdays":
-		return "(weekdays)"
+		desc = "weekdays"
 	case "weekly":
 		dayLabels := make([]string, len(rule.Days))
 		for i, d := range rule.Days {
 			// Capitalize first letter: "mon" -> "Mon"
 			dayLabels[i] = strings.ToUpper(d[:1]) + d[1:]
 		}
-		return "(" + strings.Join(dayLabels, "/") + ")"
+		desc = strings.Join(dayLabels, "/")
 	case "monthly":
-		return fmt.Sprintf("(%d%s of month)", rule.DayOfMonth, ordinalSuffix(rule.DayOfMonth))
+		desc = fmt.Sprintf("%d%s of month", rule.DayOfMonth, ordinalSuffix(rule.DayOfMonth))
+	case "rrule":
+		desc = rule.Recur.RRule()
 	default:
-		return "(" + sched.CadenceType + ")"
+		desc = sched.CadenceType
+	}
+	if n := recurring.Lookahead(sched); n != recurring.DefaultLookahead {
+		desc += fmt.Sprintf(", %d days ahead", n)
+	}
+	if sched.CatchUp != recurring.CatchUpNone {
+		desc += ", " + recurring.CatchUpLabel(sched.CatchUp)
+	}
+	desc += rangeNote(sched, rule)
+	if sched.Paused {
+		desc += ", paused"
+	}
+	return "(" + desc + m.holidayNote(sched, rule) + ")"
+}
+
+// rangeNote describes the start and end of a schedule, e.g. ", from Mar 1,
+// 10 times" or ", ended".
+func rangeNote(sched store.Schedule, rule recurring.ScheduleRule) string {
+	occ := recurring.NewOccurrences(sched, rule)
+	if occ.Ended(time.Now()) {
+		return ", ended"
+	}
+	var note string
+	first, _ := occ.Bounds()
+	if first.After(time.Now()) {
+		note += ", from " + first.Format("Jan 2, 2006")
+	}
+	if sched.EndDate != "" {
+		if d, err := time.Parse("2006-01-02", sched.EndDate); err == nil {
+			note += ", until " + d.Format("Jan 2, 2006")
+		}
+	}
+	if sched.MaxOccurrences > 0 {
+		note += fmt.Sprintf(", %d times", sched.MaxOccurrences)
+	}
+	return note
+}
+
+// holidayNote describes the holiday policy of a schedule and, when its
+// next occurrence falls on a weekend or holiday, what happens to it.
+func (m Model) holidayNote(sched store.Schedule, rule recurring.ScheduleRule) string {
+	if sched.HolidayPolicy == recurring.HolidayCreate {
+		return ""
+	}
+	note := ", " + recurring.HolidayPolicyLabel(sched.HolidayPolicy)
+
+	now := time.Now()
+	d := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	for i := 0; i < 366 && !rule.MatchesDate(d); i++ {
+		d = d.AddDate(0, 0, 1)
+	}
+	if !rule.MatchesDate(d) {
+		return note
+	}
+	shifted, ok := recurring.ShiftDate(d, sched.HolidayPolicy, m.isHoliday())
+	switch {
+	case !ok:
+		note += ": skips " + d.Format("Mon Jan 2")
+	case !shifted.Equal(d):
+		note += ": " + d.Format("Jan 2") + " -> " + shifted.Format("Mon Jan 2")
 	}
+	return note
 }
 
 // ordinalSuffix returns the English ordinal suffix for a number (st, nd, rd, th).
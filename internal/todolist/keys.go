@@ -11,10 +11,23 @@ type KeyMap struct {
 	Add            key.Binding
 	Toggle         key.Binding
 	Delete         key.Binding
+	Archive        key.Binding
+	RollOver       key.Binding
 	Edit           key.Binding
 	Filter     key.Binding
 	Preview    key.Binding
 	OpenEditor key.Binding
+	Projects   key.Binding
+	Views      key.Binding
+	Postpone   key.Binding
+	PostponeDay     key.Binding
+	PostponeWeekday key.Binding
+	PostponeWeek    key.Binding
+	PostponeMonth   key.Binding
+	PostponePick    key.Binding
+	Rename     key.Binding
+	Undo       key.Binding
+	Redo       key.Binding
 	Confirm    key.Binding
 	Cancel         key.Binding
 	SwitchField    key.Binding
@@ -23,13 +36,13 @@ type KeyMap struct {
 
 // ShortHelp returns key bindings for the short help view.
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.MoveUp, k.MoveDown, k.Add, k.Toggle, k.Delete, k.Edit, k.Filter, k.Preview, k.OpenEditor, k.SwitchField}
+	return []key.Binding{k.Up, k.Down, k.MoveUp, k.MoveDown, k.Add, k.Toggle, k.Delete, k.Edit, k.Filter, k.Preview, k.OpenEditor, k.Projects, k.Views, k.Postpone, k.SwitchField}
 }
 
 // FullHelp returns key bindings for the full help view.
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.MoveUp, k.MoveDown, k.Add, k.Toggle, k.Delete, k.Edit, k.Filter, k.Preview, k.OpenEditor, k.SwitchField},
+		{k.Up, k.Down, k.MoveUp, k.MoveDown, k.Add, k.Toggle, k.Delete, k.Edit, k.Filter, k.Preview, k.OpenEditor, k.Projects, k.Views, k.Postpone, k.SwitchField},
 	}
 }
 
@@ -64,6 +77,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("d"),
 			key.WithHelp("d", "delete"),
 		),
+		Archive: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "archive done"),
+		),
+		RollOver: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "roll over"),
+		),
 		Edit: key.NewBinding(
 			key.WithKeys("e"),
 			key.WithHelp("e", "edit"),
@@ -80,6 +101,50 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("o"),
 			key.WithHelp("o", "open editor"),
 		),
+		Projects: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "projects"),
+		),
+		Views: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "views"),
+		),
+		Postpone: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "postpone"),
+		),
+		PostponeDay: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "+1 day"),
+		),
+		PostponeWeekday: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "next weekday"),
+		),
+		PostponeWeek: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "next week"),
+		),
+		PostponeMonth: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "next month"),
+		),
+		PostponePick: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pick date"),
+		),
+		Rename: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "rename"),
+		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo"),
+		),
+		Redo: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("C-r", "redo"),
+		),
 		Confirm: key.NewBinding(
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "confirm"),
@@ -11,9 +11,15 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/antti/todo-calendar/internal/checklist"
 	"github.com/antti/todo-calendar/internal/config"
 	"github.com/antti/todo-calendar/internal/fuzzy"
 	"github.com/antti/todo-calendar/internal/google"
+	"github.com/antti/todo-calendar/internal/holidays"
+	"github.com/antti/todo-calendar/internal/postpone"
+	"github.com/antti/todo-calendar/internal/query"
+	"github.com/antti/todo-calendar/internal/recurring"
+	"github.com/antti/todo-calendar/internal/remind"
 	"github.com/antti/todo-calendar/internal/store"
 	"github.com/antti/todo-calendar/internal/theme"
 	"github.com/antti/todo-calendar/internal/tmpl"
@@ -29,6 +35,13 @@ type OpenEditorMsg struct {
 	Todo store.Todo
 }
 
+// UndoneMsg is emitted after an undo or redo. Label names the operation,
+// e.g. "delete", and is empty when there was nothing to undo or redo.
+type UndoneMsg struct {
+	Label string
+	Redo  bool
+}
+
 // mode represents the current input state of the todo list.
 type mode int
 
@@ -37,15 +50,21 @@ const (
 	inputMode       // typing todo text
 	editMode        // editing existing todo (title + date + body)
 	filterMode      // inline filter narrowing visible todos
+	projectMode     // project picker (select, add, rename, delete projects)
+	postponeMode    // choosing how far to postpone the selected todo
 )
 
 // editField constants for the multi-field form.
 const (
 	fieldTitle    = 0
 	fieldDate     = 1
-	fieldPriority = 2
-	fieldBody     = 3
-	fieldTemplate = 4 // inputMode only
+	fieldRemind   = 2
+	fieldRepeat   = 3
+	fieldPriority = 4
+	fieldTags     = 5
+	fieldProject  = 6
+	fieldBody     = 7
+	fieldTemplate = 8 // inputMode only
 )
 
 // itemKind classifies a visible row in the rendered list.
@@ -66,6 +85,8 @@ const (
 	sectionMonth                     // "This Month" fuzzy-date todos
 	sectionYear                      // "This Year" fuzzy-date todos
 	sectionFloating                  // floating (undated) todos
+	sectionSearch                    // todos matching a saved search
+	sectionOverdue                   // incomplete todos dated before today
 )
 
 // visibleItem is a single row in the combined todo list display.
@@ -97,18 +118,27 @@ type Model struct {
 
 	// Full-pane edit fields
 	bodyTextarea  textarea.Model  // textarea for body editing in edit mode
-	editField     int             // 0=title, 1=date, 2=priority, 3=body, 4=template
+	editField     int             // 0=title, 1=date, 2=remind, 3=repeat, 4=priority, 5=tags, 6=project, 7=body, 8=template
 	editPriority  int             // 0=none, 1-4=priority level during editing
+	editProjectID int             // 0=no project, otherwise the project being assigned
+	tagsInput     textinput.Model // comma/space separated tag names
+	remindInput   textinput.Model // reminder offsets before the due time, e.g. "15m, 1h"
+	repeatInput   textinput.Model // repeat rule, e.g. "weekly:mon" or "after:3"
 	templateInput textinput.Model // placeholder input for template field (Phase 25 adds picker)
 
 	// Segmented date input (replaces dateInput)
 	dateSegDay   textinput.Model
 	dateSegMonth textinput.Model
 	dateSegYear  textinput.Model
-	dateSegFocus int    // 0, 1, 2 = which segment is focused (left to right)
+	dateSegFocus int    // 0-2 = date segments (left to right), 3-5 = hour, minute, duration
 	dateSegOrder [3]int // maps visual position to semantic: 0=day, 1=month, 2=year
 	dateFormat   string // "iso", "eu", or "us"
 
+	// Optional time of day and duration, edited as extra segments of the date field
+	timeSegHour   textinput.Model
+	timeSegMinute textinput.Model
+	durationInput textinput.Model
+
 	// Google Calendar events (passed in from app model)
 	calendarEvents []google.CalendarEvent
 
@@ -116,6 +146,9 @@ type Model struct {
 	weekFilterStart string
 	weekFilterEnd   string
 
+	// Saved search shown instead of the month or week sections (nil = none)
+	savedSearch *store.SavedSearch
+
 	// Priority display style ("bars" or "nerd")
 	priorityStyle string
 
@@ -123,6 +156,21 @@ type Model struct {
 	showMonthTodos bool
 	showYearTodos  bool
 
+	// Active project (0 = all projects) and project picker sub-state
+	projectID         int
+	pickerProjects    []store.Project
+	projectCursor     int  // 0 = "All projects", i = pickerProjects[i-1]
+	projectNaming     bool // typing a project name
+	projectRenamingID int  // project being renamed (0 = adding a new one)
+	projectErr        string
+
+	// Postpone menu sub-state
+	postponeID      int  // todo being postponed
+	postponePicking bool // typing the date to postpone to
+	postponeErr     string
+	holidays        *holidays.Provider // holidays skipped by postponed todos, if skipHolidays
+	skipHolidays    bool
+
 	// Template picker sub-state (within inputMode)
 	pickingTemplate         bool
 	pickerTemplates         []store.Template
@@ -160,10 +208,41 @@ func New(s store.TodoStore, t theme.Theme) Model {
 	segYear.Width = 4
 	segYear.Prompt = ""
 
+	segHour := textinput.New()
+	segHour.Placeholder = "hh"
+	segHour.CharLimit = 2
+	segHour.Width = 2
+	segHour.Prompt = ""
+
+	segMinute := textinput.New()
+	segMinute.Placeholder = "mm"
+	segMinute.CharLimit = 2
+	segMinute.Width = 2
+	segMinute.Prompt = ""
+
+	durInput := textinput.New()
+	durInput.Placeholder = "min"
+	durInput.CharLimit = 6
+	durInput.Width = 6
+	durInput.Prompt = ""
+
 	ba := textarea.New()
 	ba.Placeholder = "Body text (markdown supported)"
 	ba.ShowLineNumbers = false
 
+	tagsInput := textinput.New()
+	tagsInput.Placeholder = "work, home"
+	tagsInput.Prompt = "# "
+
+	remindInput := textinput.New()
+	remindInput.Placeholder = "15m, 1h, 1d"
+	remindInput.Prompt = "> "
+
+	repeatInput := textinput.New()
+	repeatInput.Placeholder = "weekly:mon, monthly:1, after:3"
+	repeatInput.Prompt = "> "
+	repeatInput.CharLimit = 200
+
 	tmplInput := textinput.New()
 	tmplInput.Placeholder = "Press Enter to select template"
 	tmplInput.Prompt = "> "
@@ -176,9 +255,15 @@ func New(s store.TodoStore, t theme.Theme) Model {
 		dateSegDay:       segDay,
 		dateSegMonth:     segMonth,
 		dateSegYear:      segYear,
+		timeSegHour:      segHour,
+		timeSegMinute:    segMinute,
+		durationInput:    durInput,
 		dateSegOrder:     dateSegmentOrder("iso"),
 		dateFormat:       "iso",
 		bodyTextarea:     ba,
+		tagsInput:        tagsInput,
+		remindInput:      remindInput,
+		repeatInput:      repeatInput,
 		templateInput:    tmplInput,
 		viewYear:         now.Year(),
 		viewMonth:        now.Month(),
@@ -263,6 +348,10 @@ func (m Model) HelpBindings() []key.Binding {
 		return []key.Binding{m.keys.SwitchField, m.keys.Confirm, m.keys.Cancel}
 	case normalMode:
 		return []key.Binding{m.keys.Add, m.keys.Toggle, m.keys.Delete, m.keys.Edit, m.keys.Filter}
+	case projectMode:
+		return m.projectHelpBindings()
+	case postponeMode:
+		return m.postponeHelpBindings()
 	default:
 		return []key.Binding{m.keys.Confirm, m.keys.Cancel}
 	}
@@ -292,9 +381,12 @@ func (m Model) AllHelpBindings() []key.Binding {
 		return []key.Binding{
 			m.keys.Up, m.keys.Down, m.keys.MoveUp, m.keys.MoveDown,
 			m.keys.Add, m.keys.Edit,
-			m.keys.Toggle, m.keys.Delete, m.keys.Filter,
-			m.keys.Preview, m.keys.OpenEditor,
+			m.keys.Toggle, m.keys.Delete, m.keys.Archive, m.keys.RollOver, m.keys.Filter,
+			m.keys.Preview, m.keys.OpenEditor, m.keys.Projects, m.keys.Views, m.keys.Postpone,
+			m.keys.Undo, m.keys.Redo,
 		}
+	case projectMode:
+		return m.projectHelpBindings()
 	default:
 		return []key.Binding{m.keys.Confirm, m.keys.Cancel}
 	}
@@ -302,8 +394,24 @@ func (m Model) AllHelpBindings() []key.Binding {
 
 // visibleItems builds the combined display list of headers, todos, and empty placeholders.
 func (m Model) visibleItems() []visibleItem {
+	if m.savedSearch != nil {
+		return m.filterItems(m.savedSearchItems())
+	}
+
 	var items []visibleItem
 
+	// Overdue section: incomplete todos from before today, whatever the
+	// viewed month or week, shown only when there are any. The sections
+	// below leave them out.
+	overdueIDs := make(map[int]bool)
+	if overdue := m.inProject(m.store.OverdueTodos(time.Now().Format("2006-01-02"))); len(overdue) > 0 {
+		items = append(items, visibleItem{kind: headerItem, label: "Overdue", section: sectionOverdue})
+		for i := range overdue {
+			overdueIDs[overdue[i].ID] = true
+			items = append(items, visibleItem{kind: todoItem, todo: &overdue[i], section: sectionOverdue})
+		}
+	}
+
 	// Expand multi-day events once for use in both branches
 	expandedEvents := google.ExpandMultiDay(m.calendarEvents)
 
@@ -325,7 +433,7 @@ func (m Model) visibleItems() []visibleItem {
 				mixed = append(mixed, visibleItem{kind: eventItem, event: e, section: sectionDated})
 			}
 		}
-		dated := m.store.TodosForDateRange(m.weekFilterStart, m.weekFilterEnd)
+		dated := without(m.inProject(m.store.TodosForDateRange(m.weekFilterStart, m.weekFilterEnd)), overdueIDs)
 		for i := range dated {
 			mixed = append(mixed, visibleItem{kind: todoItem, todo: &dated[i], section: sectionDated})
 		}
@@ -350,7 +458,7 @@ func (m Model) visibleItems() []visibleItem {
 				}
 			}
 		}
-		dated := m.store.TodosForMonth(m.viewYear, m.viewMonth)
+		dated := without(m.inProject(m.store.TodosForMonth(m.viewYear, m.viewMonth)), overdueIDs)
 		for i := range dated {
 			mixed = append(mixed, visibleItem{kind: todoItem, todo: &dated[i], section: sectionDated})
 		}
@@ -367,7 +475,7 @@ func (m Model) visibleItems() []visibleItem {
 		if m.showMonthTodos {
 			// This Month section
 			items = append(items, visibleItem{kind: headerItem, label: "This Month", section: sectionMonth})
-			monthTodos := m.store.MonthTodos(m.viewYear, m.viewMonth)
+			monthTodos := without(m.inProject(m.store.MonthTodos(m.viewYear, m.viewMonth)), overdueIDs)
 			if len(monthTodos) == 0 {
 				items = append(items, visibleItem{kind: emptyItem, label: "(no month todos)", section: sectionMonth})
 			} else {
@@ -380,7 +488,7 @@ func (m Model) visibleItems() []visibleItem {
 		if m.showYearTodos {
 			// This Year section
 			items = append(items, visibleItem{kind: headerItem, label: "This Year", section: sectionYear})
-			yearTodos := m.store.YearTodos(m.viewYear)
+			yearTodos := without(m.inProject(m.store.YearTodos(m.viewYear)), overdueIDs)
 			if len(yearTodos) == 0 {
 				items = append(items, visibleItem{kind: emptyItem, label: "(no year todos)", section: sectionYear})
 			} else {
@@ -395,7 +503,7 @@ func (m Model) visibleItems() []visibleItem {
 	items = append(items, visibleItem{kind: headerItem, label: "Floating", section: sectionFloating})
 
 	// Floating todos
-	floating := m.store.FloatingTodos()
+	floating := m.inProject(m.store.FloatingTodos())
 	if len(floating) == 0 {
 		items = append(items, visibleItem{kind: emptyItem, label: "(no floating todos)", section: sectionFloating})
 	} else {
@@ -404,15 +512,58 @@ func (m Model) visibleItems() []visibleItem {
 		}
 	}
 
-	// Apply inline filter when active
+	return m.filterItems(items)
+}
+
+// without returns todos less the ones whose IDs are in ids.
+func without(todos []store.Todo, ids map[int]bool) []store.Todo {
+	if len(ids) == 0 {
+		return todos
+	}
+	var kept []store.Todo
+	for _, t := range todos {
+		if !ids[t.ID] {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// savedSearchItems lists the todos matching the active saved search.
+func (m Model) savedSearchItems() []visibleItem {
+	items := []visibleItem{{kind: headerItem, label: m.savedSearch.Name, section: sectionSearch}}
+	var todos []store.Todo
+	if q, err := query.Parse(m.savedSearch.Query, time.Now()); err == nil {
+		for _, r := range m.store.QueryTodos(q) {
+			todos = append(todos, r.Todo)
+		}
+	}
+	todos = m.inProject(todos)
+	if len(todos) == 0 {
+		return append(items, visibleItem{kind: emptyItem, label: "(no matching todos)", section: sectionSearch})
+	}
+	for i := range todos {
+		items = append(items, visibleItem{kind: todoItem, todo: &todos[i], section: sectionSearch})
+	}
+	return items
+}
+
+// filterItems applies the inline filter, if active, to the display list.
+// "#tag" tokens require the tag; the remaining text is fuzzy-matched
+// against the todo text.
+func (m Model) filterItems(items []visibleItem) []visibleItem {
 	if m.filterQuery != "" {
+		text, tags := store.SplitTagQuery(m.filterQuery)
 		var filtered []visibleItem
 		for _, item := range items {
 			switch item.kind {
 			case headerItem:
 				filtered = append(filtered, item)
 			case todoItem:
-				if matched, _ := fuzzy.Match(m.filterQuery, item.todo.Text); matched {
+				if !item.todo.HasAllTags(tags) {
+					continue
+				}
+				if matched, _ := fuzzy.Match(text, item.todo.Text); matched {
 					filtered = append(filtered, item)
 				}
 			// Skip emptyItem and eventItem entries during filtering
@@ -436,6 +587,21 @@ func (m Model) visibleItems() []visibleItem {
 	return items
 }
 
+// inProject returns the todos belonging to the active project,
+// or all todos when no project is selected.
+func (m Model) inProject(todos []store.Todo) []store.Todo {
+	if m.projectID == 0 {
+		return todos
+	}
+	var result []store.Todo
+	for _, t := range todos {
+		if t.ProjectID == m.projectID {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
 // sortVisibleByDate sorts a mixed slice of event and todo visible items by date.
 // Events on the same date as a todo sort before the todo; events sort by start time.
 func sortVisibleByDate(items []visibleItem) {
@@ -445,22 +611,40 @@ func sortVisibleByDate(items []visibleItem) {
 		if di != dj {
 			return di < dj
 		}
-		// Same date: events before todos
+		// Same date: untimed items (all-day events, todos without a time)
+		// first, then timed events and todos interleaved chronologically
+		ti := itemTime(items[i])
+		tj := itemTime(items[j])
+		if ti != tj {
+			return ti < tj
+		}
+		// Same time: events before todos
 		if items[i].kind != items[j].kind {
 			return items[i].kind == eventItem
 		}
-		// Both events on same date: sort by start time
-		if items[i].kind == eventItem && items[j].kind == eventItem {
-			ei, ej := items[i].event, items[j].event
-			if ei.AllDay != ej.AllDay {
-				return ei.AllDay
-			}
-			return ei.Start.Before(ej.Start)
+		// Both timed events in the same minute: sort by exact start
+		if items[i].kind == eventItem && ti != "" {
+			return items[i].event.Start.Before(items[j].event.Start)
 		}
 		return false
 	})
 }
 
+// itemTime returns the "HH:MM" start time of a visible item, or "" for
+// all-day events and untimed todos.
+func itemTime(item visibleItem) string {
+	switch item.kind {
+	case eventItem:
+		if item.event.AllDay {
+			return ""
+		}
+		return item.event.Start.Format("15:04")
+	case todoItem:
+		return item.todo.StartTime
+	}
+	return ""
+}
+
 // itemDate returns the date string for a visible item (event or todo).
 func itemDate(item visibleItem) string {
 	switch item.kind {
@@ -487,6 +671,18 @@ func selectableIndices(items []visibleItem) []int {
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	// Forward blink/tick messages to focused text input in edit modes.
 	switch m.mode {
+	case projectMode:
+		if _, ok := msg.(tea.KeyMsg); !ok && m.projectNaming {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+	case postponeMode:
+		if _, ok := msg.(tea.KeyMsg); !ok && m.postponePicking {
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
 	case inputMode, editMode:
 		if _, ok := msg.(tea.KeyMsg); !ok {
 			if _, ok := msg.(tea.WindowSizeMsg); !ok {
@@ -504,8 +700,14 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				case fieldDate:
 					seg := m.dateSegmentByPos(m.dateSegFocus)
 					*seg, cmd = seg.Update(msg)
-				case fieldPriority:
+				case fieldPriority, fieldProject:
 					// no-op, no widget to forward to
+				case fieldTags:
+					m.tagsInput, cmd = m.tagsInput.Update(msg)
+				case fieldRemind:
+					m.remindInput, cmd = m.remindInput.Update(msg)
+				case fieldRepeat:
+					m.repeatInput, cmd = m.repeatInput.Update(msg)
 				default:
 					m.input, cmd = m.input.Update(msg)
 				}
@@ -527,6 +729,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m.updateEditMode(msg)
 		case filterMode:
 			return m.updateFilterMode(msg)
+		case projectMode:
+			return m.updateProjectMode(msg)
+		case postponeMode:
+			return m.updatePostponeMode(msg)
 		default:
 			return m.updateNormalMode(msg)
 		}
@@ -558,7 +764,7 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			curItem := items[curIdx]
 			prevItem := items[prevIdx]
 			if curItem.todo != nil && prevItem.todo != nil &&
-				curItem.section == prevItem.section {
+				curItem.section == prevItem.section && curItem.section != sectionSearch && curItem.section != sectionOverdue {
 				m.store.SwapOrder(curItem.todo.ID, prevItem.todo.ID)
 				m.cursor--
 			}
@@ -571,7 +777,7 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			curItem := items[curIdx]
 			nextItem := items[nextIdx]
 			if curItem.todo != nil && nextItem.todo != nil &&
-				curItem.section == nextItem.section {
+				curItem.section == nextItem.section && curItem.section != sectionSearch && curItem.section != sectionOverdue {
 				m.store.SwapOrder(curItem.todo.ID, nextItem.todo.ID)
 				m.cursor++
 			}
@@ -581,19 +787,23 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.mode = inputMode
 		m.editField = fieldTitle
 		m.editPriority = 0
+		m.editProjectID = m.projectID
 		m.input.Placeholder = "What needs doing?"
 		m.input.Prompt = "> "
 		m.input.SetValue("")
 		m.clearAllDateSegments()
 		m.blurAllDateSegments()
 		m.bodyTextarea.SetValue("")
+		m.tagsInput.SetValue("")
+		m.remindInput.SetValue("")
+		m.repeatInput.SetValue("")
 		return m, m.input.Focus()
 
 	case key.Matches(msg, m.keys.Toggle):
 		if len(selectable) > 0 && m.cursor < len(selectable) {
 			idx := selectable[m.cursor]
 			if items[idx].todo != nil {
-				m.store.Toggle(items[idx].todo.ID)
+				recurring.Toggle(m.store, items[idx].todo.ID, time.Now())
 			}
 		}
 
@@ -610,6 +820,35 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			}
 		}
 
+	case key.Matches(msg, m.keys.Archive):
+		// Archive every completed todo in view as one undoable operation.
+		m.store.Batch("archive", func() {
+			for _, item := range items {
+				if item.todo != nil && item.todo.Done {
+					m.store.Archive(item.todo.ID)
+				}
+			}
+		})
+		newSelectable := selectableIndices(m.visibleItems())
+		if m.cursor >= len(newSelectable) {
+			m.cursor = max(0, len(newSelectable)-1)
+		}
+
+	case key.Matches(msg, m.keys.RollOver):
+		// Move every overdue todo in view to today as one undoable operation.
+		today := time.Now().Format("2006-01-02")
+		m.store.Batch("rollover", func() {
+			for _, item := range items {
+				if item.section == sectionOverdue && item.todo != nil {
+					m.store.RollOver(item.todo.ID, today)
+				}
+			}
+		})
+		newSelectable := selectableIndices(m.visibleItems())
+		if m.cursor >= len(newSelectable) {
+			m.cursor = max(0, len(newSelectable)-1)
+		}
+
 	case key.Matches(msg, m.keys.Edit):
 		if len(selectable) > 0 && m.cursor < len(selectable) {
 			todo := items[selectable[m.cursor]].todo
@@ -622,6 +861,7 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.mode = editMode
 			m.editField = fieldTitle
 			m.editPriority = fresh.Priority
+			m.editProjectID = fresh.ProjectID
 			m.input.Placeholder = "Todo title"
 			m.input.Prompt = "> "
 			m.input.SetValue(fresh.Text)
@@ -645,14 +885,38 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 					}
 				}
 			}
+			if hh, mm, ok := strings.Cut(fresh.StartTime, ":"); ok {
+				m.timeSegHour.SetValue(hh)
+				m.timeSegMinute.SetValue(mm)
+				m.durationInput.SetValue(store.FormatDuration(fresh.Duration))
+			}
 			m.bodyTextarea.SetValue(fresh.Body)
+			m.tagsInput.SetValue(strings.Join(fresh.Tags, ", "))
+			m.remindInput.SetValue(remind.FormatOffsets(fresh.Reminders))
+			m.repeatInput.SetValue(fresh.Repeat)
 			return m, m.input.Focus()
 		}
 
+	case key.Matches(msg, m.keys.Projects):
+		return m.openProjectPicker(), nil
+
+	case key.Matches(msg, m.keys.Views):
+		m.cycleSavedSearch()
+
+	case key.Matches(msg, m.keys.Postpone):
+		if len(selectable) > 0 && m.cursor < len(selectable) {
+			if todo := items[selectable[m.cursor]].todo; todo != nil && !todo.Done {
+				m.mode = postponeMode
+				m.postponeID = todo.ID
+				m.postponePicking = false
+				m.postponeErr = ""
+			}
+		}
+
 	case key.Matches(msg, m.keys.Filter):
 		m.mode = filterMode
 		m.filterQuery = ""
-		m.input.Placeholder = "Filter todos..."
+		m.input.Placeholder = "Filter todos (#tag narrows by tag)..."
 		m.input.Prompt = "/ "
 		m.input.SetValue("")
 		return m, m.input.Focus()
@@ -679,11 +943,39 @@ func (m Model) updateNormalMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			}
 		}
 
+	case key.Matches(msg, m.keys.Undo):
+		return m, m.Undo()
+
+	case key.Matches(msg, m.keys.Redo):
+		return m, m.Redo()
 	}
 
 	return m, nil
 }
 
+// Undo reverts the most recent todo mutation and returns a command that
+// reports it with an UndoneMsg.
+func (m *Model) Undo() tea.Cmd {
+	label, _ := m.store.Undo()
+	return m.afterUndo(label, false)
+}
+
+// Redo re-applies the most recently undone todo mutation and returns a
+// command that reports it with an UndoneMsg.
+func (m *Model) Redo() tea.Cmd {
+	label, _ := m.store.Redo()
+	return m.afterUndo(label, true)
+}
+
+// afterUndo clamps the cursor to the changed list and builds the UndoneMsg.
+func (m *Model) afterUndo(label string, redo bool) tea.Cmd {
+	selectable := selectableIndices(m.visibleItems())
+	if m.cursor >= len(selectable) {
+		m.cursor = max(0, len(selectable)-1)
+	}
+	return func() tea.Msg { return UndoneMsg{Label: label, Redo: redo} }
+}
+
 // updateFilterMode handles key events in filter mode.
 func (m Model) updateFilterMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch {
@@ -712,7 +1004,7 @@ func (m Model) updateFilterMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, cmd
 }
 
-// updateInputMode handles key events in the 5-field add form (title, date, priority, body, template).
+// updateInputMode handles key events in the 7-field add form (title, date, priority, tags, project, body, template).
 func (m Model) updateInputMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 	// Template picker sub-states intercept all keys
 	if m.pickingTemplate {
@@ -735,6 +1027,18 @@ func (m Model) updateInputMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 	}
 
+	// Project field cycles through "none" and the existing projects
+	if m.editField == fieldProject {
+		switch msg.String() {
+		case "left":
+			m.editProjectID = m.cycleProject(-1)
+			return m, nil
+		case "right":
+			m.editProjectID = m.cycleProject(1)
+			return m, nil
+		}
+	}
+
 	// Up/down arrows navigate between fields; in body textarea, only at boundaries
 	{
 		k := msg.String()
@@ -782,22 +1086,37 @@ func (m Model) updateInputMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m.saveAdd()
 
 	case key.Matches(msg, m.keys.SwitchField):
-		// Cycle: title(0) -> date(1) -> priority(2) -> body(3) -> template(4) -> title(0)
+		// Cycle: title -> date -> remind -> repeat -> priority -> tags -> project -> body -> template -> title
 		switch m.editField {
 		case fieldTitle:
 			m.editField = fieldDate
 			m.input.Blur()
 			return m, m.focusDateSegment(0)
 		case fieldDate:
-			if m.dateSegFocus < 2 {
+			if m.dateSegFocus < segDuration {
 				// Advance to next date segment
 				return m, m.focusDateSegment(m.dateSegFocus + 1)
 			}
-			// Past last segment -> priority
-			m.editField = fieldPriority
+			// Past last segment -> reminders
+			m.editField = fieldRemind
 			m.blurAllDateSegments()
+			return m, m.remindInput.Focus()
+		case fieldRemind:
+			m.editField = fieldRepeat
+			m.remindInput.Blur()
+			return m, m.repeatInput.Focus()
+		case fieldRepeat:
+			m.editField = fieldPriority
+			m.repeatInput.Blur()
 			return m, nil
 		case fieldPriority:
+			m.editField = fieldTags
+			return m, m.tagsInput.Focus()
+		case fieldTags:
+			m.editField = fieldProject
+			m.tagsInput.Blur()
+			return m, nil
+		case fieldProject:
 			m.editField = fieldBody
 			return m, m.bodyTextarea.Focus()
 		case fieldBody:
@@ -826,14 +1145,20 @@ func (m Model) updateInputMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.pickerPlaceholderValues = nil
 			return m, m.input.Focus()
 		}
-		// Esc in title/date/priority cancels entirely
+		// Esc in title/date/priority/tags cancels entirely
 		m.mode = normalMode
 		m.input.Blur()
 		m.blurAllDateSegments()
+		m.tagsInput.Blur()
+		m.remindInput.Blur()
+		m.repeatInput.Blur()
 		m.bodyTextarea.Blur()
 		m.templateInput.Blur()
 		m.input.SetValue("")
 		m.clearAllDateSegments()
+		m.tagsInput.SetValue("")
+		m.remindInput.SetValue("")
+		m.repeatInput.SetValue("")
 		m.bodyTextarea.SetValue("")
 		m.templateInput.SetValue("")
 		m.pickingTemplate = false
@@ -854,8 +1179,14 @@ func (m Model) updateInputMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.input, cmd = m.input.Update(msg)
 	case fieldDate:
 		return m.updateDateSegment(msg)
-	case fieldPriority:
+	case fieldPriority, fieldProject:
 		// no-op, handled above
+	case fieldTags:
+		m.tagsInput, cmd = m.tagsInput.Update(msg)
+	case fieldRemind:
+		m.remindInput, cmd = m.remindInput.Update(msg)
+	case fieldRepeat:
+		m.repeatInput, cmd = m.repeatInput.Update(msg)
 	case fieldBody:
 		m.bodyTextarea, cmd = m.bodyTextarea.Update(msg)
 	case fieldTemplate:
@@ -864,7 +1195,7 @@ func (m Model) updateInputMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, cmd
 }
 
-// updateEditMode handles key events while editing an existing todo (title + date + priority + body).
+// updateEditMode handles key events while editing an existing todo (title + date + priority + tags + project + body).
 func (m Model) updateEditMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 	// Handle priority field left/right BEFORE other key matching
 	if m.editField == fieldPriority {
@@ -879,6 +1210,18 @@ func (m Model) updateEditMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		}
 	}
 
+	// Project field cycles through "none" and the existing projects
+	if m.editField == fieldProject {
+		switch msg.String() {
+		case "left":
+			m.editProjectID = m.cycleProject(-1)
+			return m, nil
+		case "right":
+			m.editProjectID = m.cycleProject(1)
+			return m, nil
+		}
+	}
+
 	// Up/down arrows navigate between fields; in body textarea, only at boundaries
 	{
 		k := msg.String()
@@ -916,20 +1259,35 @@ func (m Model) updateEditMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m.saveEdit()
 
 	case key.Matches(msg, m.keys.SwitchField):
-		// Cycle: title(0) -> date(1) -> priority(2) -> body(3) -> title(0)
+		// Cycle: title -> date -> remind -> repeat -> priority -> tags -> project -> body -> title
 		switch m.editField {
 		case fieldTitle:
 			m.editField = fieldDate
 			m.input.Blur()
 			return m, m.focusDateSegment(0)
 		case fieldDate:
-			if m.dateSegFocus < 2 {
+			if m.dateSegFocus < segDuration {
 				return m, m.focusDateSegment(m.dateSegFocus + 1)
 			}
-			m.editField = fieldPriority
+			m.editField = fieldRemind
 			m.blurAllDateSegments()
+			return m, m.remindInput.Focus()
+		case fieldRemind:
+			m.editField = fieldRepeat
+			m.remindInput.Blur()
+			return m, m.repeatInput.Focus()
+		case fieldRepeat:
+			m.editField = fieldPriority
+			m.repeatInput.Blur()
 			return m, nil
 		case fieldPriority:
+			m.editField = fieldTags
+			return m, m.tagsInput.Focus()
+		case fieldTags:
+			m.editField = fieldProject
+			m.tagsInput.Blur()
+			return m, nil
+		case fieldProject:
 			m.editField = fieldBody
 			return m, m.bodyTextarea.Focus()
 		case fieldBody:
@@ -949,9 +1307,15 @@ func (m Model) updateEditMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.mode = normalMode
 		m.input.Blur()
 		m.blurAllDateSegments()
+		m.tagsInput.Blur()
+		m.remindInput.Blur()
+		m.repeatInput.Blur()
 		m.bodyTextarea.Blur()
 		m.input.SetValue("")
 		m.clearAllDateSegments()
+		m.tagsInput.SetValue("")
+		m.remindInput.SetValue("")
+		m.repeatInput.SetValue("")
 		m.bodyTextarea.SetValue("")
 		m.editField = fieldTitle
 		m.editPriority = 0
@@ -965,15 +1329,21 @@ func (m Model) updateEditMode(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.input, cmd = m.input.Update(msg)
 	case fieldDate:
 		return m.updateDateSegment(msg)
-	case fieldPriority:
+	case fieldPriority, fieldProject:
 		// no-op, handled above
+	case fieldTags:
+		m.tagsInput, cmd = m.tagsInput.Update(msg)
+	case fieldRemind:
+		m.remindInput, cmd = m.remindInput.Update(msg)
+	case fieldRepeat:
+		m.repeatInput, cmd = m.repeatInput.Update(msg)
 	case fieldBody:
 		m.bodyTextarea, cmd = m.bodyTextarea.Update(msg)
 	}
 	return m, cmd
 }
 
-// editNextField moves focus to the next edit field (title→date→priority→body→title).
+// editNextField moves focus to the next edit field (title→date→remind→repeat→priority→tags→project→body→title).
 func (m Model) editNextField() (Model, tea.Cmd) {
 	switch m.editField {
 	case fieldTitle:
@@ -981,10 +1351,25 @@ func (m Model) editNextField() (Model, tea.Cmd) {
 		m.input.Blur()
 		return m, m.focusDateSegment(0)
 	case fieldDate:
-		m.editField = fieldPriority
+		m.editField = fieldRemind
 		m.blurAllDateSegments()
+		return m, m.remindInput.Focus()
+	case fieldRemind:
+		m.editField = fieldRepeat
+		m.remindInput.Blur()
+		return m, m.repeatInput.Focus()
+	case fieldRepeat:
+		m.editField = fieldPriority
+		m.repeatInput.Blur()
 		return m, nil
 	case fieldPriority:
+		m.editField = fieldTags
+		return m, m.tagsInput.Focus()
+	case fieldTags:
+		m.editField = fieldProject
+		m.tagsInput.Blur()
+		return m, nil
+	case fieldProject:
 		m.editField = fieldBody
 		return m, m.bodyTextarea.Focus()
 	case fieldBody:
@@ -995,7 +1380,7 @@ func (m Model) editNextField() (Model, tea.Cmd) {
 	return m, nil
 }
 
-// editPrevField moves focus to the previous edit field (title←date←priority←body).
+// editPrevField moves focus to the previous edit field (title←date←remind←repeat←priority←tags←project←body).
 func (m Model) editPrevField() (Model, tea.Cmd) {
 	switch m.editField {
 	case fieldTitle:
@@ -1006,11 +1391,26 @@ func (m Model) editPrevField() (Model, tea.Cmd) {
 		m.editField = fieldTitle
 		m.blurAllDateSegments()
 		return m, m.input.Focus()
-	case fieldPriority:
+	case fieldRemind:
 		m.editField = fieldDate
+		m.remindInput.Blur()
 		return m, m.focusDateSegment(0)
-	case fieldBody:
+	case fieldRepeat:
+		m.editField = fieldRemind
+		m.repeatInput.Blur()
+		return m, m.remindInput.Focus()
+	case fieldPriority:
+		m.editField = fieldRepeat
+		return m, m.repeatInput.Focus()
+	case fieldTags:
 		m.editField = fieldPriority
+		m.tagsInput.Blur()
+		return m, nil
+	case fieldProject:
+		m.editField = fieldTags
+		return m, m.tagsInput.Focus()
+	case fieldBody:
+		m.editField = fieldProject
 		m.bodyTextarea.Blur()
 		return m, nil
 	}
@@ -1018,7 +1418,7 @@ func (m Model) editPrevField() (Model, tea.Cmd) {
 }
 
 // inputNextField moves focus to the next field in input (add) mode.
-// Cycle: title→date→priority→body→template→title
+// Cycle: title→date→remind→repeat→priority→tags→project→body→template→title
 func (m Model) inputNextField() (Model, tea.Cmd) {
 	switch m.editField {
 	case fieldTitle:
@@ -1026,10 +1426,25 @@ func (m Model) inputNextField() (Model, tea.Cmd) {
 		m.input.Blur()
 		return m, m.focusDateSegment(0)
 	case fieldDate:
-		m.editField = fieldPriority
+		m.editField = fieldRemind
 		m.blurAllDateSegments()
+		return m, m.remindInput.Focus()
+	case fieldRemind:
+		m.editField = fieldRepeat
+		m.remindInput.Blur()
+		return m, m.repeatInput.Focus()
+	case fieldRepeat:
+		m.editField = fieldPriority
+		m.repeatInput.Blur()
 		return m, nil
 	case fieldPriority:
+		m.editField = fieldTags
+		return m, m.tagsInput.Focus()
+	case fieldTags:
+		m.editField = fieldProject
+		m.tagsInput.Blur()
+		return m, nil
+	case fieldProject:
 		m.editField = fieldBody
 		return m, m.bodyTextarea.Focus()
 	case fieldBody:
@@ -1055,11 +1470,26 @@ func (m Model) inputPrevField() (Model, tea.Cmd) {
 		m.editField = fieldTitle
 		m.blurAllDateSegments()
 		return m, m.input.Focus()
-	case fieldPriority:
+	case fieldRemind:
 		m.editField = fieldDate
+		m.remindInput.Blur()
 		return m, m.focusDateSegment(0)
-	case fieldBody:
+	case fieldRepeat:
+		m.editField = fieldRemind
+		m.repeatInput.Blur()
+		return m, m.remindInput.Focus()
+	case fieldPriority:
+		m.editField = fieldRepeat
+		return m, m.repeatInput.Focus()
+	case fieldTags:
 		m.editField = fieldPriority
+		m.tagsInput.Blur()
+		return m, nil
+	case fieldProject:
+		m.editField = fieldTags
+		return m, m.tagsInput.Focus()
+	case fieldBody:
+		m.editField = fieldProject
 		m.bodyTextarea.Blur()
 		return m, nil
 	case fieldTemplate:
@@ -1086,17 +1516,56 @@ func (m Model) saveEdit() (Model, tea.Cmd) {
 		return m, m.focusDateSegment(errPos)
 	}
 
+	startTime, duration, errPos := m.deriveTimeFromSegments(precision)
+	if errPos >= 0 {
+		m.editField = fieldDate
+		m.input.Blur()
+		m.bodyTextarea.Blur()
+		return m, m.focusDateSegment(errPos)
+	}
+
+	offsets, ok := m.deriveReminders(precision)
+	if !ok {
+		m.editField = fieldRemind
+		m.input.Blur()
+		m.bodyTextarea.Blur()
+		m.blurAllDateSegments()
+		return m, m.remindInput.Focus()
+	}
+
+	repeat, ok := m.deriveRepeat(isoDate, precision)
+	if !ok {
+		m.editField = fieldRepeat
+		m.input.Blur()
+		m.bodyTextarea.Blur()
+		m.blurAllDateSegments()
+		return m, m.repeatInput.Focus()
+	}
+
 	body := m.bodyTextarea.Value()
 
-	m.store.Update(m.editingID, text, isoDate, precision, m.editPriority)
-	m.store.UpdateBody(m.editingID, body)
+	m.store.Batch("edit", func() {
+		m.store.Update(m.editingID, text, isoDate, precision, m.editPriority)
+		m.store.SetTime(m.editingID, startTime, duration)
+		m.store.SetReminders(m.editingID, offsets)
+		m.store.SetRepeat(m.editingID, repeat)
+		m.store.UpdateBody(m.editingID, body)
+		m.store.SetTags(m.editingID, store.ParseTags(m.tagsInput.Value()))
+		m.store.SetProject(m.editingID, m.editProjectID)
+	})
 
 	m.mode = normalMode
 	m.input.Blur()
 	m.blurAllDateSegments()
+	m.tagsInput.Blur()
+	m.remindInput.Blur()
+	m.repeatInput.Blur()
 	m.bodyTextarea.Blur()
 	m.input.SetValue("")
 	m.clearAllDateSegments()
+	m.tagsInput.SetValue("")
+	m.remindInput.SetValue("")
+	m.repeatInput.SetValue("")
 	m.bodyTextarea.SetValue("")
 	m.editField = fieldTitle
 	m.editPriority = 0
@@ -1126,20 +1595,71 @@ func (m Model) saveAdd() (Model, tea.Cmd) {
 		return m, m.focusDateSegment(errPos)
 	}
 
-	todo := m.store.Add(text, isoDate, precision, m.editPriority)
+	startTime, duration, errPos := m.deriveTimeFromSegments(precision)
+	if errPos >= 0 {
+		m.editField = fieldDate
+		m.input.Blur()
+		m.bodyTextarea.Blur()
+		m.templateInput.Blur()
+		return m, m.focusDateSegment(errPos)
+	}
 
-	body := m.bodyTextarea.Value()
-	if strings.TrimSpace(body) != "" {
-		m.store.UpdateBody(todo.ID, body)
+	offsets, ok := m.deriveReminders(precision)
+	if !ok {
+		m.editField = fieldRemind
+		m.input.Blur()
+		m.bodyTextarea.Blur()
+		m.templateInput.Blur()
+		m.blurAllDateSegments()
+		return m, m.remindInput.Focus()
 	}
 
+	repeat, ok := m.deriveRepeat(isoDate, precision)
+	if !ok {
+		m.editField = fieldRepeat
+		m.input.Blur()
+		m.bodyTextarea.Blur()
+		m.templateInput.Blur()
+		m.blurAllDateSegments()
+		return m, m.repeatInput.Focus()
+	}
+
+	body := m.bodyTextarea.Value()
+	m.store.Batch("add", func() {
+		todo := m.store.Add(text, isoDate, precision, m.editPriority)
+		if startTime != "" {
+			m.store.SetTime(todo.ID, startTime, duration)
+		}
+		if len(offsets) > 0 {
+			m.store.SetReminders(todo.ID, offsets)
+		}
+		if repeat != "" {
+			m.store.SetRepeat(todo.ID, repeat)
+		}
+		if strings.TrimSpace(body) != "" {
+			m.store.UpdateBody(todo.ID, body)
+		}
+		if tags := store.ParseTags(m.tagsInput.Value()); len(tags) > 0 {
+			m.store.SetTags(todo.ID, tags)
+		}
+		if m.editProjectID != 0 {
+			m.store.SetProject(todo.ID, m.editProjectID)
+		}
+	})
+
 	m.mode = normalMode
 	m.input.Blur()
 	m.blurAllDateSegments()
+	m.tagsInput.Blur()
+	m.remindInput.Blur()
+	m.repeatInput.Blur()
 	m.bodyTextarea.Blur()
 	m.templateInput.Blur()
 	m.input.SetValue("")
 	m.clearAllDateSegments()
+	m.tagsInput.SetValue("")
+	m.remindInput.SetValue("")
+	m.repeatInput.SetValue("")
 	m.bodyTextarea.SetValue("")
 	m.templateInput.SetValue("")
 	m.pickingTemplate = false
@@ -1150,9 +1670,41 @@ func (m Model) saveAdd() (Model, tea.Cmd) {
 	m.pickerPlaceholderValues = nil
 	m.editField = fieldTitle
 	m.editPriority = 0
+	m.editProjectID = 0
 	return m, nil
 }
 
+// cycleProject returns the project ID dir steps away from editProjectID in
+// the list "no project", then the projects by name, wrapping around.
+func (m Model) cycleProject(dir int) int {
+	options := []int{0}
+	for _, p := range m.store.ListProjects() {
+		options = append(options, p.ID)
+	}
+	cur := 0
+	for i, id := range options {
+		if id == m.editProjectID {
+			cur = i
+		}
+	}
+	next := (cur + dir + len(options)) % len(options)
+	return options[next]
+}
+
+// renderProjectSelector renders the inline project selector for the edit form.
+func (m Model) renderProjectSelector() string {
+	name := "(none)"
+	if m.editProjectID != 0 {
+		if p := m.store.FindProject(m.editProjectID); p != nil {
+			name = p.Name
+		}
+	}
+	prefix := "  "
+	if m.editField == fieldProject {
+		prefix = m.styles.Cursor.Render("> ")
+	}
+	return prefix + m.styles.EditHint.Render("< ") + name + m.styles.EditHint.Render(" >")
+}
 
 // renderPrioritySelector renders the inline priority selector for the edit form.
 func (m Model) renderPrioritySelector() string {
@@ -1185,6 +1737,8 @@ func (m Model) View() string {
 	switch m.mode {
 	case inputMode, editMode:
 		return m.editView()
+	case projectMode:
+		return m.projectView()
 	default:
 		return m.normalView()
 	}
@@ -1208,7 +1762,7 @@ func (m Model) editView() string {
 	// Field(s)
 	switch m.mode {
 	case editMode:
-		// Four fields: Title, Date (segmented), Priority, Body
+		// Eight fields: Title, Date (segmented), Remind, Repeat, Priority, Tags, Project, Body
 		b.WriteString(m.styles.FieldLabel.Render("Title"))
 		b.WriteString("\n")
 		b.WriteString(m.input.View())
@@ -1217,12 +1771,28 @@ func (m Model) editView() string {
 		b.WriteString("\n")
 		b.WriteString(m.renderDateSegments())
 		b.WriteString("  ")
-		b.WriteString(m.styles.EditHint.Render("(t = today)"))
+		b.WriteString(m.styles.EditHint.Render("(t = today, time and duration optional)"))
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.FieldLabel.Render("Remind"))
+		b.WriteString("\n")
+		b.WriteString(m.remindInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.FieldLabel.Render("Repeat"))
+		b.WriteString("\n")
+		b.WriteString(m.repeatInput.View())
 		b.WriteString("\n\n")
 		b.WriteString(m.styles.FieldLabel.Render("Priority"))
 		b.WriteString("\n")
 		b.WriteString(m.renderPrioritySelector())
 		b.WriteString("\n\n")
+		b.WriteString(m.styles.FieldLabel.Render("Tags"))
+		b.WriteString("\n")
+		b.WriteString(m.tagsInput.View())
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.FieldLabel.Render("Project"))
+		b.WriteString("\n")
+		b.WriteString(m.renderProjectSelector())
+		b.WriteString("\n\n")
 		b.WriteString(m.styles.FieldLabel.Render("Body"))
 		b.WriteString("\n")
 		b.WriteString(m.bodyTextarea.View())
@@ -1263,7 +1833,7 @@ func (m Model) editView() string {
 			b.WriteString(m.input.View())
 			b.WriteString("\n")
 		} else {
-			// Normal 5-field form: Title, Date (segmented), Priority, Body, Template
+			// Normal 8-field form: Title, Date (segmented), Remind, Priority, Tags, Project, Body, Template
 			b.WriteString(m.styles.FieldLabel.Render("Title"))
 			b.WriteString("\n")
 			b.WriteString(m.input.View())
@@ -1273,11 +1843,33 @@ func (m Model) editView() string {
 			b.WriteString(m.renderDateSegments())
 			b.WriteString("\n")
 			b.WriteString(m.styles.EditHint.Render("(t = today, leave day blank for month todo, leave day+month blank for year todo)"))
+			b.WriteString("\n")
+			b.WriteString(m.styles.EditHint.Render("(hh:mm and duration, e.g. 90 or 1h30m, are optional)"))
+			b.WriteString("\n\n")
+			b.WriteString(m.styles.FieldLabel.Render("Remind"))
+			b.WriteString("\n")
+			b.WriteString(m.remindInput.View())
+			b.WriteString("\n")
+			b.WriteString(m.styles.EditHint.Render("(before the due time, needs a day date)"))
+			b.WriteString("\n\n")
+			b.WriteString(m.styles.FieldLabel.Render("Repeat"))
+			b.WriteString("\n")
+			b.WriteString(m.repeatInput.View())
+			b.WriteString("\n")
+			b.WriteString(m.styles.EditHint.Render("(a schedule rule, or after:N for N days after completion)"))
 			b.WriteString("\n\n")
 			b.WriteString(m.styles.FieldLabel.Render("Priority"))
 			b.WriteString("\n")
 			b.WriteString(m.renderPrioritySelector())
 			b.WriteString("\n\n")
+			b.WriteString(m.styles.FieldLabel.Render("Tags"))
+			b.WriteString("\n")
+			b.WriteString(m.tagsInput.View())
+			b.WriteString("\n\n")
+			b.WriteString(m.styles.FieldLabel.Render("Project"))
+			b.WriteString("\n")
+			b.WriteString(m.renderProjectSelector())
+			b.WriteString("\n\n")
 			b.WriteString(m.styles.FieldLabel.Render("Body"))
 			b.WriteString("\n")
 			b.WriteString(m.bodyTextarea.View())
@@ -1310,6 +1902,16 @@ func (m Model) normalView() string {
 	var b strings.Builder
 	selectableIdx := 0
 
+	// Active project banner
+	if m.projectID != 0 {
+		name := "(deleted project)"
+		if p := m.store.FindProject(m.projectID); p != nil {
+			name = p.Name
+		}
+		b.WriteString(m.styles.EditHint.Render("Project: ") + m.styles.ProjectName.Render(name))
+		b.WriteString("\n")
+	}
+
 	for _, item := range items {
 		switch item.kind {
 		case headerItem:
@@ -1342,6 +1944,10 @@ func (m Model) normalView() string {
 		b.WriteString("\n")
 		b.WriteString(m.input.View())
 
+	case postponeMode:
+		b.WriteString("\n")
+		b.WriteString(m.postponeView())
+
 	case normalMode:
 		// No extra UI in normal mode
 	}
@@ -1416,6 +2022,11 @@ func (m Model) renderTodo(b *strings.Builder, t *store.Todo, selected bool) {
 	}
 	b.WriteString(text)
 
+	// Subtask progress badge for bodies with checklist items
+	if done, total := checklist.Progress(t.Body); total > 0 {
+		b.WriteString(" " + m.styles.Progress.Render(fmt.Sprintf("%d/%d", done, total)))
+	}
+
 	// Body indicator (after text, not affected by completed styling)
 	if t.HasBody() {
 		b.WriteString(" " + m.styles.BodyIndicator.Render("[+]"))
@@ -1424,18 +2035,38 @@ func (m Model) renderTodo(b *strings.Builder, t *store.Todo, selected bool) {
 	}
 
 	// Recurring indicator (after body indicator, before date)
-	if t.ScheduleID > 0 {
+	if t.IsRecurring() {
 		b.WriteString(" " + m.styles.RecurringIndicator.Render("[R]"))
 	}
 
+	// Tag chips
+	for _, tag := range t.Tags {
+		b.WriteString(" " + m.styles.Tag.Render("#"+tag))
+	}
+
 	// Date (after text, not affected by completed styling)
 	if t.HasDate() {
-		b.WriteString(" " + m.styles.Date.Render(renderFuzzyDate(t, m.dateLayout)))
+		date := renderFuzzyDate(t, m.dateLayout)
+		if t.HasTime() {
+			date += " " + t.TimeLabel()
+		}
+		if t.IsOverdue(time.Now().Format("2006-01-02")) {
+			b.WriteString(" " + m.styles.Overdue.Render(date))
+		} else {
+			b.WriteString(" " + m.styles.Date.Render(date))
+		}
 	}
 
 	b.WriteString("\n")
 }
 
+// TagFilter returns the tags required by the active inline filter
+// ("#tag" tokens), or nil when no tag filter is active.
+func (m Model) TagFilter() []string {
+	_, tags := store.SplitTagQuery(m.filterQuery)
+	return tags
+}
+
 // SetTheme replaces the todolist styles with ones built from the given theme.
 // This preserves all model state (cursor, mode, input).
 func (m *Model) SetTheme(t theme.Theme) {
@@ -1499,6 +2130,235 @@ func (m *Model) SetDateFormat(format, layout, placeholder string) {
 	m.dateSegOrder = dateSegmentOrder(format)
 }
 
+// SavedSearch returns the ID of the saved search shown instead of the
+// month or week, or 0 when none is.
+func (m Model) SavedSearch() int {
+	if m.savedSearch == nil {
+		return 0
+	}
+	return m.savedSearch.ID
+}
+
+// cycleSavedSearch shows the next saved search, going back to the month
+// or week after the last one.
+func (m *Model) cycleSavedSearch() {
+	searches := m.store.ListSavedSearches()
+	next := 0
+	for i, ss := range searches {
+		if ss.ID == m.SavedSearch() {
+			next = i + 1
+		}
+	}
+	m.savedSearch = nil
+	if next < len(searches) {
+		m.savedSearch = &searches[next]
+	}
+	m.cursor = 0
+}
+
+// ProjectFilter returns the ID of the active project, or 0 when all
+// projects are shown.
+func (m Model) ProjectFilter() int {
+	return m.projectID
+}
+
+// openProjectPicker switches to the project picker with the cursor on the
+// active project.
+func (m Model) openProjectPicker() Model {
+	m.mode = projectMode
+	m.projectNaming = false
+	m.projectErr = ""
+	m.pickerProjects = m.store.ListProjects()
+	m.projectCursor = 0
+	for i, p := range m.pickerProjects {
+		if p.ID == m.projectID {
+			m.projectCursor = i + 1
+		}
+	}
+	return m
+}
+
+// projectHelpBindings returns the key bindings shown while the project picker is open.
+func (m Model) projectHelpBindings() []key.Binding {
+	if m.projectNaming {
+		return []key.Binding{m.keys.Confirm, m.keys.Cancel}
+	}
+	add := key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "new project"))
+	del := key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete project"))
+	return []key.Binding{m.keys.Up, m.keys.Down, m.keys.Confirm, add, m.keys.Rename, del, m.keys.Cancel}
+}
+
+// selectedPickerProject returns the project under the picker cursor,
+// or nil when "All projects" is selected.
+func (m Model) selectedPickerProject() *store.Project {
+	if m.projectCursor < 1 || m.projectCursor > len(m.pickerProjects) {
+		return nil
+	}
+	return &m.pickerProjects[m.projectCursor-1]
+}
+
+// updateProjectMode handles key events in the project picker.
+func (m Model) updateProjectMode(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.projectNaming {
+		return m.updateProjectNaming(msg)
+	}
+	m.projectErr = ""
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.projectCursor > 0 {
+			m.projectCursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.projectCursor < len(m.pickerProjects) {
+			m.projectCursor++
+		}
+
+	case key.Matches(msg, m.keys.Confirm):
+		m.projectID = 0
+		if p := m.selectedPickerProject(); p != nil {
+			m.projectID = p.ID
+		}
+		m.mode = normalMode
+		m.cursor = 0
+
+	case key.Matches(msg, m.keys.Add):
+		m.projectNaming = true
+		m.projectRenamingID = 0
+		m.input.Placeholder = "Project name"
+		m.input.Prompt = "> "
+		m.input.SetValue("")
+		return m, m.input.Focus()
+
+	case key.Matches(msg, m.keys.Rename):
+		if p := m.selectedPickerProject(); p != nil {
+			m.projectNaming = true
+			m.projectRenamingID = p.ID
+			m.input.Placeholder = "Project name"
+			m.input.Prompt = "> "
+			m.input.SetValue(p.Name)
+			m.input.CursorEnd()
+			return m, m.input.Focus()
+		}
+
+	case key.Matches(msg, m.keys.Delete):
+		if p := m.selectedPickerProject(); p != nil {
+			m.store.DeleteProject(p.ID)
+			if m.projectID == p.ID {
+				m.projectID = 0
+			}
+			m.pickerProjects = m.store.ListProjects()
+			if m.projectCursor > len(m.pickerProjects) {
+				m.projectCursor = len(m.pickerProjects)
+			}
+		}
+
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = normalMode
+	}
+	return m, nil
+}
+
+// updateProjectNaming handles key events while typing a new or renamed project name.
+func (m Model) updateProjectNaming(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Confirm):
+		name := strings.TrimSpace(m.input.Value())
+		if name == "" {
+			return m, nil
+		}
+		var err error
+		id := m.projectRenamingID
+		if id != 0 {
+			err = m.store.RenameProject(id, name)
+		} else {
+			var p store.Project
+			p, err = m.store.AddProject(name)
+			id = p.ID
+		}
+		if err != nil {
+			m.projectErr = fmt.Sprintf("A project named %q already exists", name)
+			return m, nil
+		}
+		m.projectNaming = false
+		m.projectErr = ""
+		m.input.Blur()
+		m.input.SetValue("")
+		m.pickerProjects = m.store.ListProjects()
+		for i, p := range m.pickerProjects {
+			if p.ID == id {
+				m.projectCursor = i + 1
+			}
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Cancel):
+		m.projectNaming = false
+		m.projectErr = ""
+		m.input.Blur()
+		m.input.SetValue("")
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// projectView renders the project picker with per-project pending counts.
+func (m Model) projectView() string {
+	var b strings.Builder
+	b.WriteString(m.styles.EditTitle.Render("Projects"))
+	b.WriteString("\n\n")
+
+	pending := make(map[int]int)
+	for _, pc := range m.store.ProjectCounts() {
+		pending[pc.ProjectID] = pc.Pending
+	}
+
+	line := func(i int, label string, active bool) {
+		if i == m.projectCursor && !m.projectNaming {
+			b.WriteString(m.styles.Cursor.Render("> "))
+		} else {
+			b.WriteString("  ")
+		}
+		if active {
+			b.WriteString(m.styles.ProjectName.Render(label))
+		} else {
+			b.WriteString(label)
+		}
+		b.WriteString("\n")
+	}
+	line(0, "All projects", m.projectID == 0)
+	for i, p := range m.pickerProjects {
+		label := p.Name + " " + m.styles.Empty.Render(fmt.Sprintf("(%d)", pending[p.ID]))
+		line(i+1, label, m.projectID == p.ID)
+	}
+	if len(m.pickerProjects) == 0 {
+		b.WriteString("  " + m.styles.Empty.Render("(no projects yet, press a to add one)"))
+		b.WriteString("\n")
+	}
+
+	if m.projectNaming {
+		b.WriteString("\n")
+		label := "New project"
+		if m.projectRenamingID != 0 {
+			label = "Rename project"
+		}
+		b.WriteString(m.styles.FieldLabel.Render(label))
+		b.WriteString("\n")
+		b.WriteString(m.input.View())
+		b.WriteString("\n")
+	}
+	if m.projectErr != "" {
+		b.WriteString("\n")
+		b.WriteString(m.styles.EditHint.Render(m.projectErr))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 // updateTemplatePicker handles key events in the template picker sub-state.
 func (m Model) updateTemplatePicker(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch {
@@ -1608,7 +2468,10 @@ func (m Model) renderDateSegments() string {
 		seg := m.dateSegmentByPos(i)
 		parts = append(parts, seg.View())
 	}
-	return parts[0] + sep + parts[1] + sep + parts[2]
+	timeSep := m.styles.DateSeparator.Render(":")
+	return parts[0] + sep + parts[1] + sep + parts[2] +
+		"  " + m.timeSegHour.View() + timeSep + m.timeSegMinute.View() +
+		m.styles.DateSeparator.Render("  for ") + m.durationInput.View()
 }
 
 // renderFuzzyDate formats a todo's date for display, respecting its precision level.
@@ -1745,6 +2608,67 @@ func (m Model) deriveDateFromSegments() (string, string, int) {
 	return isoDate, "day", -1
 }
 
+// deriveTimeFromSegments reads the hour, minute and duration segments for a todo
+// with the given date precision. Returns (startTime, durationMinutes, errSegPos) where
+// errSegPos >= 0 indicates which visual segment needs attention (-1 means success).
+// A time requires a full day date.
+func (m Model) deriveTimeFromSegments(precision string) (string, int, int) {
+	hour := strings.TrimSpace(m.timeSegHour.Value())
+	minute := strings.TrimSpace(m.timeSegMinute.Value())
+	dur := strings.TrimSpace(m.durationInput.Value())
+
+	if hour == "" && minute == "" && dur == "" {
+		return "", 0, -1
+	}
+	if hour == "" || precision != "day" {
+		return "", 0, segHour
+	}
+	if minute == "" {
+		minute = "00"
+	}
+	if _, err := store.ParseClock(hour); err != nil || len(hour) > 2 {
+		return "", 0, segHour
+	}
+	startTime, err := store.ParseClock(hour + ":" + minute)
+	if err != nil {
+		return "", 0, segMinute
+	}
+	duration, err := store.ParseDuration(dur)
+	if err != nil {
+		return "", 0, segDuration
+	}
+	return startTime, duration, -1
+}
+
+// deriveReminders parses the reminders field for a todo with the given date
+// precision. ok is false when the field is invalid or reminders are set on a
+// todo without a day date.
+func (m Model) deriveReminders(precision string) ([]int, bool) {
+	offsets, err := remind.ParseOffsets(m.remindInput.Value())
+	if err != nil {
+		return nil, false
+	}
+	if len(offsets) > 0 && precision != "day" {
+		return nil, false
+	}
+	return offsets, true
+}
+
+// deriveRepeat parses the repeat field for a todo with the given date and
+// precision and returns the rule to store ("" for none). ok is false when
+// the rule is invalid or set on a month or year todo.
+func (m Model) deriveRepeat(isoDate, precision string) (string, bool) {
+	value := strings.TrimSpace(m.repeatInput.Value())
+	if value == "" {
+		return "", true
+	}
+	r, err := recurring.ParseRepeat(recurring.AnchorRepeat(value, isoDate, time.Now()))
+	if err != nil || (precision != "" && precision != "day") {
+		return "", false
+	}
+	return r.String(), true
+}
+
 // updateDateSegment handles key events forwarded to the focused date segment.
 // It intercepts separator chars, handles auto-advance on full segment, and backspace navigation.
 func (m Model) updateDateSegment(msg tea.KeyMsg) (Model, tea.Cmd) {
@@ -1759,6 +2683,14 @@ func (m Model) updateDateSegment(msg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// ":" after the hour jumps to the minute segment
+	if key == ":" {
+		if m.dateSegFocus == segHour {
+			return m, m.focusDateSegment(segMinute)
+		}
+		return m, nil
+	}
+
 	// Block separator characters (handled visually)
 	if key == "-" || key == "." || key == "/" {
 		return m, nil
@@ -1779,7 +2711,7 @@ func (m Model) updateDateSegment(msg tea.KeyMsg) (Model, tea.Cmd) {
 	// Auto-advance: if segment just reached its char limit, move to next
 	newLen := len(seg.Value())
 	limit := m.dateSegCharLimit(m.dateSegFocus)
-	if newLen >= limit && newLen > prevLen && m.dateSegFocus < 2 {
+	if newLen >= limit && newLen > prevLen && m.dateSegFocus < segDuration {
 		cmd2 := m.focusDateSegment(m.dateSegFocus + 1)
 		return m, tea.Batch(cmd, cmd2)
 	}
@@ -1787,6 +2719,13 @@ func (m Model) updateDateSegment(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, cmd
 }
 
+// Visual positions of the time segments, which follow the three date segments.
+const (
+	segHour     = 3
+	segMinute   = 4
+	segDuration = 5
+)
+
 // dateSegmentOrder returns the visual-to-semantic mapping for date segments.
 // Semantic: 0=day, 1=month, 2=year. The returned array maps visual positions (left-to-right) to semantic meaning.
 func dateSegmentOrder(format string) [3]int {
@@ -1801,7 +2740,16 @@ func dateSegmentOrder(format string) [3]int {
 }
 
 // dateSegmentByPos returns the textinput for a visual position using dateSegOrder.
+// Positions after the three date segments are the hour, minute and duration inputs.
 func (m *Model) dateSegmentByPos(pos int) *textinput.Model {
+	switch pos {
+	case segHour:
+		return &m.timeSegHour
+	case segMinute:
+		return &m.timeSegMinute
+	case segDuration:
+		return &m.durationInput
+	}
 	switch m.dateSegOrder[pos] {
 	case 0:
 		return &m.dateSegDay
@@ -1828,6 +2776,9 @@ func (m *Model) dateSegSeparator() string {
 
 // dateSegPlaceholderByPos returns the placeholder text for a visual position.
 func (m *Model) dateSegPlaceholderByPos(pos int) string {
+	if pos >= segHour {
+		return m.dateSegmentByPos(pos).Placeholder
+	}
 	switch m.dateSegOrder[pos] {
 	case 0:
 		return "dd"
@@ -1842,30 +2793,37 @@ func (m *Model) dateSegPlaceholderByPos(pos int) string {
 
 // focusDateSegment focuses the segment at the given visual position and blurs all others.
 func (m *Model) focusDateSegment(pos int) tea.Cmd {
-	m.dateSegDay.Blur()
-	m.dateSegMonth.Blur()
-	m.dateSegYear.Blur()
+	m.blurAllDateSegments()
 	m.dateSegFocus = pos
 	return m.dateSegmentByPos(pos).Focus()
 }
 
-// blurAllDateSegments blurs all three date segments.
+// blurAllDateSegments blurs the date, time and duration segments.
 func (m *Model) blurAllDateSegments() {
 	m.dateSegDay.Blur()
 	m.dateSegMonth.Blur()
 	m.dateSegYear.Blur()
+	m.timeSegHour.Blur()
+	m.timeSegMinute.Blur()
+	m.durationInput.Blur()
 }
 
-// clearAllDateSegments clears all three date segments and resets focus.
+// clearAllDateSegments clears the date, time and duration segments and resets focus.
 func (m *Model) clearAllDateSegments() {
 	m.dateSegDay.SetValue("")
 	m.dateSegMonth.SetValue("")
 	m.dateSegYear.SetValue("")
+	m.timeSegHour.SetValue("")
+	m.timeSegMinute.SetValue("")
+	m.durationInput.SetValue("")
 	m.dateSegFocus = 0
 }
 
 // dateSegCharLimit returns the character limit for the segment at the given visual position.
 func (m *Model) dateSegCharLimit(pos int) int {
+	if pos >= segHour {
+		return m.dateSegmentByPos(pos).CharLimit
+	}
 	switch m.dateSegOrder[pos] {
 	case 2:
 		return 4 // year
@@ -1880,3 +2838,129 @@ func max(a, b int) int {
 	}
 	return b
 }
+
+// SetHolidayProvider sets the holidays postponed todos can skip.
+func (m *Model) SetHolidayProvider(p *holidays.Provider) {
+	m.holidays = p
+}
+
+// SetSkipHolidays controls whether postponed todos skip holidays.
+func (m *Model) SetSkipHolidays(skip bool) {
+	m.skipHolidays = skip
+}
+
+// postponeHelpBindings returns the key bindings shown while the postpone
+// menu is open.
+func (m Model) postponeHelpBindings() []key.Binding {
+	if m.postponePicking {
+		return []key.Binding{m.keys.Confirm, m.keys.Cancel}
+	}
+	return []key.Binding{m.keys.PostponeDay, m.keys.PostponeWeekday, m.keys.PostponeWeek, m.keys.PostponeMonth, m.keys.PostponePick, m.keys.Cancel}
+}
+
+// updatePostponeMode handles key events in the postpone menu.
+func (m Model) updatePostponeMode(msg tea.KeyMsg) (Model, tea.Cmd) {
+	todo := m.store.Find(m.postponeID)
+	if todo == nil {
+		m.mode = normalMode
+		return m, nil
+	}
+	if m.postponePicking {
+		return m.updatePostponePicking(msg, todo)
+	}
+
+	var skip func(time.Time) bool
+	if m.skipHolidays && m.holidays != nil {
+		skip = m.holidays.IsHoliday
+	}
+	step := postpone.Day
+	switch {
+	case key.Matches(msg, m.keys.PostponeDay):
+	case key.Matches(msg, m.keys.PostponeWeekday):
+		step = postpone.Weekday
+	case key.Matches(msg, m.keys.PostponeWeek):
+		step = postpone.Week
+	case key.Matches(msg, m.keys.PostponeMonth):
+		step = postpone.Month
+	case key.Matches(msg, m.keys.PostponePick):
+		m.postponePicking = true
+		m.input.Placeholder = "tomorrow, fri, +2w, " + m.datePlaceholder
+		m.input.Prompt = "> "
+		m.input.SetValue("")
+		return m, m.input.Focus()
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = normalMode
+		return m, nil
+	default:
+		return m, nil
+	}
+	date, precision := postpone.Date(*todo, step, time.Now(), skip)
+	m.postpone(todo, date, precision)
+	return m, nil
+}
+
+// updatePostponePicking handles key events while typing the date to
+// postpone to.
+func (m Model) updatePostponePicking(msg tea.KeyMsg, todo *store.Todo) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Confirm):
+		date, precision, err := postpone.Parse(m.input.Value(), time.Now(), m.dateLayout)
+		if err != nil {
+			m.postponeErr = err.Error()
+			return m, nil
+		}
+		m.input.Blur()
+		m.postpone(todo, date, precision)
+		return m, nil
+	case key.Matches(msg, m.keys.Cancel):
+		m.input.Blur()
+		m.postponePicking = false
+		m.postponeErr = ""
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.postponeErr = ""
+	return m, cmd
+}
+
+// postpone moves the todo to the given date and closes the postpone menu.
+// The cursor stays on the todo if it is still in view.
+func (m *Model) postpone(todo *store.Todo, date, precision string) {
+	m.store.Batch("postpone", func() {
+		m.store.Update(todo.ID, todo.Text, date, precision, todo.Priority)
+	})
+	m.mode = normalMode
+	m.postponePicking = false
+	items := m.visibleItems()
+	selectable := selectableIndices(items)
+	for i, idx := range selectable {
+		if items[idx].todo.ID == todo.ID {
+			m.cursor = i
+			return
+		}
+	}
+	if m.cursor >= len(selectable) {
+		m.cursor = max(0, len(selectable)-1)
+	}
+}
+
+// postponeView renders the postpone menu shown below the todo list.
+func (m Model) postponeView() string {
+	var b strings.Builder
+	title := ""
+	if todo := m.store.Find(m.postponeID); todo != nil {
+		title = todo.Text
+	}
+	b.WriteString(m.styles.FieldLabel.Render("Postpone ") + title)
+	b.WriteString("\n")
+	if m.postponePicking {
+		b.WriteString(m.input.View())
+		b.WriteString("\n")
+	}
+	if m.postponeErr != "" {
+		b.WriteString(m.styles.EditHint.Render(m.postponeErr))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
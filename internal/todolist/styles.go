@@ -11,9 +11,13 @@ type Styles struct {
 	Completed     lipgloss.Style
 	Cursor        lipgloss.Style
 	Date          lipgloss.Style
+	Overdue       lipgloss.Style
 	Empty         lipgloss.Style
 	BodyIndicator      lipgloss.Style
 	RecurringIndicator lipgloss.Style
+	Tag                lipgloss.Style
+	Progress           lipgloss.Style
+	ProjectName        lipgloss.Style
 	Separator          lipgloss.Style
 	Checkbox      lipgloss.Style
 	CheckboxDone  lipgloss.Style
@@ -37,9 +41,13 @@ func NewStyles(t theme.Theme) Styles {
 		Completed:     lipgloss.NewStyle().Strikethrough(true).Foreground(t.CompletedFg),
 		Cursor:        lipgloss.NewStyle().Foreground(t.AccentFg),
 		Date:          lipgloss.NewStyle().Foreground(t.MutedFg),
+		Overdue:       lipgloss.NewStyle().Bold(true).Foreground(t.OverdueFg),
 		Empty:         lipgloss.NewStyle().Foreground(t.EmptyFg),
 		BodyIndicator:      lipgloss.NewStyle().Foreground(t.MutedFg),
 		RecurringIndicator: lipgloss.NewStyle().Foreground(t.MutedFg),
+		Tag:                lipgloss.NewStyle().Foreground(t.AccentFg),
+		Progress:           lipgloss.NewStyle().Foreground(t.MutedFg),
+		ProjectName:        lipgloss.NewStyle().Bold(true).Foreground(t.AccentFg),
 		Separator:          lipgloss.NewStyle().Foreground(t.MutedFg),
 		Checkbox:      lipgloss.NewStyle().Foreground(t.AccentFg),
 		CheckboxDone:  lipgloss.NewStyle().Foreground(t.CompletedCountFg),
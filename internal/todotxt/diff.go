@@ -0,0 +1,45 @@
+package todotxt
+
+// Diff compares two versions of a file line by line and returns the lines
+// only in old prefixed with "- " and the lines only in new prefixed with
+// "+ ", in file order. Unchanged lines are left out.
+func Diff(old, new []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of
+	// old[i:] and new[j:].
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(old) && j < len(new) {
+		switch {
+		case old[i] == new[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+old[i])
+			i++
+		default:
+			out = append(out, "+ "+new[j])
+			j++
+		}
+	}
+	for ; i < len(old); i++ {
+		out = append(out, "- "+old[i])
+	}
+	for ; j < len(new); j++ {
+		out = append(out, "+ "+new[j])
+	}
+	return out
+}
@@ -0,0 +1,178 @@
+package todotxt
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/recurring"
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+// Lines returns the todos outside the trash and the archive as todo.txt
+// lines, in list order.
+func Lines(s store.TodoStore) []string {
+	names := projectNames(s)
+	var lines []string
+	for _, t := range s.Todos() {
+		lines = append(lines, Format(t, names[t.ProjectID]))
+	}
+	return lines
+}
+
+// Export writes the todos outside the trash and the archive as todo.txt.
+func Export(w io.Writer, s store.TodoStore) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range Lines(s) {
+		if _, err := bw.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Change is what importing one todo.txt line does to the store.
+type Change struct {
+	Task     Task
+	Existing *store.Todo // todo with the same text, nil for a new todo
+	Before   string      // the existing todo as a todo.txt line, "" for a new todo
+	After    string      // the todo as a todo.txt line after the import
+}
+
+// Unchanged reports whether applying the change leaves the store as it is.
+func (c Change) Unchanged() bool {
+	return c.Before == c.After
+}
+
+// Result counts the changes made by Apply.
+type Result struct {
+	Added     int
+	Updated   int
+	Unchanged int
+}
+
+// Plan reads todo.txt lines and works out how each one changes the store,
+// without changing it. A line updates the first not yet matched todo with
+// the same text, including archived ones; other lines add new todos.
+func Plan(r io.Reader, s store.TodoStore) ([]Change, error) {
+	byText := make(map[string][]store.Todo)
+	existing := append(s.Todos(), s.ArchivedTodos("0000-01-01", "9999-12-31")...)
+	for _, t := range existing {
+		key := normalizeText(t.Text)
+		byText[key] = append(byText[key], t)
+	}
+	names := projectNames(s)
+	today := time.Now().Format(dateFormat)
+
+	var changes []Change
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		task, ok := Parse(sc.Text())
+		if !ok {
+			continue
+		}
+		c := Change{Task: task}
+		after := store.Todo{CreatedAt: today}
+		if matches := byText[normalizeText(task.Text)]; len(matches) > 0 {
+			match := matches[0]
+			byText[normalizeText(task.Text)] = matches[1:]
+			c.Existing = &match
+			c.Before = Format(match, names[match.ProjectID])
+			after = match
+		}
+		after.Text = task.Text
+		after.Priority = task.Priority
+		after.Date, after.DatePrecision = task.Date, task.DatePrecision
+		after.Tags = store.NormalizeTags(task.Contexts)
+		if task.Done && !after.Done {
+			after.CompletedAt = today
+		}
+		after.Done = task.Done
+		c.After = Format(after, projectName(s, task.Project))
+		changes = append(changes, c)
+	}
+	return changes, sc.Err()
+}
+
+// Apply makes the planned changes as a single undoable operation. Projects
+// named by the lines are created when missing.
+func Apply(s store.TodoStore, changes []Change) Result {
+	var res Result
+	s.Batch("import", func() {
+		for _, c := range changes {
+			if c.Unchanged() {
+				res.Unchanged++
+				continue
+			}
+			t := c.Task
+			var id int
+			done := false
+			if c.Existing == nil {
+				id = s.Add(t.Text, t.Date, t.DatePrecision, t.Priority).ID
+				res.Added++
+			} else {
+				id, done = c.Existing.ID, c.Existing.Done
+				s.Update(id, t.Text, t.Date, t.DatePrecision, t.Priority)
+				res.Updated++
+			}
+			s.SetTags(id, t.Contexts)
+			s.SetProject(id, projectID(s, t.Project))
+			if t.Done != done {
+				recurring.Toggle(s, id, time.Now())
+			}
+		}
+	})
+	return res
+}
+
+// findProject returns the project a +project name refers to, or nil.
+// Dashes stand for spaces, as written by Format.
+func findProject(s store.TodoStore, name string) *store.Project {
+	if p := s.FindProjectByName(name); p != nil {
+		return p
+	}
+	return s.FindProjectByName(strings.ReplaceAll(name, "-", " "))
+}
+
+// projectName returns the stored name of the named project, or name
+// itself if the project does not exist yet.
+func projectName(s store.TodoStore, name string) string {
+	if name == "" {
+		return ""
+	}
+	if p := findProject(s, name); p != nil {
+		return p.Name
+	}
+	return name
+}
+
+// projectID returns the ID of the named project, creating it if missing.
+// It returns 0 for "".
+func projectID(s store.TodoStore, name string) int {
+	if name == "" {
+		return 0
+	}
+	if p := findProject(s, name); p != nil {
+		return p.ID
+	}
+	p, err := s.AddProject(name)
+	if err != nil {
+		return 0
+	}
+	return p.ID
+}
+
+// projectNames maps project IDs to names.
+func projectNames(s store.TodoStore) map[int]string {
+	names := make(map[int]string)
+	for _, p := range s.ListProjects() {
+		names[p.ID] = p.Name
+	}
+	return names
+}
+
+// normalizeText collapses whitespace so texts compare as Format writes them.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,163 @@
+// Package todotxt converts between todos and todo.txt lines
+// (https://github.com/todotxt/todo.txt).
+//
+// Priorities (A) to (C) map to P1-P3 and any lower letter to P4. The due:
+// tag holds the date: YYYY-MM-DD for day todos, YYYY-MM for month todos
+// and YYYY for year todos. The first +project names the todo's project and
+// @contexts become tags. Completed todos keep their priority in a pri: tag,
+// as the format drops the (A) marker on completion.
+package todotxt
+
+import (
+	"strings"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+const dateFormat = "2006-01-02"
+
+// Task is a parsed todo.txt line.
+type Task struct {
+	Done          bool
+	Completed     string // completion date (YYYY-MM-DD), "" if not given
+	Created       string // creation date (YYYY-MM-DD), "" if not given
+	Priority      int    // 1-4, 0 for none
+	Text          string // description without project, context and due: tags
+	Project       string // first +project, "" for none
+	Contexts      []string
+	Date          string // from due:, "" for none
+	DatePrecision string // "day", "month" or "year" when Date is set
+}
+
+// Parse parses a todo.txt line. ok is false for blank lines.
+func Parse(line string) (t Task, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return Task{}, false
+	}
+	if fields[0] == "x" {
+		t.Done = true
+		fields = fields[1:]
+		if len(fields) > 0 && isDate(fields[0]) {
+			t.Completed = fields[0]
+			fields = fields[1:]
+		}
+	} else if len(fields[0]) == 3 && fields[0][0] == '(' && fields[0][2] == ')' && isPriorityLetter(fields[0][1]) {
+		t.Priority = letterPriority(fields[0][1])
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && isDate(fields[0]) {
+		t.Created = fields[0]
+		fields = fields[1:]
+	}
+
+	var words []string
+	for _, f := range fields {
+		switch {
+		case len(f) > 1 && f[0] == '+' && t.Project == "":
+			t.Project = f[1:]
+		case len(f) > 1 && f[0] == '@':
+			t.Contexts = append(t.Contexts, f[1:])
+		case strings.HasPrefix(f, "due:") && t.Date == "" && parseDue(&t, f[4:]):
+		case strings.HasPrefix(f, "pri:") && len(f) == 5 && isPriorityLetter(f[4]) && t.Priority == 0:
+			t.Priority = letterPriority(f[4])
+		default:
+			words = append(words, f)
+		}
+	}
+	t.Text = strings.Join(words, " ")
+	return t, true
+}
+
+// parseDue sets the date of t from a due: value and reports whether the
+// value was a valid date.
+func parseDue(t *Task, v string) bool {
+	layouts := []struct{ layout, precision string }{
+		{dateFormat, "day"},
+		{"2006-01", "month"},
+		{"2006", "year"},
+	}
+	for _, l := range layouts {
+		if len(v) != len(l.layout) {
+			continue
+		}
+		if d, err := time.Parse(l.layout, v); err == nil {
+			t.Date, t.DatePrecision = d.Format(dateFormat), l.precision
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders a todo as a todo.txt line. project is the name of the
+// todo's project, "" for none; spaces in it become dashes.
+func Format(t store.Todo, project string) string {
+	var parts []string
+	if t.Done {
+		parts = append(parts, "x")
+		// A creation date is only allowed after a completion date.
+		if len(t.CompletedAt) >= 10 {
+			parts = append(parts, t.CompletedAt[:10])
+			if isDate(t.CreatedAt) {
+				parts = append(parts, t.CreatedAt)
+			}
+		}
+	} else {
+		if t.Priority >= 1 && t.Priority <= 4 {
+			parts = append(parts, "("+priorityLetter(t.Priority)+")")
+		}
+		if isDate(t.CreatedAt) {
+			parts = append(parts, t.CreatedAt)
+		}
+	}
+	parts = append(parts, strings.Join(strings.Fields(t.Text), " "))
+	if project != "" {
+		parts = append(parts, "+"+strings.Join(strings.Fields(project), "-"))
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, "@"+tag)
+	}
+	if t.HasDate() {
+		switch {
+		case t.IsMonthPrecision():
+			parts = append(parts, "due:"+t.Date[:7])
+		case t.IsYearPrecision():
+			parts = append(parts, "due:"+t.Date[:4])
+		default:
+			parts = append(parts, "due:"+t.Date)
+		}
+	}
+	if t.Done && t.Priority >= 1 && t.Priority <= 4 {
+		parts = append(parts, "pri:"+priorityLetter(t.Priority))
+	}
+	return strings.Join(parts, " ")
+}
+
+// isDate reports whether s is a YYYY-MM-DD date.
+func isDate(s string) bool {
+	if len(s) != len(dateFormat) {
+		return false
+	}
+	_, err := time.Parse(dateFormat, s)
+	return err == nil
+}
+
+// isPriorityLetter reports whether c is a todo.txt priority letter.
+func isPriorityLetter(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+// letterPriority maps a priority letter to a todo priority: A-C to P1-P3,
+// anything lower to P4.
+func letterPriority(c byte) int {
+	if c <= 'C' {
+		return int(c-'A') + 1
+	}
+	return 4
+}
+
+// priorityLetter maps a todo priority (1-4) to a priority letter.
+func priorityLetter(p int) string {
+	return string(rune('A' + p - 1))
+}
@@ -0,0 +1,170 @@
+package todotxt
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antti/todo-calendar/internal/store"
+)
+
+func newStore(t *testing.T) *store.SQLiteStore {
+	t.Helper()
+	s, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want Task
+	}{
+		{
+			"(A) 2026-10-01 Call mom +Family @phone due:2026-10-20",
+			Task{Priority: 1, Created: "2026-10-01", Text: "Call mom", Project: "Family", Contexts: []string{"phone"}, Date: "2026-10-20", DatePrecision: "day"},
+		},
+		{
+			"x 2026-10-17 2026-10-01 File taxes due:2026-10 pri:B",
+			Task{Done: true, Completed: "2026-10-17", Created: "2026-10-01", Priority: 2, Text: "File taxes", Date: "2026-10-01", DatePrecision: "month"},
+		},
+		{
+			"(D) Learn Go +work +extra due:2027 due:soon",
+			Task{Priority: 4, Text: "Learn Go +extra due:soon", Project: "work", Date: "2027-01-01", DatePrecision: "year"},
+		},
+		{
+			"xylophone lessons (A)",
+			Task{Text: "xylophone lessons (A)"},
+		},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.line)
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+	if _, ok := Parse("   "); ok {
+		t.Error("Parse accepted a blank line")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		todo    store.Todo
+		project string
+		want    string
+	}{
+		{
+			store.Todo{Text: "Call mom", Priority: 1, CreatedAt: "2026-10-01", Date: "2026-10-20", DatePrecision: "day", Tags: []string{"phone"}},
+			"Family stuff",
+			"(A) 2026-10-01 Call mom +Family-stuff @phone due:2026-10-20",
+		},
+		{
+			store.Todo{Text: "File taxes", Done: true, Priority: 2, CreatedAt: "2026-10-01", CompletedAt: "2026-10-17 09:30:00", Date: "2026-10-01", DatePrecision: "month"},
+			"",
+			"x 2026-10-17 2026-10-01 File taxes due:2026-10 pri:B",
+		},
+		{
+			store.Todo{Text: "Someday", Done: true, CreatedAt: "2026-10-01"},
+			"",
+			"x Someday",
+		},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.todo, tt.project); got != tt.want {
+			t.Errorf("Format(%+v) = %q, want %q", tt.todo, got, tt.want)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	s := newStore(t)
+	existing := s.Add("Call mom", "", "", 0)
+
+	file := "(A) Call mom +Family @phone due:2026-10-20\n" +
+		"\n" +
+		"x Pay rent\n" +
+		"Read book\n"
+	changes, err := Plan(strings.NewReader(file), s)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if len(changes) != 3 || changes[0].Existing == nil || changes[0].Existing.ID != existing.ID || changes[1].Existing != nil {
+		t.Fatalf("changes = %+v", changes)
+	}
+	if s.FindProjectByName("Family") != nil || len(s.Todos()) != 1 {
+		t.Fatal("Plan changed the store")
+	}
+
+	res := Apply(s, changes)
+	if res != (Result{Added: 2, Updated: 1}) {
+		t.Errorf("Apply = %+v", res)
+	}
+	got := s.Find(existing.ID)
+	p := s.FindProjectByName("Family")
+	if got == nil || p == nil || got.ProjectID != p.ID || got.Priority != 1 || got.Date != "2026-10-20" || !got.HasTag("phone") {
+		t.Errorf("updated todo = %+v", got)
+	}
+
+	// Importing the same file again changes nothing.
+	changes, _ = Plan(strings.NewReader(file), s)
+	if res := Apply(s, changes); res != (Result{Unchanged: 3}) {
+		t.Errorf("re-import = %+v", res)
+	}
+
+	// The import is one undoable operation.
+	if label, _ := s.Undo(); label != "import" || len(s.Todos()) != 1 {
+		t.Errorf("undo = %q with %d todos", label, len(s.Todos()))
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	src := newStore(t)
+	p, _ := src.AddProject("Home stuff")
+	a := src.Add("Fix bike", "2026-10-20", "day", 3)
+	src.SetProject(a.ID, p.ID)
+	src.SetTags(a.ID, []string{"garage"})
+	b := src.Add("Taxes", "2027-01-01", "year", 0)
+	src.Toggle(b.ID)
+
+	var buf strings.Builder
+	if err := Export(&buf, src); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	today := time.Now().Format(dateFormat)
+	want := "(C) " + today + " Fix bike +Home-stuff @garage due:2026-10-20\n" +
+		"x " + today + " " + today + " Taxes due:2027\n"
+	if buf.String() != want {
+		t.Errorf("Export = %q, want %q", buf.String(), want)
+	}
+
+	dst := newStore(t)
+	dst.AddProject("Home stuff")
+	changes, err := Plan(strings.NewReader(buf.String()), dst)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	Apply(dst, changes)
+	var out strings.Builder
+	Export(&out, dst)
+	if out.String() != want {
+		t.Errorf("round trip = %q, want %q", out.String(), want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := []string{"a", "b", "c", "d"}
+	new := []string{"a", "c", "e", "d", "f"}
+	want := []string{"- b", "+ e", "+ f"}
+	if got := Diff(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff = %q, want %q", got, want)
+	}
+	if got := Diff(old, old); len(got) != 0 {
+		t.Errorf("Diff of equal files = %q", got)
+	}
+}
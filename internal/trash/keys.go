@@ -0,0 +1,63 @@
+package trash
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines key bindings for the trash overlay.
+type KeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Restore key.Binding
+	Purge   key.Binding
+	Empty   key.Binding
+	Yes     key.Binding
+	No      key.Binding
+	Cancel  key.Binding
+}
+
+// ShortHelp returns key bindings for the short help view.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Restore, k.Purge, k.Empty, k.Cancel}
+}
+
+// FullHelp returns key bindings for the full help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// DefaultKeyMap returns the default trash key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("k", "up"),
+			key.WithHelp("k/up", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("j", "down"),
+			key.WithHelp("j/dn", "down"),
+		),
+		Restore: key.NewBinding(
+			key.WithKeys("r", "enter"),
+			key.WithHelp("r", "restore"),
+		),
+		Purge: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "delete forever"),
+		),
+		Empty: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "empty trash"),
+		),
+		Yes: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "yes"),
+		),
+		No: key.NewBinding(
+			key.WithKeys("n", "esc"),
+			key.WithHelp("n", "no"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
@@ -0,0 +1,233 @@
+// Package trash implements the overlay for browsing deleted todos,
+// restoring them and removing them permanently.
+package trash
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/antti/todo-calendar/internal/config"
+	"github.com/antti/todo-calendar/internal/store"
+	"github.com/antti/todo-calendar/internal/theme"
+)
+
+// CloseMsg is emitted when the user presses Esc to close the trash overlay.
+type CloseMsg struct{}
+
+// confirmAction is a permanent deletion waiting for confirmation.
+type confirmAction int
+
+const (
+	confirmNone  confirmAction = iota
+	confirmPurge               // delete the selected todo forever
+	confirmEmpty               // delete every trashed todo forever
+)
+
+// Model represents the trash overlay.
+type Model struct {
+	store         store.TodoStore
+	todos         []store.Todo
+	cursor        int
+	confirm       confirmAction
+	dateLayout    string
+	retentionDays int
+	width         int
+	height        int
+	keys          KeyMap
+	styles        Styles
+}
+
+// New creates a new trash overlay model.
+func New(s store.TodoStore, t theme.Theme, cfg config.Config) Model {
+	return Model{
+		store:         s,
+		todos:         s.TrashedTodos(),
+		dateLayout:    cfg.DateLayout(),
+		retentionDays: cfg.TrashRetentionDays,
+		keys:          DefaultKeyMap(),
+		styles:        NewStyles(t),
+	}
+}
+
+// SetSize stores dimensions for layout.
+func (m *Model) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+}
+
+// SetTheme replaces the styles with ones built from the given theme.
+func (m *Model) SetTheme(t theme.Theme) {
+	m.styles = NewStyles(t)
+}
+
+// HelpBindings returns overlay-specific key bindings for help bar display.
+func (m Model) HelpBindings() []key.Binding {
+	if m.confirm != confirmNone {
+		return []key.Binding{m.keys.Yes, m.keys.No}
+	}
+	return m.keys.ShortHelp()
+}
+
+// refresh reloads the trashed todos and clamps the cursor.
+func (m *Model) refresh() {
+	m.todos = m.store.TrashedTodos()
+	if m.cursor >= len(m.todos) {
+		m.cursor = len(m.todos) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// selected returns the todo under the cursor, or nil if the trash is empty.
+func (m Model) selected() *store.Todo {
+	if m.cursor < 0 || m.cursor >= len(m.todos) {
+		return nil
+	}
+	t := m.todos[m.cursor]
+	return &t
+}
+
+// Update handles messages for the trash overlay.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.confirm != confirmNone {
+			return m.updateConfirm(msg)
+		}
+		switch {
+		case key.Matches(msg, m.keys.Cancel):
+			return m, func() tea.Msg { return CloseMsg{} }
+
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.todos)-1 {
+				m.cursor++
+			}
+
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case key.Matches(msg, m.keys.Restore):
+			if sel := m.selected(); sel != nil {
+				m.store.Restore(sel.ID)
+				m.refresh()
+			}
+
+		case key.Matches(msg, m.keys.Purge):
+			if m.selected() != nil {
+				m.confirm = confirmPurge
+			}
+
+		case key.Matches(msg, m.keys.Empty):
+			if len(m.todos) > 0 {
+				m.confirm = confirmEmpty
+			}
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+// updateConfirm handles the y/n answer to a permanent deletion prompt.
+func (m Model) updateConfirm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Yes):
+		switch m.confirm {
+		case confirmPurge:
+			if sel := m.selected(); sel != nil {
+				m.store.Purge(sel.ID)
+			}
+		case confirmEmpty:
+			// Include todos trashed within the current second.
+			m.store.PurgeTrash(time.Now().Add(time.Second))
+		}
+		m.confirm = confirmNone
+		m.refresh()
+	case key.Matches(msg, m.keys.No):
+		m.confirm = confirmNone
+	}
+	return m, nil
+}
+
+// View renders the trash overlay.
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render("Trash"))
+	b.WriteString("\n\n")
+
+	if len(m.todos) == 0 {
+		b.WriteString(m.styles.Empty.Render("(trash is empty)"))
+		return m.verticalCenter(b.String())
+	}
+
+	maxVisible := m.height - 10
+	if maxVisible < 1 {
+		maxVisible = 1
+	}
+	start := 0
+	if m.cursor >= maxVisible {
+		start = m.cursor - maxVisible + 1
+	}
+	end := min(len(m.todos), start+maxVisible)
+
+	for i := start; i < end; i++ {
+		t := m.todos[i]
+		check := "[ ]"
+		if t.Done {
+			check = "[x]"
+		}
+		info := "deleted " + t.DeletedAt
+		if t.HasDate() {
+			info = config.FormatDate(t.Date, m.dateLayout) + " · " + info
+		}
+		if i == m.cursor {
+			b.WriteString(m.styles.SelectedItem.Render("> " + check + " " + t.Text))
+			b.WriteString("  ")
+			b.WriteString(m.styles.SelectedDate.Render(info))
+		} else {
+			b.WriteString(m.styles.ItemText.Render("  " + check + " " + t.Text))
+			b.WriteString("  ")
+			b.WriteString(m.styles.ItemDate.Render(info))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	switch m.confirm {
+	case confirmPurge:
+		if sel := m.selected(); sel != nil {
+			b.WriteString(m.styles.Prompt.Render(fmt.Sprintf("Delete %q forever? (y/n)", sel.Text)))
+		}
+	case confirmEmpty:
+		b.WriteString(m.styles.Prompt.Render(fmt.Sprintf("Delete all %d todos in the trash forever? (y/n)", len(m.todos))))
+	default:
+		if m.retentionDays > 0 {
+			b.WriteString(m.styles.Hint.Render(fmt.Sprintf("Todos are deleted forever %d days after they were trashed.", m.retentionDays)))
+		} else {
+			b.WriteString(m.styles.Hint.Render("Todos stay in the trash until it is emptied."))
+		}
+	}
+
+	return m.verticalCenter(b.String())
+}
+
+// verticalCenter centers the content vertically within the available height.
+func (m Model) verticalCenter(content string) string {
+	if m.height > 0 {
+		lines := strings.Count(content, "\n") + 1
+		topPad := (m.height - lines) / 2
+		if topPad > 0 {
+			content = strings.Repeat("\n", topPad) + content
+		}
+	}
+	return content
+}
@@ -0,0 +1,32 @@
+package trash
+
+import (
+	"github.com/antti/todo-calendar/internal/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles holds themed lipgloss styles for the trash overlay.
+type Styles struct {
+	Title        lipgloss.Style
+	ItemText     lipgloss.Style
+	ItemDate     lipgloss.Style
+	SelectedItem lipgloss.Style
+	SelectedDate lipgloss.Style
+	Hint         lipgloss.Style
+	Prompt       lipgloss.Style
+	Empty        lipgloss.Style
+}
+
+// NewStyles builds trash styles from the given theme.
+func NewStyles(t theme.Theme) Styles {
+	return Styles{
+		Title:        lipgloss.NewStyle().Bold(true).Foreground(t.AccentFg),
+		ItemText:     lipgloss.NewStyle().Foreground(t.NormalFg),
+		ItemDate:     lipgloss.NewStyle().Foreground(t.MutedFg),
+		SelectedItem: lipgloss.NewStyle().Bold(true).Foreground(t.AccentFg),
+		SelectedDate: lipgloss.NewStyle().Foreground(t.AccentFg),
+		Hint:         lipgloss.NewStyle().Foreground(t.MutedFg),
+		Prompt:       lipgloss.NewStyle().Foreground(t.HolidayFg),
+		Empty:        lipgloss.NewStyle().Foreground(t.MutedFg),
+	}
+}
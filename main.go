@@ -7,11 +7,11 @@ import (
 	"time"
 
 	"github.com/antti/todo-calendar/internal/app"
+	"github.com/antti/todo-calendar/internal/cli"
 	"github.com/antti/todo-calendar/internal/config"
 	"github.com/antti/todo-calendar/internal/google"
 	"github.com/antti/todo-calendar/internal/holidays"
 	"github.com/antti/todo-calendar/internal/recurring"
-	"github.com/antti/todo-calendar/internal/status"
 	"github.com/antti/todo-calendar/internal/store"
 	"github.com/antti/todo-calendar/internal/theme"
 	tea "github.com/charmbracelet/bubbletea"
@@ -25,12 +25,22 @@ func main() {
 	showStatus := flag.Bool("status", false, "Show today's pending todo count")
 	flag.BoolVar(showVersion, "v", false, "Show version")
 	flag.BoolVar(showStatus, "s", false, "Show today's pending todo count")
+	jsonOutput := flag.Bool("json", false, "Print --status as JSON")
+	outputFormat := flag.String("format", "plain", "Output format for --status: plain, json or tsv")
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: todo-calendar [flags]\n\nA terminal calendar with todo management.\n\nFlags:\n")
+		fmt.Fprintf(os.Stderr, "Usage: todo-calendar [flags]\n       todo-calendar <command> [args]\n\nA terminal calendar with todo management.\n\nFlags:\n")
 		fmt.Fprintf(os.Stderr, "  -s, --status   Show today's pending todo count\n")
+		fmt.Fprintf(os.Stderr, "      --json     Print --status as JSON\n")
+		fmt.Fprintf(os.Stderr, "      --format   Output format for --status: plain, json or tsv\n")
 		fmt.Fprintf(os.Stderr, "  -v, --version  Show version\n")
-		fmt.Fprintf(os.Stderr, "  -h, --help     Show this help\n")
+		fmt.Fprintf(os.Stderr, "  -h, --help     Show this help\n\n")
+		cli.WriteUsage(os.Stderr)
 	}
+
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	flag.Parse()
 
 	if *showVersion {
@@ -58,9 +68,14 @@ func main() {
 	defer s.Close()
 
 	if *showStatus {
-		today := time.Now().Format("2006-01-02")
-		todos := s.TodosForDateRange(today, today)
-		fmt.Print(status.FormatStatus(todos))
+		args := []string{"status", "--format", *outputFormat}
+		if *jsonOutput {
+			args = append(args, "--json")
+		}
+		if code := cli.Run(args, s, cfg, os.Stdout, os.Stderr); code != cli.ExitOK {
+			s.Close()
+			os.Exit(code)
+		}
 		return
 	}
 
@@ -70,7 +85,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	recurring.AutoCreate(s)
+	recurring.AutoCreate(s, provider.IsHoliday)
+	if cfg.TrashRetentionDays > 0 {
+		s.PurgeTrash(time.Now().AddDate(0, 0, -cfg.TrashRetentionDays))
+	}
+	if cfg.ArchiveAfterDays > 0 {
+		s.ArchiveCompleted(time.Now().AddDate(0, 0, -cfg.ArchiveAfterDays))
+	}
 
 	authState := google.CheckAuthState()
 
@@ -87,3 +108,28 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runCommand opens the store and runs a scriptable subcommand without
+// starting the TUI. It returns the process exit code.
+func runCommand(args []string) int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Config error: %v\n", err)
+		return cli.ExitError
+	}
+
+	dbPath, err := config.DBPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Database path error: %v\n", err)
+		return cli.ExitError
+	}
+
+	s, err := store.NewSQLiteStore(dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Store error: %v\n", err)
+		return cli.ExitError
+	}
+	defer s.Close()
+
+	return cli.Run(args, s, cfg, os.Stdout, os.Stderr)
+}